@@ -44,10 +44,20 @@ const (
 	ImageNotFoundReason = "ImageNotFound"
 	// ImageAmbiguousReason indicates that there are multiple images with the required properties.
 	ImageAmbiguousReason = "ImageAmbiguous"
+	// ImageSelectorInvalidReason indicates that the label selector of the image selector could not be rendered.
+	ImageSelectorInvalidReason = "ImageSelectorInvalid"
 	// ServerTypeNotFoundReason indicates that server type could not be found.
 	ServerTypeNotFoundReason = "ServerTypeNotFound"
 	// ServerCreateFailedReason indicates that server could not get created.
 	ServerCreateFailedReason = "ServerCreateFailedReason"
+	// ServerTypeUnavailableReason indicates that none of the server types is available in any of the locations.
+	ServerTypeUnavailableReason = "ServerTypeUnavailable"
+	// PrimaryIPNotAvailableReason indicates that a referenced primary IP cannot be assigned to the server.
+	PrimaryIPNotAvailableReason = "PrimaryIPNotAvailable"
+	// VolumeMountsNotPossibleReason indicates that the mounts of volumes could not be added to the bootstrap data.
+	VolumeMountsNotPossibleReason = "VolumeMountsNotPossible"
+	// BootstrapDataTooLargeReason indicates that the bootstrap data exceeds the size limit of user data, even after compression.
+	BootstrapDataTooLargeReason = "BootstrapDataTooLarge"
 )
 
 const (
@@ -59,6 +69,25 @@ const (
 	ServerStartingReason = "ServerStarting"
 	// ServerOffReason instance is off.
 	ServerOffReason = "ServerOff"
+	// ServerResizingReason indicates that the server is not available, as its type is changed in place.
+	ServerResizingReason = "ServerResizing"
+)
+
+const (
+	// ServerResizeSucceededCondition reports on the progress of an in-place resize of the server type.
+	ServerResizeSucceededCondition clusterv1.ConditionType = "ServerResizeSucceeded"
+	// ServerResizeDrainingNodeReason indicates that the node is cordoned and drained before the server is resized.
+	ServerResizeDrainingNodeReason = "DrainingNode"
+	// ServerResizePoweringOffReason indicates that the server is shut down before its type is changed.
+	ServerResizePoweringOffReason = "PoweringOff"
+	// ServerResizeChangingTypeReason indicates that the type of the server is being changed.
+	ServerResizeChangingTypeReason = "ChangingServerType"
+	// ServerResizePoweringOnReason indicates that the resized server is powered on and the node is not ready yet.
+	ServerResizePoweringOnReason = "PoweringOn"
+	// ServerResizeFailedReason indicates that the server could not be resized.
+	ServerResizeFailedReason = "ServerResizeFailed"
+	// ServerResizeAbortedReason indicates that the resize annotation was removed before the server was resized.
+	ServerResizeAbortedReason = "ServerResizeAborted"
 )
 
 const (
@@ -68,6 +97,19 @@ const (
 	LoadBalancerAttachFailedReason = "LoadBalancerAttachFailed"
 )
 
+const (
+	// VolumesReadyCondition reports on whether the volumes of the server are created and attached.
+	VolumesReadyCondition clusterv1.ConditionType = "VolumesReady"
+	// VolumeCreateFailedReason indicates that a volume could not be created.
+	VolumeCreateFailedReason = "VolumeCreateFailed"
+	// VolumeAttachFailedReason indicates that a volume could not be attached to the server.
+	VolumeAttachFailedReason = "VolumeAttachFailed"
+	// VolumeAttachedToOtherServerReason indicates that a volume is attached to another server.
+	VolumeAttachedToOtherServerReason = "VolumeAttachedToOtherServer"
+	// VolumeDeleteFailedReason indicates that a volume could not be deleted.
+	VolumeDeleteFailedReason = "VolumeDeleteFailed"
+)
+
 const (
 	// BootstrapReadyCondition  indicates that bootstrap is ready.
 	BootstrapReadyCondition clusterv1.ConditionType = "BootstrapReady"
@@ -82,6 +124,13 @@ const (
 	NetworkReconcileFailedReason = "NetworkReconcileFailed"
 )
 
+const (
+	// VSwitchReadyCondition reports on whether the vSwitch is connected to the network and to the bare metal servers.
+	VSwitchReadyCondition clusterv1.ConditionType = "VSwitchReady"
+	// VSwitchReconcileFailedReason indicates that reconciling the vSwitch failed.
+	VSwitchReconcileFailedReason = "VSwitchReconcileFailed"
+)
+
 const (
 	// PlacementGroupsSyncedCondition reports on whether the placement groups are successfully synced.
 	PlacementGroupsSyncedCondition clusterv1.ConditionType = "PlacementGroupsSynced"
@@ -89,6 +138,29 @@ const (
 	PlacementGroupsSyncFailedReason = "PlacementGroupsSyncFailed"
 )
 
+const (
+	// FirewallsSyncedCondition reports on whether the firewalls are successfully synced.
+	FirewallsSyncedCondition clusterv1.ConditionType = "FirewallsSynced"
+	// FirewallsSyncFailedReason indicates that syncing the firewalls failed.
+	FirewallsSyncFailedReason = "FirewallsSyncFailed"
+)
+
+const (
+	// ControlPlaneFloatingIPReadyCondition reports on whether the floating IP of the control plane is assigned to a healthy node.
+	ControlPlaneFloatingIPReadyCondition clusterv1.ConditionType = "ControlPlaneFloatingIPReady"
+	// ControlPlaneFloatingIPSyncFailedReason indicates that the floating IP could not be created or assigned.
+	ControlPlaneFloatingIPSyncFailedReason = "ControlPlaneFloatingIPSyncFailed"
+	// ControlPlaneFloatingIPNotAssignedReason indicates that there is no healthy control plane node to assign the floating IP to.
+	ControlPlaneFloatingIPNotAssignedReason = "ControlPlaneFloatingIPNotAssigned"
+)
+
+const (
+	// PrimaryIPPoolSyncedCondition reports on whether the pool of primary IPs is successfully synced.
+	PrimaryIPPoolSyncedCondition clusterv1.ConditionType = "PrimaryIPPoolSynced"
+	// PrimaryIPPoolSyncFailedReason indicates that syncing the pool of primary IPs failed.
+	PrimaryIPPoolSyncFailedReason = "PrimaryIPPoolSyncFailed"
+)
+
 const (
 	// HCloudTokenAvailableCondition reports on whether the HCloud Token is available.
 	HCloudTokenAvailableCondition clusterv1.ConditionType = "HCloudTokenAvailable"
@@ -112,6 +184,42 @@ const (
 	StorageDeviceNotFoundReason = "StorageDeviceNotFound"
 )
 
+const (
+	// RootDeviceHintsSelectedCondition reports on whether the root device hints have been selected by the RootDeviceHintsPolicy.
+	RootDeviceHintsSelectedCondition clusterv1.ConditionType = "RootDeviceHintsSelected"
+	// RootDeviceHintsSelectedByPolicyReason indicates that the root device hints have been selected by the RootDeviceHintsPolicy.
+	RootDeviceHintsSelectedByPolicyReason = "SelectedByPolicy"
+	// NoMatchingStorageDeviceReason indicates that no storage devices fulfill the RootDeviceHintsPolicy.
+	NoMatchingStorageDeviceReason = "NoMatchingStorageDevice"
+)
+
+const (
+	// BurnInPassedCondition reports on whether the hardware of the host has passed the burn-in.
+	BurnInPassedCondition clusterv1.ConditionType = "BurnInPassed"
+	// BurnInRunningReason indicates that the burn-in is still running.
+	BurnInRunningReason = "BurnInRunning"
+	// BurnInFailedReason indicates that at least one check of the burn-in has failed.
+	BurnInFailedReason = "BurnInFailed"
+)
+
+const (
+	// DiskHealthyCondition reports on whether the disks of a provisioned host are healthy.
+	DiskHealthyCondition clusterv1.ConditionType = "DiskHealthy"
+	// DiskUnhealthyReason indicates that SMART reports a broken disk.
+	DiskUnhealthyReason = "DiskUnhealthy"
+	// RaidDegradedReason indicates that a software RAID has lost a member.
+	RaidDegradedReason = "RaidDegraded"
+	// DiskHealthCheckFailedReason indicates that the disk health could not be checked.
+	DiskHealthCheckFailedReason = "DiskHealthCheckFailed"
+)
+
+const (
+	// SSHHostKeyVerifiedCondition reports on whether the host key of the last SSH connection matched the pinned host key.
+	SSHHostKeyVerifiedCondition clusterv1.ConditionType = "SSHHostKeyVerified"
+	// SSHHostKeyMismatchReason indicates that the host key of the server does not match the pinned host key.
+	SSHHostKeyMismatchReason = "SSHHostKeyMismatch"
+)
+
 const (
 	// TargetClusterReadyCondition reports on whether the kubeconfig in the target cluster is ready.
 	TargetClusterReadyCondition clusterv1.ConditionType = "TargetClusterReady"
@@ -156,6 +264,8 @@ const (
 	OSSSHSecretMissingReason = "OSSSHSecretMissing"
 	// RescueSSHSecretMissingReason indicates that secret with the rescue ssh key is missing.
 	RescueSSHSecretMissingReason = "RescueSSHSecretMissing"
+	// SSHJumpHostSecretMissingReason indicates that secret with the ssh key of the jump host is missing.
+	SSHJumpHostSecretMissingReason = "SSHJumpHostSecretMissing"
 )
 
 const (
@@ -171,6 +281,8 @@ const (
 	ImageSpecInvalidReason = "ImageSpecInvalid"
 	// ImageDownloadFailedReason indicates that downloading the machine image (http or OCI) failed.
 	ImageDownloadFailedReason = "ImageDownloadFailed"
+	// DiskImageChecksumMismatchReason indicates that the checksum of the downloaded disk image does not match.
+	DiskImageChecksumMismatchReason = "DiskImageChecksumMismatch"
 	// NoStorageDeviceFoundReason indicates that no suitable storage device could be found.
 	NoStorageDeviceFoundReason = "NoStorageDeviceFound"
 	// CloudInitNotInstalledReason indicates that cloud init is not installed.
@@ -181,12 +293,16 @@ const (
 	LinuxOnOtherDiskFoundReason = "LinuxOnOtherDiskFound"
 	// WipeDiskFailedReason indicates that erasing the disks before provisioning failed.
 	WipeDiskFailedReason = "WipeDiskFailed"
+	// SSHToOSFailedReason indicates that the operating system of an externally provisioned server can't be reached via ssh.
+	SSHToOSFailedReason = "SSHToOSFailed"
 	// SSHToRescueSystemFailedReason indicates that the rescue system can't be reached via ssh.
 	SSHToRescueSystemFailedReason = "SSHToRescueSystemFailed"
 	// RebootTimedOutReason indicates that the reboot timed out.
 	RebootTimedOutReason = "RebootTimedOut"
 	// CheckDiskFailedReason indicates that checking the health of the disk was not successful.
 	CheckDiskFailedReason = "CheckDiskFailed"
+	// VSwitchIPNotAssignedReason indicates that the server has no private IP in the vSwitch subnet yet.
+	VSwitchIPNotAssignedReason = "VSwitchIPNotAssigned"
 )
 
 const (
@@ -197,6 +313,16 @@ const (
 	SSHAfterInstallImageFailedReason = "SSHAfterInstallImageFailed"
 )
 
+const (
+	// ReprovisionSucceededCondition reports on the reprovisioning of the host of a HetznerBareMetalMachine that has
+	// been requested with the ReprovisionAnnotation.
+	ReprovisionSucceededCondition clusterv1.ConditionType = "ReprovisionSucceeded"
+	// ReprovisionPendingReason indicates that the reprovisioning waits until the host is provisioned.
+	ReprovisionPendingReason = "ReprovisionPending"
+	// ReprovisioningReason indicates that the host is being reprovisioned.
+	ReprovisioningReason = "Reprovisioning"
+)
+
 const (
 	// HostAssociateSucceededCondition indicates that a host has been associated.
 	HostAssociateSucceededCondition clusterv1.ConditionType = "HostAssociateSucceeded"
@@ -211,6 +337,13 @@ const (
 	DeletionInProgressReason = "DeletionInProgress"
 )
 
+const (
+	// InventorySyncedCondition reports on whether the HetznerBareMetalHosts of the inventory are in sync with Hetzner Robot.
+	InventorySyncedCondition clusterv1.ConditionType = "InventorySynced"
+	// InventorySyncFailedReason indicates that the synchronization with Hetzner Robot failed.
+	InventorySyncFailedReason = "InventorySyncFailed"
+)
+
 // deprecated conditions.
 
 const (
@@ -31,6 +31,11 @@ const (
 	// DeprecatedHCloudMachineFinalizer contains the old string.
 	// The controller will automatically update to the new string.
 	DeprecatedHCloudMachineFinalizer = "hcloudmachine.infrastructure.cluster.x-k8s.io"
+
+	// ResizeServerTypeAnnotation requests an in-place resize of the server to the server type given as value.
+	// The node gets drained and the server is powered off during the resize. The annotation is removed
+	// once the resize is done.
+	ResizeServerTypeAnnotation = "capi.syself.com/resize-server-type"
 )
 
 // HCloudMachineSpec defines the desired state of HCloudMachine.
@@ -43,10 +48,28 @@ type HCloudMachineSpec struct {
 	// +kubebuilder:validation:Enum=cpx11;cx21;cpx21;cx31;cpx31;cx41;cpx41;cx51;cpx51;ccx11;ccx12;ccx13;ccx21;ccx22;ccx23;ccx31;ccx32;ccx33;ccx41;ccx42;ccx43;ccx51;ccx52;ccx53;ccx62;ccx63;cax11;cax21;cax31;cax41;cx22;cx32;cx42;cx52
 	Type HCloudMachineType `json:"type"`
 
+	// FallbackTypes is an ordered list of server types that are tried if no server of the type defined in Type
+	// can be created, because Hetzner's Cloud API has no capacity left for it.
+	// +optional
+	FallbackTypes []HCloudMachineType `json:"fallbackTypes,omitempty"`
+
+	// FallbackRegions is an ordered list of locations that are tried if no server can be created in the location
+	// of the failure domain, because Hetzner's Cloud API has no capacity left for any of the server types.
+	// Only locations that are failure domains of the cluster are used. The fallback regions are ignored if the
+	// Machine specifies a failure domain. They cannot be combined with volumes, as volumes are bound to a location.
+	// +optional
+	FallbackRegions []Region `json:"fallbackRegions,omitempty"`
+
 	// ImageName is the reference to the Machine Image from which to create the machine instance.
 	// It can reference an image uploaded to Hetzner API in two ways: either directly as the name of an image or as the label of an image.
-	// +kubebuilder:validation:MinLength=1
-	ImageName string `json:"imageName"`
+	// Either ImageName or ImageSelector has to be specified.
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+
+	// ImageSelector selects the image by labels instead of by name. If several images match, the newest
+	// one is used by default. This way, new snapshots can be published without renaming old ones.
+	// +optional
+	ImageSelector *HCloudImageSelector `json:"imageSelector,omitempty"`
 
 	// SSHKeys define machine-specific SSH keys and override cluster-wide SSH keys.
 	// +optional
@@ -60,6 +83,47 @@ type HCloudMachineSpec struct {
 	// the primary IP address of the server. If both IPv4 and IPv6 are disabled, then the private network has to be enabled.
 	// +optional
 	PublicNetwork *PublicNetworkSpec `json:"publicNetwork,omitempty"`
+
+	// Volumes define HCloud volumes that are created together with the server and attached to it.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Volumes []HCloudVolumeSpec `json:"volumes,omitempty"`
+}
+
+// HCloudVolumeSpec defines a volume in Hetzner's Cloud API that is attached to the server of an HCloudMachine.
+type HCloudVolumeSpec struct {
+	// Name identifies the volume of the machine. The volume in HCloud API is called "<machine name>-<name>".
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Size is the size of the volume in GB.
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=10240
+	Size int `json:"size"`
+
+	// Format is the filesystem the volume is formatted with. If it is not set, the volume is not formatted.
+	// +kubebuilder:validation:Enum=ext4;xfs
+	// +optional
+	Format *string `json:"format,omitempty"`
+
+	// MountPath is the path where the volume is mounted on the server. It is added as mount to the
+	// cloud-init bootstrap data and requires a format. MountPath and Automount are mutually exclusive.
+	// +optional
+	MountPath *string `json:"mountPath,omitempty"`
+
+	// Automount lets Hetzner mount the volume at /mnt/HC_Volume_<volume ID> after it has been attached.
+	// +optional
+	// +kubebuilder:default=false
+	Automount bool `json:"automount"`
+
+	// DeleteOnMachineDelete defines whether the volume is deleted together with the HCloudMachine.
+	// If it is false, the volume is kept in HCloud API and has to be deleted manually.
+	// +optional
+	// +kubebuilder:default=true
+	DeleteOnMachineDelete bool `json:"deleteOnMachineDelete"`
 }
 
 // HCloudMachineStatus defines the observed state of HCloudMachine.
@@ -74,9 +138,18 @@ type HCloudMachineStatus struct {
 	// Region contains the name of the HCloud location the server is running.
 	Region Region `json:"region,omitempty"`
 
+	// ServerType is the current server type of the server. It differs from the type in the spec
+	// if one of the fallback types had to be used or if the server was resized in place.
+	// +optional
+	ServerType HCloudMachineType `json:"serverType,omitempty"`
+
 	// SSHKeys specifies the ssh keys that were used for provisioning the server.
 	SSHKeys []SSHKey `json:"sshKeys,omitempty"`
 
+	// Volumes contain the volumes that are attached to the server.
+	// +optional
+	Volumes []HCloudVolumeStatus `json:"volumes,omitempty"`
+
 	// InstanceState is the state of the server for this machine.
 	// +optional
 	InstanceState *hcloud.ServerStatus `json:"instanceState,omitempty"`
@@ -98,6 +171,19 @@ type HCloudMachineStatus struct {
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+// HCloudVolumeStatus defines the observed state of a volume of an HCloudMachine.
+type HCloudVolumeStatus struct {
+	// Name is the name of the volume as defined in the spec of the HCloudMachine.
+	Name string `json:"name"`
+
+	// ID is the ID of the volume in HCloud API.
+	ID int64 `json:"id"`
+
+	// LinuxDevice is the path of the device of the volume on the server.
+	// +optional
+	LinuxDevice string `json:"linuxDevice,omitempty"`
+}
+
 // HCloudMachine is the Schema for the hcloudmachines API.
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=hcloudmachines,scope=Namespaced,categories=cluster-api,shortName=hcma
@@ -17,11 +17,108 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"path/filepath"
 	"reflect"
+	"text/template"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+func validateHCloudMachineSpecCreate(spec HCloudMachineSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	volumeNames := make(map[string]struct{}, len(spec.Volumes))
+	for i, volume := range spec.Volumes {
+		path := field.NewPath("spec", "volumes").Index(i)
+
+		if _, found := volumeNames[volume.Name]; found {
+			allErrs = append(allErrs,
+				field.Duplicate(path.Child("name"), volume.Name),
+			)
+		}
+		volumeNames[volume.Name] = struct{}{}
+
+		if volume.MountPath == nil {
+			continue
+		}
+
+		if !filepath.IsAbs(*volume.MountPath) {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("mountPath"), *volume.MountPath, "mount path has to be absolute"),
+			)
+		}
+		if volume.Format == nil {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("mountPath"), *volume.MountPath, "mount path requires a format"),
+			)
+		}
+		if volume.Automount {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("automount"), volume.Automount, "automount and mount path are mutually exclusive"),
+			)
+		}
+	}
+
+	allErrs = append(allErrs, validateHCloudMachineImage(spec)...)
+	allErrs = append(allErrs, validateHCloudMachineFallbackRegions(spec)...)
+
+	if spec.PublicNetwork != nil {
+		path := field.NewPath("spec", "publicNetwork")
+		if spec.PublicNetwork.PrimaryIPv4 != nil && !spec.PublicNetwork.EnableIPv4 {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("primaryIPv4"), *spec.PublicNetwork.PrimaryIPv4, "primary IPv4 requires enableIPv4"),
+			)
+		}
+		if spec.PublicNetwork.PrimaryIPv6 != nil && !spec.PublicNetwork.EnableIPv6 {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("primaryIPv6"), *spec.PublicNetwork.PrimaryIPv6, "primary IPv6 requires enableIPv6"),
+			)
+		}
+		if spec.PublicNetwork.UsePrimaryIPPool && (spec.PublicNetwork.PrimaryIPv4 != nil || spec.PublicNetwork.PrimaryIPv6 != nil) {
+			allErrs = append(allErrs,
+				field.Invalid(path.Child("usePrimaryIPPool"), spec.PublicNetwork.UsePrimaryIPPool, "primary IP pool and referenced primary IPs are mutually exclusive"),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+func validateHCloudMachineImage(spec HCloudMachineSpec) field.ErrorList {
+	if spec.ImageSelector == nil {
+		if spec.ImageName == "" {
+			return field.ErrorList{
+				field.Required(field.NewPath("spec", "imageName"), "either imageName or imageSelector has to be specified"),
+			}
+		}
+		return nil
+	}
+
+	if spec.ImageName != "" {
+		return field.ErrorList{
+			field.Invalid(field.NewPath("spec", "imageSelector"), spec.ImageSelector, "imageName and imageSelector are mutually exclusive"),
+		}
+	}
+
+	if _, err := template.New("labelSelector").Parse(spec.ImageSelector.LabelSelector); err != nil {
+		return field.ErrorList{
+			field.Invalid(field.NewPath("spec", "imageSelector", "labelSelector"), spec.ImageSelector.LabelSelector, fmt.Sprintf("invalid template: %s", err)),
+		}
+	}
+	return nil
+}
+
+func validateHCloudMachineFallbackRegions(spec HCloudMachineSpec) field.ErrorList {
+	// volumes are created in the location of the failure domain before the server
+	if len(spec.FallbackRegions) > 0 && len(spec.Volumes) > 0 {
+		return field.ErrorList{
+			field.Forbidden(field.NewPath("spec", "fallbackRegions"), "fallback regions cannot be combined with volumes"),
+		}
+	}
+	return nil
+}
+
 func validateHCloudMachineSpec(oldSpec, newSpec HCloudMachineSpec) field.ErrorList {
 	var allErrs field.ErrorList
 	// Type is immutable
@@ -38,6 +135,13 @@ func validateHCloudMachineSpec(oldSpec, newSpec HCloudMachineSpec) field.ErrorLi
 		)
 	}
 
+	// ImageSelector is immutable
+	if !reflect.DeepEqual(oldSpec.ImageSelector, newSpec.ImageSelector) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "imageSelector"), newSpec.ImageSelector, "field is immutable"),
+		)
+	}
+
 	// SSHKeys is immutable
 	if !reflect.DeepEqual(oldSpec.SSHKeys, newSpec.SSHKeys) {
 		allErrs = append(allErrs,
@@ -52,5 +156,14 @@ func validateHCloudMachineSpec(oldSpec, newSpec HCloudMachineSpec) field.ErrorLi
 		)
 	}
 
+	// Volumes are immutable
+	if !reflect.DeepEqual(oldSpec.Volumes, newSpec.Volumes) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "volumes"), newSpec.Volumes, "field is immutable"),
+		)
+	}
+
+	allErrs = append(allErrs, validateHCloudMachineFallbackRegions(newSpec)...)
+
 	return allErrs
 }
@@ -105,6 +105,18 @@ func TestValidateHCloudMachineSpec(t *testing.T) {
 			},
 			want: field.Invalid(field.NewPath("spec", "placementGroupName"), "placement-group-2", "field is immutable"),
 		},
+		{
+			name: "Immutable Volumes",
+			args: args{
+				oldSpec: HCloudMachineSpec{
+					Volumes: []HCloudVolumeSpec{{Name: "etcd", Size: 10}},
+				},
+				newSpec: HCloudMachineSpec{
+					Volumes: []HCloudVolumeSpec{{Name: "etcd", Size: 20}},
+				},
+			},
+			want: field.Invalid(field.NewPath("spec", "volumes"), []HCloudVolumeSpec{{Name: "etcd", Size: 20}}, "field is immutable"),
+		},
 		{
 			name: "No Errors",
 			args: args{
@@ -146,6 +158,138 @@ func TestValidateHCloudMachineSpec(t *testing.T) {
 	}
 }
 
+func TestValidateHCloudMachineSpecCreate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec HCloudMachineSpec
+		want *field.Error
+	}{
+		{
+			name: "Duplicate volume name",
+			spec: HCloudMachineSpec{
+				ImageName: "ubuntu-24.04",
+				Volumes:   []HCloudVolumeSpec{{Name: "etcd", Size: 10}, {Name: "etcd", Size: 20}},
+			},
+			want: field.Duplicate(field.NewPath("spec", "volumes").Index(1).Child("name"), "etcd"),
+		},
+		{
+			name: "Relative mount path",
+			spec: HCloudMachineSpec{
+				ImageName: "ubuntu-24.04",
+				Volumes:   []HCloudVolumeSpec{{Name: "etcd", Size: 10, Format: createFormat("ext4"), MountPath: createMountPath("var/lib/etcd")}},
+			},
+			want: field.Invalid(field.NewPath("spec", "volumes").Index(0).Child("mountPath"), "var/lib/etcd", "mount path has to be absolute"),
+		},
+		{
+			name: "Mount path without format",
+			spec: HCloudMachineSpec{
+				ImageName: "ubuntu-24.04",
+				Volumes:   []HCloudVolumeSpec{{Name: "etcd", Size: 10, MountPath: createMountPath("/var/lib/etcd")}},
+			},
+			want: field.Invalid(field.NewPath("spec", "volumes").Index(0).Child("mountPath"), "/var/lib/etcd", "mount path requires a format"),
+		},
+		{
+			name: "Mount path with automount",
+			spec: HCloudMachineSpec{
+				ImageName: "ubuntu-24.04",
+				Volumes:   []HCloudVolumeSpec{{Name: "etcd", Size: 10, Format: createFormat("ext4"), MountPath: createMountPath("/var/lib/etcd"), Automount: true}},
+			},
+			want: field.Invalid(field.NewPath("spec", "volumes").Index(0).Child("automount"), true, "automount and mount path are mutually exclusive"),
+		},
+		{
+			name: "Fallback regions with volumes",
+			spec: HCloudMachineSpec{
+				ImageName:       "ubuntu-24.04",
+				FallbackRegions: []Region{"nbg1"},
+				Volumes:         []HCloudVolumeSpec{{Name: "data", Size: 10, Automount: true}},
+			},
+			want: field.Forbidden(field.NewPath("spec", "fallbackRegions"), "fallback regions cannot be combined with volumes"),
+		},
+		{
+			name: "Primary IPv4 without IPv4",
+			spec: HCloudMachineSpec{
+				ImageName:     "ubuntu-24.04",
+				PublicNetwork: &PublicNetworkSpec{EnableIPv6: true, PrimaryIPv4: createPrimaryIPName("api-ipv4")},
+			},
+			want: field.Invalid(field.NewPath("spec", "publicNetwork", "primaryIPv4"), "api-ipv4", "primary IPv4 requires enableIPv4"),
+		},
+		{
+			name: "Primary IP pool with referenced primary IP",
+			spec: HCloudMachineSpec{
+				ImageName:     "ubuntu-24.04",
+				PublicNetwork: &PublicNetworkSpec{EnableIPv4: true, PrimaryIPv4: createPrimaryIPName("api-ipv4"), UsePrimaryIPPool: true},
+			},
+			want: field.Invalid(field.NewPath("spec", "publicNetwork", "usePrimaryIPPool"), true, "primary IP pool and referenced primary IPs are mutually exclusive"),
+		},
+		{
+			name: "Neither image name nor image selector",
+			spec: HCloudMachineSpec{},
+			want: field.Required(field.NewPath("spec", "imageName"), "either imageName or imageSelector has to be specified"),
+		},
+		{
+			name: "Image name and image selector",
+			spec: HCloudMachineSpec{
+				ImageName:     "ubuntu-24.04",
+				ImageSelector: &HCloudImageSelector{LabelSelector: "os==ubuntu"},
+			},
+			want: field.Invalid(field.NewPath("spec", "imageSelector"), nil, "imageName and imageSelector are mutually exclusive"),
+		},
+		{
+			name: "Invalid template in image selector",
+			spec: HCloudMachineSpec{
+				ImageSelector: &HCloudImageSelector{LabelSelector: "k8s-version=={{ .KubernetesVersion"},
+			},
+			want: field.Invalid(field.NewPath("spec", "imageSelector", "labelSelector"), nil, "invalid template: template: labelSelector:1: unclosed action"),
+		},
+		{
+			name: "Valid image selector",
+			spec: HCloudMachineSpec{
+				ImageSelector: &HCloudImageSelector{LabelSelector: "os==ubuntu,k8s-version=={{ .KubernetesVersion }}", Policy: HCloudImageSelectionPolicyNewest},
+			},
+			want: nil,
+		},
+		{
+			name: "No Errors",
+			spec: HCloudMachineSpec{
+				ImageName: "ubuntu-24.04",
+				Volumes: []HCloudVolumeSpec{
+					{Name: "etcd", Size: 10, Format: createFormat("ext4"), MountPath: createMountPath("/var/lib/etcd")},
+					{Name: "data", Size: 10, Automount: true},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudMachineSpecCreate(tt.spec)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			if assert.Len(t, got, 1) {
+				assert.Equal(t, tt.want.Type, got[0].Type)
+				assert.Equal(t, tt.want.Field, got[0].Field)
+				assert.Equal(t, tt.want.Detail, got[0].Detail)
+			}
+		})
+	}
+}
+
+func createFormat(format string) *string {
+	return &format
+}
+
+func createMountPath(path string) *string {
+	return &path
+}
+
+func createPrimaryIPName(name string) *string {
+	return &name
+}
+
 func createPlacementGroupName(name string) *string {
 	return &name
 }
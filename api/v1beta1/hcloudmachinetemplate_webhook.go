@@ -47,8 +47,15 @@ type HCloudMachineTemplateWebhook struct{}
 var _ webhook.CustomValidator = &HCloudMachineTemplateWebhook{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (r *HCloudMachineTemplateWebhook) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
-	return nil, nil
+func (r *HCloudMachineTemplateWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+	hcloudMachineTemplate, ok := raw.(*HCloudMachineTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a HCloudMachineTemplate but got a %T", raw))
+	}
+
+	allErrs := validateHCloudMachineSpecCreate(hcloudMachineTemplate.Spec.Template.Spec)
+
+	return nil, aggregateObjErrors(hcloudMachineTemplate.GroupVersionKind().GroupKind(), hcloudMachineTemplate.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -51,6 +52,31 @@ const (
 	IgnoreCheckDiskAnnotation = "capi.syself.com/ignore-check-disk"
 )
 
+const (
+	// HardwareRAMGBLabel is the label for the RAM of the host in GB.
+	HardwareRAMGBLabel = "hardware.caph/ram-gb"
+	// HardwareCPUArchLabel is the label for the CPU architecture of the host.
+	HardwareCPUArchLabel = "hardware.caph/cpu-arch"
+	// HardwareCPUCoresLabel is the label for the number of CPU cores of the host.
+	HardwareCPUCoresLabel = "hardware.caph/cpu-cores"
+	// HardwareCPUThreadsLabel is the label for the number of CPU threads of the host.
+	HardwareCPUThreadsLabel = "hardware.caph/cpu-threads"
+	// HardwareDiskCountLabel is the label for the number of disks of the host.
+	HardwareDiskCountLabel = "hardware.caph/disk-count"
+	// HardwareNVMeCountLabel is the label for the number of NVMe disks of the host.
+	HardwareNVMeCountLabel = "hardware.caph/nvme-count"
+	// HardwareSSDCountLabel is the label for the number of non-rotational disks of the host, including NVMe disks.
+	HardwareSSDCountLabel = "hardware.caph/ssd-count"
+	// HardwareHDDCountLabel is the label for the number of rotational disks of the host.
+	HardwareHDDCountLabel = "hardware.caph/hdd-count"
+	// HardwareNICSpeedLabel is the label for the speed of the fastest network interface of the host in Mbps.
+	HardwareNICSpeedLabel = "hardware.caph/nic-speed"
+	// HardwareDatacenterLabel is the label for the Hetzner datacenter of the server, e.g. "FSN1-DC14".
+	HardwareDatacenterLabel = "hardware.caph/datacenter"
+	// HardwareProductLabel is the label for the Hetzner product of the server, e.g. "AX41-NVMe".
+	HardwareProductLabel = "hardware.caph/product"
+)
+
 // RootDeviceHints holds the hints for specifying the storage location
 // for the root filesystem for the image. Need to specify either WWN or raid
 // to provision the host machine successfully. It is important to find the correct root device.
@@ -102,6 +128,128 @@ type Raid struct {
 	WWN []string `json:"wwn,omitempty"`
 }
 
+// RootDeviceHintsStrategy defines how the root device hints are derived from the storage devices of a host.
+type RootDeviceHintsStrategy string
+
+const (
+	// RootDeviceHintsStrategySmallestDisk selects the smallest disk. It results in rootDeviceHints.wwn
+	// and is meant for machines without software RAID.
+	RootDeviceHintsStrategySmallestDisk RootDeviceHintsStrategy = "SmallestDisk"
+	// RootDeviceHintsStrategySmallestIdenticalPair selects the two smallest disks that have the same model and size.
+	// It results in rootDeviceHints.raid.wwn and is meant for machines with software RAID.
+	RootDeviceHintsStrategySmallestIdenticalPair RootDeviceHintsStrategy = "SmallestIdenticalPair"
+)
+
+// DiskType restricts the storage devices that are considered by a RootDeviceHintsPolicy.
+type DiskType string
+
+const (
+	// DiskTypeAny considers all disks.
+	DiskTypeAny DiskType = "Any"
+	// DiskTypeNVMe considers only NVMe disks.
+	DiskTypeNVMe DiskType = "NVMe"
+	// DiskTypeSSD considers only non-rotational disks, including NVMe disks.
+	DiskTypeSSD DiskType = "SSD"
+	// DiskTypeHDD considers only rotational disks.
+	DiskTypeHDD DiskType = "HDD"
+)
+
+// RootDeviceHintsPolicy defines how the controller selects the root device hints automatically
+// if they are not specified.
+type RootDeviceHintsPolicy struct {
+	// Strategy defines how the disks are selected. SmallestDisk fills rootDeviceHints.wwn,
+	// SmallestIdenticalPair fills rootDeviceHints.raid.wwn. It has to fit to the swraid setting of the
+	// HetznerBareMetalMachine that uses the host.
+	// +kubebuilder:validation:Enum=SmallestDisk;SmallestIdenticalPair
+	Strategy RootDeviceHintsStrategy `json:"strategy"`
+
+	// DiskType restricts the disks that are considered.
+	// +kubebuilder:validation:Enum=Any;NVMe;SSD;HDD
+	// +kubebuilder:default=Any
+	// +optional
+	DiskType DiskType `json:"diskType,omitempty"`
+}
+
+// BurnInSpec defines the checks of the burn-in. Checks with a zero value are disabled.
+type BurnInSpec struct {
+	// SMART checks the SMART health status of all disks.
+	// +optional
+	SMART bool `json:"smart,omitempty"`
+
+	// MemtesterMB is the amount of RAM in MB that is tested with one loop of memtester.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MemtesterMB int `json:"memtesterMB,omitempty"`
+
+	// MinDiskReadMBps is the minimum sequential read throughput of every disk in MB/s, measured with fio.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinDiskReadMBps int `json:"minDiskReadMBps,omitempty"`
+
+	// MinNICSpeedMbps is the minimum link speed in Mbps of the fastest network interface that is up.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinNICSpeedMbps int `json:"minNICSpeedMbps,omitempty"`
+}
+
+// DiskHealthCheckSpec defines the periodic check of the disks of a provisioned host.
+type DiskHealthCheckSpec struct {
+	// Interval is the time between two checks.
+	// +kubebuilder:default="1h"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// RemediateMachine marks the CAPI Machine of the host for remediation if a disk is unhealthy, so that a
+	// MachineHealthCheck replaces the Machine.
+	// +optional
+	RemediateMachine bool `json:"remediateMachine,omitempty"`
+}
+
+// ProvisioningLogsStatus references the secret with the logs of the last provisioning attempts.
+type ProvisioningLogsStatus struct {
+	// SecretName is the name of the secret in the namespace of the host. Its keys have the form
+	// "<attempt>-installimage.log" and "<attempt>-cloud-init-output.log".
+	SecretName string `json:"secretName"`
+
+	// LastAttempt is the number of the last provisioning attempt.
+	LastAttempt int `json:"lastAttempt"`
+}
+
+// BurnInStatus contains the results of the burn-in.
+type BurnInStatus struct {
+	// Passed shows whether all checks of the burn-in have passed.
+	Passed bool `json:"passed"`
+
+	// StartedAt is the time when the burn-in has been started.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is the time when the burn-in has been completed.
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Results contains the result of every check.
+	// +optional
+	Results []BurnInResult `json:"results,omitempty"`
+}
+
+// BurnInResult is the result of one check of the burn-in.
+type BurnInResult struct {
+	// Check is the name of the check, e.g. "smart".
+	Check string `json:"check"`
+
+	// Target is the device that has been checked, e.g. the WWN of a disk.
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// Passed shows whether the check has passed.
+	Passed bool `json:"passed"`
+
+	// Message contains details of the result.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // ErrorType indicates the class of problem that has caused the Host resource
 // to enter an error state.
 type ErrorType string
@@ -144,6 +292,8 @@ const (
 	ErrorMessageMissingRescueSSHSecret = "could not find RescueSSHSecret"
 	// ErrorMessageMissingOSSSHSecret specifies the error message when no OSSSH secret is found.
 	ErrorMessageMissingOSSSHSecret = "could not find OSSSHSecret"
+	// ErrorMessageMissingSSHJumpHostSecret specifies the error message when no secret for the SSH jump host is found.
+	ErrorMessageMissingSSHJumpHostSecret = "could not find SSHJumpHostSecret"
 	// ErrorMessageMissingOrInvalidSecretData specifies the error message when no data in secret is missing or invalid.
 	ErrorMessageMissingOrInvalidSecretData = "invalid or not specified information in secret"
 )
@@ -161,6 +311,12 @@ const (
 	// StateRegistering means we are getting hardware details.
 	StateRegistering ProvisioningState = "registering"
 
+	// StateBurnIn means we are validating the hardware in the rescue system.
+	StateBurnIn ProvisioningState = "burn-in"
+
+	// StateAdopting means we are verifying the access to an externally provisioned server and getting hardware details.
+	StateAdopting ProvisioningState = "adopting"
+
 	// StateImageInstalling means we install a new image.
 	StateImageInstalling ProvisioningState = "image-installing"
 
@@ -221,6 +377,42 @@ type HetznerBareMetalHostSpec struct {
 	// +optional
 	RootDeviceHints *RootDeviceHints `json:"rootDeviceHints,omitempty"`
 
+	// RootDeviceHintsPolicy is used to select the root device hints automatically after the hardware
+	// details have been gathered. It is only used if no RootDeviceHints are specified.
+	// +optional
+	RootDeviceHintsPolicy *RootDeviceHintsPolicy `json:"rootDeviceHintsPolicy,omitempty"`
+
+	// BurnIn defines checks that validate the hardware in the rescue system after registering and before the
+	// image gets installed. The burn-in runs until it has passed once. If a check fails, the host gets a permanent error.
+	// +optional
+	BurnIn *BurnInSpec `json:"burnIn,omitempty"`
+
+	// DiskHealthCheck periodically checks the SMART status of the root devices and whether a software RAID is
+	// degraded while the host is provisioned. The result is reported in the DiskHealthy condition.
+	// +optional
+	DiskHealthCheck *DiskHealthCheckSpec `json:"diskHealthCheck,omitempty"`
+
+	// ProvisioningLogsHistoryLimit is the number of provisioning attempts of which the redacted logs of
+	// installimage and cloud-init are kept in a secret of the host. The logs are not stored if it is 0.
+	// Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=5
+	// +optional
+	ProvisioningLogsHistoryLimit *int `json:"provisioningLogsHistoryLimit,omitempty"`
+
+	// SSHJumpHost is a bastion host through which the SSH connections to this host are made. It overrides
+	// the jump host of the HetznerCluster.
+	// +optional
+	SSHJumpHost *SSHJumpHost `json:"sshJumpHost,omitempty"`
+
+	// ExternallyProvisioned marks a server that already runs an operating system which should be adopted
+	// instead of being installed. When the host gets consumed, the controller verifies the SSH access to the
+	// operating system with the SSHSpec of the HetznerBareMetalMachine, gets the hardware details from the
+	// running system and moves the host directly to the state provisioned. The server is not rebooted.
+	// The controller resets this field when the host gets deprovisioned or reprovisioned.
+	// +optional
+	ExternallyProvisioned bool `json:"externallyProvisioned,omitempty"`
+
 	// ConsumerRef is a reference to the HetznerBareMetalMachine
 	// that is using this host. When it is not empty, the host is considered "in use".
 	// +optional
@@ -301,6 +493,31 @@ type ControllerGeneratedStatus struct {
 	// +optional
 	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
 
+	// BurnIn contains the results of the burn-in.
+	// +optional
+	BurnIn *BurnInStatus `json:"burnIn,omitempty"`
+
+	// LastDiskHealthCheck is the time of the last disk health check.
+	// +optional
+	LastDiskHealthCheck *metav1.Time `json:"lastDiskHealthCheck,omitempty"`
+
+	// ProvisioningLogs references the secret with the logs of the last provisioning attempts.
+	// +optional
+	ProvisioningLogs *ProvisioningLogsStatus `json:"provisioningLogs,omitempty"`
+
+	// RobotLinuxInstallStarted is the time when the Linux installation of the Robot API was started. It is
+	// only set while the install method "robot-linux" is in progress.
+	// +optional
+	RobotLinuxInstallStarted *metav1.Time `json:"robotLinuxInstallStarted,omitempty"`
+
+	// ReleasedAt is the time when the host was released by its last consumer.
+	// +optional
+	ReleasedAt *metav1.Time `json:"releasedAt,omitempty"`
+
+	// AdoptedHostname is the hostname of an externally provisioned server at the time it was adopted.
+	// +optional
+	AdoptedHostname string `json:"adoptedHostname,omitempty"`
+
 	// Rebooted shows whether the server is currently being rebooted.
 	Rebooted bool `json:"rebooted,omitempty"`
 
@@ -337,6 +554,12 @@ type SSHStatus struct {
 	OSKey *SSHKey `json:"osKey,omitempty"`
 	// RescueKey contains name and fingerprint of the in HetznerCluster spec specified SSH key.
 	RescueKey *SSHKey `json:"rescueKey,omitempty"`
+	// RescueHostKeyFingerprint is the SHA256 fingerprint of the host key of the rescue system. It gets pinned on the
+	// first connection to the rescue system and reset whenever the rescue system gets activated again.
+	RescueHostKeyFingerprint string `json:"rescueHostKeyFingerprint,omitempty"`
+	// OSHostKeyFingerprint is the SHA256 fingerprint of the host key of the installed operating system. It gets pinned
+	// on the first connection to the installed operating system and reset whenever the image gets installed again.
+	OSHostKeyFingerprint string `json:"osHostKeyFingerprint,omitempty"`
 }
 
 // SecretStatus contains the reference and version of the last secret that was used.
@@ -414,6 +637,26 @@ type Storage struct {
 	Rota bool `json:"rota,omitempty"`
 }
 
+// IsNVMe checks whether the storage device is an NVMe disk. NVMe disks have no SCSI address and their WWN
+// is an EUI-64 or NGUID identifier.
+func (s Storage) IsNVMe() bool {
+	return s.HCTL == "" && (strings.HasPrefix(s.WWN, "eui.") || strings.HasPrefix(s.WWN, "nvme."))
+}
+
+// MatchesDiskType checks whether the storage device is of the given disk type.
+func (s Storage) MatchesDiskType(diskType DiskType) bool {
+	switch diskType {
+	case DiskTypeNVMe:
+		return s.IsNVMe()
+	case DiskTypeSSD:
+		return !s.Rota
+	case DiskTypeHDD:
+		return s.Rota
+	default:
+		return true
+	}
+}
+
 // NIC describes one network interface on the host.
 type NIC struct {
 	// The name of the network interface, e.g. "en0"
@@ -521,6 +764,18 @@ func HashOfSecretData(data map[string][]byte) ([]byte, error) {
 	return hash.Sum(nil), nil
 }
 
+// GetSSHJumpHost returns the jump host for SSH connections to the host. The jump host of the host takes
+// precedence over the one of the HetznerCluster.
+func (host *HetznerBareMetalHost) GetSSHJumpHost(hetznerCluster *HetznerCluster) *SSHJumpHost {
+	if host.Spec.SSHJumpHost != nil {
+		return host.Spec.SSHJumpHost
+	}
+	if hetznerCluster == nil {
+		return nil
+	}
+	return hetznerCluster.Spec.SSHJumpHost
+}
+
 // HasSoftwareReboot returns a boolean indicating whether software reboot exists for server.
 func (host *HetznerBareMetalHost) HasSoftwareReboot() bool {
 	for _, rt := range host.Spec.Status.RebootTypes {
@@ -581,6 +836,12 @@ func (host *HetznerBareMetalHost) ClearError() {
 	host.Spec.Status.ErrorCount = 0
 }
 
+// HasReprovisionAnnotation returns true if the host should be reprovisioned.
+func (host *HetznerBareMetalHost) HasReprovisionAnnotation() bool {
+	_, ok := host.Annotations[ReprovisionAnnotation]
+	return ok
+}
+
 // HasRebootAnnotation checks for the existence of reboot annotations and returns true if at least one exists.
 func (host *HetznerBareMetalHost) HasRebootAnnotation() bool {
 	for annotation := range host.GetAnnotations() {
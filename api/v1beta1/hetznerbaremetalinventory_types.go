@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// InventoryLabel is set on all HetznerBareMetalHosts that have been created by a HetznerBareMetalInventory.
+	// Its value is the name of the inventory.
+	InventoryLabel = "infrastructure.cluster.x-k8s.io/hetzner-bare-metal-inventory"
+
+	// RobotServerCancelledAnnotation is set on HetznerBareMetalHosts whose server has been cancelled in Hetzner Robot.
+	// Its value is the date until which the server is paid, or "removed" if the server is not listed anymore.
+	RobotServerCancelledAnnotation = "capi.syself.com/robot-server-cancelled"
+
+	// RobotServerRemoved is the value of RobotServerCancelledAnnotation for servers that are not listed in Hetzner Robot anymore.
+	RobotServerRemoved = "removed"
+)
+
+// HetznerBareMetalInventorySpec defines the desired state of HetznerBareMetalInventory.
+type HetznerBareMetalInventorySpec struct {
+	// HetznerSecretRef is a reference to the secret with the credentials of the Hetzner Robot API.
+	// The secret has to be in the namespace of the HetznerBareMetalInventory.
+	HetznerSecret HetznerSecretRef `json:"hetznerSecretRef"`
+
+	// Filter selects the servers of Hetzner Robot for which HetznerBareMetalHosts are created.
+	// If it is empty, all servers are selected.
+	// +optional
+	Filter InventoryFilter `json:"filter,omitempty"`
+
+	// HostTemplate contains the metadata of the HetznerBareMetalHosts that are created.
+	// +optional
+	HostTemplate InventoryHostTemplate `json:"hostTemplate,omitempty"`
+
+	// SyncInterval is the interval in which the servers of Hetzner Robot are listed. It has to be positive.
+	// +kubebuilder:default="10m"
+	// +optional
+	SyncInterval metav1.Duration `json:"syncInterval,omitempty"`
+}
+
+// InventoryFilter selects servers of Hetzner Robot.
+type InventoryFilter struct {
+	// NamePattern is a regular expression that the name of the server in Hetzner Robot has to match.
+	// +optional
+	NamePattern string `json:"namePattern,omitempty"`
+
+	// Products are the products of the servers, e.g. "AX41-NVMe". If empty, servers of all products are selected.
+	// +optional
+	Products []string `json:"products,omitempty"`
+
+	// HostSelector selects the existing HetznerBareMetalHosts that belong to the inventory, in addition to the
+	// ones that have been created by it. Only hosts of the inventory are flagged if their server gets cancelled.
+	// +optional
+	HostSelector *metav1.LabelSelector `json:"hostSelector,omitempty"`
+}
+
+// InventoryHostTemplate contains the metadata of the HetznerBareMetalHosts that are created by the inventory.
+type InventoryHostTemplate struct {
+	// NamePrefix is the prefix of the names of the HetznerBareMetalHosts. The server ID is appended.
+	// +kubebuilder:default="bm-"
+	// +optional
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// Labels are set on the HetznerBareMetalHosts.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are set on the HetznerBareMetalHosts.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// MaintenanceMode creates the HetznerBareMetalHosts in maintenance mode, so that they are not used before
+	// they have been reviewed.
+	// +optional
+	MaintenanceMode bool `json:"maintenanceMode,omitempty"`
+}
+
+// HetznerBareMetalInventoryStatus defines the observed state of HetznerBareMetalInventory.
+type HetznerBareMetalInventoryStatus struct {
+	// Hosts is the number of HetznerBareMetalHosts that belong to the inventory.
+	// +optional
+	Hosts int `json:"hosts,omitempty"`
+
+	// CancelledServers are the IDs of the servers of the inventory that have been cancelled in Hetzner Robot.
+	// +optional
+	CancelledServers []int `json:"cancelledServers,omitempty"`
+
+	// LastSyncTime is the time of the last successful synchronization with Hetzner Robot.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Conditions define the current service state of the HetznerBareMetalInventory.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:path=hetznerbaremetalinventories,scope=Namespaced,categories=cluster-api,shortName=hbmi
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Hosts",type="integer",JSONPath=".status.hosts",description="Number of hosts of the inventory"
+// +kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime",description="Time of the last synchronization with Hetzner Robot"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+
+// HetznerBareMetalInventory is the Schema for the hetznerbaremetalinventories API. It creates
+// HetznerBareMetalHosts for the servers of a Hetzner Robot account.
+type HetznerBareMetalInventory struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec HetznerBareMetalInventorySpec `json:"spec,omitempty"`
+	// +optional
+	Status HetznerBareMetalInventoryStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the observations of the operational state of the HetznerBareMetalInventory resource.
+func (r *HetznerBareMetalInventory) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the HetznerBareMetalInventory to the predescribed clusterv1.Conditions.
+func (r *HetznerBareMetalInventory) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// HetznerBareMetalInventoryList contains a list of HetznerBareMetalInventory.
+type HetznerBareMetalInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HetznerBareMetalInventory `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &HetznerBareMetalInventory{}, &HetznerBareMetalInventoryList{})
+}
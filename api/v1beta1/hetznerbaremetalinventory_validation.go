@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func validateHetznerBareMetalInventorySpec(spec HetznerBareMetalInventorySpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if _, err := regexp.Compile(spec.Filter.NamePattern); err != nil {
+		allErrs = append(allErrs, field.Invalid(
+			field.NewPath("spec", "filter", "namePattern"), spec.Filter.NamePattern,
+			"invalid regular expression: "+err.Error(),
+		))
+	}
+
+	if spec.Filter.HostSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.Filter.HostSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				field.NewPath("spec", "filter", "hostSelector"), spec.Filter.HostSelector,
+				"invalid label selector: "+err.Error(),
+			))
+		}
+	}
+
+	if spec.SyncInterval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			field.NewPath("spec", "syncInterval"), spec.SyncInterval.Duration.String(),
+			"sync interval has to be positive",
+		))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateHetznerBareMetalInventorySpec(t *testing.T) {
+	syncInterval := metav1.Duration{Duration: 10 * time.Minute}
+
+	tests := []struct {
+		name string
+		spec HetznerBareMetalInventorySpec
+		want *field.Error
+	}{
+		{
+			name: "valid filter",
+			spec: HetznerBareMetalInventorySpec{
+				Filter: InventoryFilter{
+					NamePattern:  "^k8s-.*",
+					Products:     []string{"AX41-NVMe"},
+					HostSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "workers"}},
+				},
+				SyncInterval: syncInterval,
+			},
+		},
+		{
+			name: "empty filter",
+			spec: HetznerBareMetalInventorySpec{SyncInterval: syncInterval},
+		},
+		{
+			name: "invalid name pattern",
+			spec: HetznerBareMetalInventorySpec{
+				Filter:       InventoryFilter{NamePattern: "k8s-(.*"},
+				SyncInterval: syncInterval,
+			},
+			want: &field.Error{Type: field.ErrorTypeInvalid, Field: "spec.filter.namePattern"},
+		},
+		{
+			name: "invalid host selector",
+			spec: HetznerBareMetalInventorySpec{
+				Filter: InventoryFilter{
+					HostSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "pool", Operator: "Unknown"},
+					}},
+				},
+				SyncInterval: syncInterval,
+			},
+			want: &field.Error{Type: field.ErrorTypeInvalid, Field: "spec.filter.hostSelector"},
+		},
+		{
+			name: "zero sync interval",
+			spec: HetznerBareMetalInventorySpec{SyncInterval: metav1.Duration{}},
+			want: &field.Error{Type: field.ErrorTypeInvalid, Field: "spec.syncInterval"},
+		},
+		{
+			name: "negative sync interval",
+			spec: HetznerBareMetalInventorySpec{SyncInterval: metav1.Duration{Duration: -time.Minute}},
+			want: &field.Error{Type: field.ErrorTypeInvalid, Field: "spec.syncInterval"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHetznerBareMetalInventorySpec(tt.spec)
+
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+
+			if assert.Len(t, got, 1) {
+				assert.Equal(t, tt.want.Type, got[0].Type)
+				assert.Equal(t, tt.want.Field, got[0].Field)
+			}
+		})
+	}
+}
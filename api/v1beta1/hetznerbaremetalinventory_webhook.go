@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager initializes webhook manager for HetznerBareMetalInventory.
+func (r *HetznerBareMetalInventory) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta1-hetznerbaremetalinventory,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalinventories,verbs=create;update,versions=v1beta1,name=mutation.hetznerbaremetalinventory.infrastructure.cluster.x-k8s.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Defaulter = &HetznerBareMetalInventory{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+func (r *HetznerBareMetalInventory) Default() {
+}
+
+//+kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-hetznerbaremetalinventory,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalinventories,verbs=create;update,versions=v1beta1,name=validation.hetznerbaremetalinventory.infrastructure.cluster.x-k8s.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Validator = &HetznerBareMetalInventory{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *HetznerBareMetalInventory) ValidateCreate() (admission.Warnings, error) {
+	allErrs := validateHetznerBareMetalInventorySpec(r.Spec)
+
+	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *HetznerBareMetalInventory) ValidateUpdate(runtime.Object) (admission.Warnings, error) {
+	allErrs := validateHetznerBareMetalInventorySpec(r.Spec)
+
+	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *HetznerBareMetalInventory) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
@@ -39,6 +39,11 @@ const (
 
 	// BareMetalHostNamePrefix is a prefix for all hostNames of bare metal servers.
 	BareMetalHostNamePrefix = "bm-"
+
+	// ReprovisionAnnotation requests to install the image of a HetznerBareMetalMachine again on its host. The host
+	// and the providerID are kept. The annotation is moved from the HetznerBareMetalMachine to the host once the
+	// host is provisioned, and removed from the host when the reprovisioning starts.
+	ReprovisionAnnotation = "capi.syself.com/reprovision"
 )
 
 var errUnknownSuffix = errors.New("unknown suffix")
@@ -96,6 +101,71 @@ type HostSelector struct {
 	// MatchExpressions defines the label match expressions that must be true on a chosen BareMetalHost.
 	// +optional
 	MatchExpressions []HostSelectorRequirement `json:"matchExpressions,omitempty"`
+
+	// HardwareRequirements defines the hardware that a chosen BareMetalHost needs to have. It is checked against
+	// the hardware details of the host. Hosts whose hardware details are not known yet are only chosen if no
+	// other host fulfills the requirements. If they turn out to not fulfill them, the machine fails.
+	// +optional
+	HardwareRequirements *HardwareRequirements `json:"hardwareRequirements,omitempty"`
+
+	// Policy defines how a BareMetalHost is chosen if multiple hosts fit. Random chooses any of them,
+	// SmallestSufficient chooses the host with the least resources, and LeastRecentlyUsed chooses the host
+	// that has been released the longest time ago.
+	// +kubebuilder:validation:Enum=Random;SmallestSufficient;LeastRecentlyUsed
+	// +optional
+	Policy HostSelectionPolicy `json:"policy,omitempty"`
+}
+
+// HostSelectionPolicy defines how a BareMetalHost is chosen among all hosts that fit.
+type HostSelectionPolicy string
+
+const (
+	// HostSelectionPolicyRandom chooses a random host.
+	HostSelectionPolicyRandom HostSelectionPolicy = "Random"
+	// HostSelectionPolicySmallestSufficient chooses the host with the least RAM, CPU threads and storage.
+	HostSelectionPolicySmallestSufficient HostSelectionPolicy = "SmallestSufficient"
+	// HostSelectionPolicyLeastRecentlyUsed chooses the host that has not been used for the longest time.
+	HostSelectionPolicyLeastRecentlyUsed HostSelectionPolicy = "LeastRecentlyUsed"
+)
+
+// HardwareRequirements defines the minimum hardware of a BareMetalHost.
+type HardwareRequirements struct {
+	// MinRAMGB is the minimum RAM in GB.
+	// +optional
+	MinRAMGB int `json:"minRAMGB,omitempty"`
+
+	// MinCPUCores is the minimum number of CPU cores.
+	// +optional
+	MinCPUCores int `json:"minCPUCores,omitempty"`
+
+	// MinCPUThreads is the minimum number of CPU threads.
+	// +optional
+	MinCPUThreads int `json:"minCPUThreads,omitempty"`
+
+	// CPUArch is the required CPU architecture, e.g. "x86_64".
+	// +optional
+	CPUArch string `json:"cpuArch,omitempty"`
+
+	// CPUModelRegex is a regular expression that the CPU model has to match.
+	// +optional
+	CPUModelRegex string `json:"cpuModelRegex,omitempty"`
+
+	// MinDiskCount is the minimum number of disks of type DiskType with at least MinDiskSizeGB.
+	// +optional
+	MinDiskCount int `json:"minDiskCount,omitempty"`
+
+	// MinDiskSizeGB is the minimum size of the disks that are counted for MinDiskCount.
+	// +optional
+	MinDiskSizeGB int `json:"minDiskSizeGB,omitempty"`
+
+	// DiskType is the type of the disks that are counted for MinDiskCount.
+	// +kubebuilder:validation:Enum=Any;NVMe;SSD;HDD
+	// +optional
+	DiskType DiskType `json:"diskType,omitempty"`
+
+	// MinNICSpeedMbps is the minimum speed of the fastest network interface in Mbps.
+	// +optional
+	MinNICSpeedMbps int `json:"minNICSpeedMbps,omitempty"`
 }
 
 // HostSelectorRequirement defines a requirement used for MatchExpressions to select host machines.
@@ -148,17 +218,65 @@ type SSHSecretKeyRef struct {
 	PrivateKey string `json:"privateKey"`
 }
 
+// InstallMethod defines how the operating system of a bare metal server gets installed.
+type InstallMethod string
+
+const (
+	// InstallMethodInstallImage installs the image with installimage in the rescue system.
+	InstallMethodInstallImage InstallMethod = "installimage"
+	// InstallMethodRobotLinux installs a stock distribution of Hetzner with the Linux installation of the Robot API.
+	InstallMethodRobotLinux InstallMethod = "robot-linux"
+	// InstallMethodDiskImage writes a raw or qcow2 disk image onto the root device from the rescue system.
+	InstallMethodDiskImage InstallMethod = "disk-image"
+)
+
+// DiskImageFormat defines the format of a disk image.
+type DiskImageFormat string
+
+const (
+	// DiskImageFormatRaw defines a raw disk image. It might be compressed.
+	DiskImageFormatRaw DiskImageFormat = "raw"
+	// DiskImageFormatQcow2 defines a disk image in the qcow2 format of QEMU.
+	DiskImageFormatQcow2 DiskImageFormat = "qcow2"
+)
+
+// diskImageCompressions are the suffixes of compressed raw disk images.
+var diskImageCompressions = []string{"gz", "xz", "bz2", "zst"}
+
 // InstallImage defines the configuration for InstallImage.
 type InstallImage struct {
+	// Method defines how the operating system gets installed. With "installimage", the image is installed
+	// by installimage in the rescue system. With "robot-linux", the distribution of RobotLinux is installed
+	// by the Linux installation of the Robot API. In that case, the settings for installimage are ignored,
+	// except for PostInstallScript, which runs in the installed system. With "disk-image", DiskImage is
+	// written onto the root device from the rescue system. The partitioning of installimage is not used,
+	// and PostInstallScript runs in the rescue system after the image has been written.
+	// +kubebuilder:validation:Enum=installimage;robot-linux;disk-image
+	// +kubebuilder:default=installimage
+	// +optional
+	Method InstallMethod `json:"method,omitempty"`
+
+	// RobotLinux defines the distribution that is installed with the install method "robot-linux".
+	// +optional
+	RobotLinux *RobotLinuxInstall `json:"robotLinux,omitempty"`
+
+	// DiskImage defines the disk image that is written with the install method "disk-image".
+	// +optional
+	DiskImage *DiskImage `json:"diskImage,omitempty"`
+
 	// Image is the image to be provisioned. It defines the image for baremetal machine.
-	Image Image `json:"image"`
+	// Required for the install method "installimage".
+	// +optional
+	Image Image `json:"image,omitempty"`
 
 	// PostInstallScript (Bash) is used for configuring commands that should be executed after installimage.
 	// It is passed along with the installimage command.
 	PostInstallScript string `json:"postInstallScript,omitempty"`
 
 	// Partitions define the additional Partitions to be created in installimage.
-	Partitions []Partition `json:"partitions"`
+	// Required for the install method "installimage".
+	// +optional
+	Partitions []Partition `json:"partitions,omitempty"`
 
 	// LVMDefinitions defines the logical volume definitions to be created.
 	// +optional
@@ -182,6 +300,97 @@ type InstallImage struct {
 	SwraidLevel int `json:"swraidLevel,omitempty"`
 }
 
+// UsesRobotLinux returns true if the operating system is installed with the Linux installation of the Robot API.
+func (installImage *InstallImage) UsesRobotLinux() bool {
+	return installImage.Method == InstallMethodRobotLinux
+}
+
+// UsesDiskImage returns true if a disk image is written onto the root device instead of running installimage.
+func (installImage *InstallImage) UsesDiskImage() bool {
+	return installImage.Method == InstallMethodDiskImage
+}
+
+// RobotLinuxInstall defines a distribution that is installed with the Linux installation of the Robot API.
+type RobotLinuxInstall struct {
+	// Dist is the name of the distribution as offered by the Robot API, e.g. "Ubuntu 24.04 LTS base".
+	// +kubebuilder:validation:MinLength=1
+	Dist string `json:"dist"`
+
+	// Lang is the language of the distribution.
+	// +kubebuilder:default=en
+	// +optional
+	Lang string `json:"lang,omitempty"`
+}
+
+// DiskImage defines a disk image that is written onto the root device as it is.
+type DiskImage struct {
+	// URL is the remote URL of the disk image. Raw images can be compressed with gzip, xz, bzip2 or zstd,
+	// which is detected by the suffix of the URL (.gz, .xz, .bz2, .zst).
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Format is the format of the disk image. If not set, images with the suffix .qcow2 are qcow2 images.
+	// All others are raw images.
+	// +kubebuilder:validation:Enum=raw;qcow2
+	// +optional
+	Format DiskImageFormat `json:"format,omitempty"`
+
+	// Checksum is the checksum of the downloaded file in the format "<algorithm>:<hex digest>". The
+	// algorithms sha256 and sha512 are supported. The image is not booted if the checksum does not match.
+	// +kubebuilder:validation:Pattern=`^(sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128})$`
+	Checksum string `json:"checksum"`
+
+	// SkipPartitionGrowth disables growing the last partition of the image and its filesystem to the size
+	// of the root device. This is needed for images that create their partitions on the first boot.
+	// +optional
+	SkipPartitionGrowth bool `json:"skipPartitionGrowth,omitempty"`
+}
+
+// GetFormat returns the format of the disk image. If no format is specified, it is detected by the suffix
+// of the URL.
+func (diskImage DiskImage) GetFormat() DiskImageFormat {
+	if diskImage.Format != "" {
+		return diskImage.Format
+	}
+	if strings.HasSuffix(diskImageURLPath(diskImage.URL), "."+string(DiskImageFormatQcow2)) {
+		return DiskImageFormatQcow2
+	}
+	return DiskImageFormatRaw
+}
+
+// GetCompression returns the compression of the disk image as detected by the suffix of the URL. It returns
+// an empty string if the image is not compressed.
+func (diskImage DiskImage) GetCompression() string {
+	urlPath := diskImageURLPath(diskImage.URL)
+	for _, compression := range diskImageCompressions {
+		if strings.HasSuffix(urlPath, "."+compression) {
+			return compression
+		}
+	}
+	return ""
+}
+
+// String returns the URL of the disk image without credentials and query parameters, so that it can be shown in
+// events.
+func (diskImage DiskImage) String() string {
+	u, err := url.Parse(diskImage.URL)
+	if err != nil {
+		return err.Error()
+	}
+	u.RawQuery = ""
+	return u.Redacted()
+}
+
+// diskImageURLPath returns the path of the URL, so that query parameters like the signature of a
+// pre-signed URL are not taken into account for the detection of the suffix.
+func diskImageURLPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Path
+}
+
 // Image defines the properties for the autosetup config.
 type Image struct {
 	// URL defines the remote URL for downloading a tar, tar.gz, tar.bz, tar.bz2, tar.xz, tgz, tbz, txz image.
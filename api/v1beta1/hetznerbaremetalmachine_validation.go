@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/labels"
@@ -29,13 +30,43 @@ import (
 func validateHetznerBareMetalMachineSpecCreate(spec HetznerBareMetalMachineSpec) field.ErrorList {
 	var allErrs field.ErrorList
 
-	if (spec.InstallImage.Image.Name == "" || spec.InstallImage.Image.URL == "") &&
-		spec.InstallImage.Image.Path == "" {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "installImage", "image"), spec.InstallImage.Image,
-				"have to specify either image name and url or path"),
-		)
+	if spec.InstallImage.UsesRobotLinux() {
+		if spec.InstallImage.RobotLinux == nil || spec.InstallImage.RobotLinux.Dist == "" {
+			allErrs = append(allErrs,
+				field.Required(field.NewPath("spec", "installImage", "robotLinux", "dist"),
+					"distribution is required for install method "+string(InstallMethodRobotLinux)),
+			)
+		}
+		if spec.SSHSpec.PortAfterInstallImage != 0 && spec.SSHSpec.PortAfterInstallImage != 22 {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "sshSpec", "portAfterInstallImage"), spec.SSHSpec.PortAfterInstallImage,
+					"has to be 22 for install method "+string(InstallMethodRobotLinux)),
+			)
+		}
+	} else if spec.InstallImage.UsesDiskImage() {
+		diskImage := spec.InstallImage.DiskImage
+		switch {
+		case diskImage == nil || diskImage.URL == "":
+			allErrs = append(allErrs,
+				field.Required(field.NewPath("spec", "installImage", "diskImage", "url"),
+					"url is required for install method "+string(InstallMethodDiskImage)),
+			)
+		case diskImage.GetFormat() == DiskImageFormatQcow2 && diskImage.GetCompression() != "":
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "installImage", "diskImage", "url"), diskImage.URL,
+					"compressed qcow2 images are not supported"),
+			)
+		}
+	} else {
+		if (spec.InstallImage.Image.Name == "" || spec.InstallImage.Image.URL == "") &&
+			spec.InstallImage.Image.Path == "" {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "installImage", "image"), spec.InstallImage.Image,
+					"have to specify either image name and url or path"),
+			)
+		}
 	}
+	allErrs = append(allErrs, validateInstallImagePartitions(spec.InstallImage, field.NewPath("spec", "installImage"))...)
 
 	if spec.InstallImage.Image.URL != "" {
 		if _, err := GetImageSuffix(spec.InstallImage.Image.URL); err != nil {
@@ -65,9 +96,29 @@ func validateHetznerBareMetalMachineSpecCreate(spec HetznerBareMetalMachineSpec)
 		}
 	}
 
+	if req := spec.HostSelector.HardwareRequirements; req != nil && req.CPUModelRegex != "" {
+		if _, err := regexp.Compile(req.CPUModelRegex); err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				field.NewPath("spec", "hostSelector", "hardwareRequirements", "cpuModelRegex"), req.CPUModelRegex,
+				fmt.Sprintf("invalid regular expression: %s", err.Error()),
+			))
+		}
+	}
+
 	return allErrs
 }
 
+// validateInstallImagePartitions checks that partitions are given for the install method installimage. Unlike the
+// other checks of the spec, it is run for HetznerBareMetalMachineTemplates as well.
+func validateInstallImagePartitions(installImage InstallImage, fldPath *field.Path) field.ErrorList {
+	if installImage.UsesRobotLinux() || installImage.UsesDiskImage() || len(installImage.Partitions) > 0 {
+		return nil
+	}
+	return field.ErrorList{
+		field.Required(fldPath.Child("partitions"), "partitions are required for install method "+string(InstallMethodInstallImage)),
+	}
+}
+
 func validateHetznerBareMetalMachineSpecUpdate(oldSpec, newSpec HetznerBareMetalMachineSpec) field.ErrorList {
 	var allErrs field.ErrorList
 	if !reflect.DeepEqual(newSpec.InstallImage, oldSpec.InstallImage) {
@@ -42,6 +42,9 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 							Name: "ubuntu-20.04",
 							URL:  "https://example.com/ubuntu-20.04.tar.gz",
 						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 				},
 			},
@@ -55,6 +58,9 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 						Image: Image{
 							Path: "path/to/image.tar.gz",
 						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 				},
 			},
@@ -66,6 +72,9 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 				spec: HetznerBareMetalMachineSpec{
 					InstallImage: InstallImage{
 						Image: Image{},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 				},
 			},
@@ -80,11 +89,92 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 							Name: "ubuntu-20.04",
 							URL:  "https://example.com/ubuntu-20.04.invalid",
 						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 				},
 			},
 			want: field.Invalid(field.NewPath("spec", "installImage", "image", "url"), "https://example.com/ubuntu-20.04.invalid", "unknown image type in URL"),
 		},
+		{
+			name: "Invalid Image - Missing Partitions",
+			args: args{
+				spec: HetznerBareMetalMachineSpec{
+					InstallImage: InstallImage{
+						Image: Image{
+							Name: "ubuntu-20.04",
+							URL:  "https://example.com/ubuntu-20.04.tar.gz",
+						},
+					},
+				},
+			},
+			want: field.Required(field.NewPath("spec", "installImage", "partitions"), "partitions are required for install method installimage"),
+		},
+		{
+			name: "Valid Robot Linux",
+			args: args{
+				spec: HetznerBareMetalMachineSpec{
+					InstallImage: InstallImage{
+						Method:     InstallMethodRobotLinux,
+						RobotLinux: &RobotLinuxInstall{Dist: "Ubuntu 24.04 LTS base"},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "Invalid Robot Linux - Missing Dist",
+			args: args{
+				spec: HetznerBareMetalMachineSpec{
+					InstallImage: InstallImage{
+						Method: InstallMethodRobotLinux,
+					},
+				},
+			},
+			want: field.Required(field.NewPath("spec", "installImage", "robotLinux", "dist"), "distribution is required for install method robot-linux"),
+		},
+		{
+			name: "Valid Disk Image",
+			args: args{
+				spec: HetznerBareMetalMachineSpec{
+					InstallImage: InstallImage{
+						Method: InstallMethodDiskImage,
+						DiskImage: &DiskImage{
+							URL:      "https://example.com/flatcar_production_image.raw.xz",
+							Checksum: "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "Invalid Disk Image - Missing URL",
+			args: args{
+				spec: HetznerBareMetalMachineSpec{
+					InstallImage: InstallImage{
+						Method: InstallMethodDiskImage,
+					},
+				},
+			},
+			want: field.Required(field.NewPath("spec", "installImage", "diskImage", "url"), "url is required for install method disk-image"),
+		},
+		{
+			name: "Invalid Disk Image - Compressed qcow2",
+			args: args{
+				spec: HetznerBareMetalMachineSpec{
+					InstallImage: InstallImage{
+						Method: InstallMethodDiskImage,
+						DiskImage: &DiskImage{
+							URL:    "https://example.com/golden-image.qcow2.gz",
+							Format: DiskImageFormatQcow2,
+						},
+					},
+				},
+			},
+			want: field.Invalid(field.NewPath("spec", "installImage", "diskImage", "url"), "https://example.com/golden-image.qcow2.gz", "compressed qcow2 images are not supported"),
+		},
 		{
 			name: "Valid HostSelector MatchLabels",
 			args: args{
@@ -94,6 +184,9 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 							Name: "ubuntu-20.04",
 							URL:  "https://example.com/ubuntu-20.04.tar.gz",
 						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 					HostSelector: HostSelector{
 						MatchLabels: map[string]string{
@@ -113,6 +206,9 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 							Name: "ubuntu-20.04",
 							URL:  "https://example.com/ubuntu-20.04.tar.gz",
 						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 					HostSelector: HostSelector{
 						MatchExpressions: []HostSelectorRequirement{
@@ -136,6 +232,9 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 							Name: "ubuntu-20.04",
 							URL:  "https://example.com/ubuntu-20.04.tar.gz",
 						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 					HostSelector: HostSelector{
 						MatchExpressions: []HostSelectorRequirement{
@@ -169,6 +268,9 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 							Name: "ubuntu-20.04",
 							URL:  "https://example.com/ubuntu-20.04.tar.gz",
 						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
 					},
 					HostSelector: HostSelector{
 						MatchExpressions: []HostSelectorRequirement{
@@ -193,6 +295,32 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 				`invalid match expression: key: Invalid value: "": name part must be non-empty; name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`,
 			),
 		},
+		{
+			name: "Invalid HostSelector HardwareRequirements - Invalid CPU model regex",
+			args: args{
+				spec: HetznerBareMetalMachineSpec{
+					InstallImage: InstallImage{
+						Image: Image{
+							Name: "ubuntu-20.04",
+							URL:  "https://example.com/ubuntu-20.04.tar.gz",
+						},
+						Partitions: []Partition{
+							{Mount: "/", FileSystem: "ext4", Size: "all"},
+						},
+					},
+					HostSelector: HostSelector{
+						HardwareRequirements: &HardwareRequirements{
+							CPUModelRegex: "AMD Ryzen(",
+						},
+					},
+				},
+			},
+			want: field.Invalid(
+				field.NewPath("spec", "hostSelector", "hardwareRequirements", "cpuModelRegex"),
+				"AMD Ryzen(",
+				"invalid regular expression: error parsing regexp: missing closing ): `AMD Ryzen(`",
+			),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -216,6 +344,23 @@ func TestValidateHetznerBareMetalMachineSpecCreate(t *testing.T) {
 	}
 }
 
+func TestValidateInstallImagePartitions(t *testing.T) {
+	fldPath := field.NewPath("spec", "template", "spec", "installImage")
+	partitions := []Partition{{Mount: "/", FileSystem: "ext4", Size: "all"}}
+
+	assert.Empty(t, validateInstallImagePartitions(InstallImage{Partitions: partitions}, fldPath))
+	assert.Empty(t, validateInstallImagePartitions(InstallImage{Method: InstallMethodRobotLinux}, fldPath))
+	assert.Empty(t, validateInstallImagePartitions(InstallImage{Method: InstallMethodDiskImage}, fldPath))
+
+	for _, method := range []InstallMethod{"", InstallMethodInstallImage} {
+		got := validateInstallImagePartitions(InstallImage{Method: method}, fldPath)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, field.ErrorTypeRequired, got[0].Type)
+			assert.Equal(t, "spec.template.spec.installImage.partitions", got[0].Field)
+		}
+	}
+}
+
 func TestValidateHetznerBareMetalMachineSpecUpdate(t *testing.T) {
 	type args struct {
 		oldSpec HetznerBareMetalMachineSpec
@@ -47,7 +47,7 @@ type HetznerBareMetalMachineTemplateWebhook struct{}
 var _ webhook.CustomValidator = &HetznerBareMetalMachineTemplateWebhook{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (r *HetznerBareMetalMachineTemplateWebhook) ValidateCreate(_ context.Context, raw runtime.Object) (admission.Warnings, error) {
+func (r *HetznerBareMetalMachineTemplateWebhook) ValidateCreate(ctx context.Context, raw runtime.Object) (admission.Warnings, error) {
 	hbmmt, ok := raw.(*HetznerBareMetalMachineTemplate)
 	if !ok {
 		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a HetznerBareMetalMachineTemplate but got a %T", raw))
@@ -59,7 +59,19 @@ func (r *HetznerBareMetalMachineTemplateWebhook) ValidateCreate(_ context.Contex
 
 	// TODO: Cannot validate it because ClusterClass applies empty template objects
 	// allErrs := validateHetznerBareMetalMachineSpecCreate(hbmmt.Spec.Template.Spec)
-	return nil, nil
+
+	// The empty template objects of ClusterClass are only applied in dry-run, so the partitions can be validated.
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
+	}
+	if topology.ShouldSkipImmutabilityChecks(req, hbmmt) {
+		return nil, nil
+	}
+	allErrs := validateInstallImagePartitions(hbmmt.Spec.Template.Spec.InstallImage,
+		field.NewPath("spec", "template", "spec", "installImage"))
+
+	return nil, aggregateObjErrors(hbmmt.GroupVersionKind().GroupKind(), hbmmt.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
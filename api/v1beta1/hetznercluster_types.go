@@ -51,6 +51,12 @@ type HetznerClusterSpec struct {
 
 	// SSHKeys are cluster wide. Valid values are a valid SSH key name.
 	SSHKeys HetznerSSHKeys `json:"sshKeys"`
+
+	// SSHJumpHost is a bastion host through which the SSH connections to the bare metal servers are made. If it
+	// is not set, the controller connects to the public IPs of the servers directly.
+	// +optional
+	SSHJumpHost *SSHJumpHost `json:"sshJumpHost,omitempty"`
+
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint *clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
@@ -58,9 +64,20 @@ type HetznerClusterSpec struct {
 	// ControlPlaneLoadBalancer is an optional configuration for customizing control plane behavior.
 	ControlPlaneLoadBalancer LoadBalancerSpec `json:"controlPlaneLoadBalancer,omitempty"`
 
+	// ControlPlaneFloatingIP is an alternative to the load balancer of the control plane. The control plane endpoint
+	// is a floating IP that is assigned to one healthy control plane node. It requires the load balancer to be disabled.
+	// +optional
+	ControlPlaneFloatingIP *ControlPlaneFloatingIPSpec `json:"controlPlaneFloatingIP,omitempty"`
+
 	// +optional
 	HCloudPlacementGroups []HCloudPlacementGroupSpec `json:"hcloudPlacementGroups,omitempty"`
 
+	// HCloudFirewalls are firewalls in Hetzner's Cloud API that are applied to the HCloud servers of the cluster.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	HCloudFirewalls []HCloudFirewallSpec `json:"hcloudFirewalls,omitempty"`
+
 	// HetznerSecretRef is a reference to a token to be used when reconciling this cluster.
 	// This is generated in the security section under API TOKENS. Read & write is necessary.
 	HetznerSecret HetznerSecretRef `json:"hetznerSecretRef"`
@@ -73,12 +90,20 @@ type HetznerClusterStatus struct {
 
 	// +optional
 	Network *NetworkStatus `json:"networkStatus,omitempty"`
+	// +optional
+	VSwitch *VSwitchStatus `json:"vSwitch,omitempty"`
 
 	ControlPlaneLoadBalancer *LoadBalancerStatus `json:"controlPlaneLoadBalancer,omitempty"`
 	// +optional
+	ControlPlaneFloatingIP *ControlPlaneFloatingIPStatus `json:"controlPlaneFloatingIP,omitempty"`
+	// +optional
 	HCloudPlacementGroups []HCloudPlacementGroupStatus `json:"hcloudPlacementGroups,omitempty"`
-	FailureDomains        clusterv1.FailureDomains     `json:"failureDomains,omitempty"`
-	Conditions            clusterv1.Conditions         `json:"conditions,omitempty"`
+	// +optional
+	HCloudFirewalls []HCloudFirewallStatus `json:"hcloudFirewalls,omitempty"`
+	// +optional
+	HCloudPrimaryIPPool []HCloudPrimaryIPStatus  `json:"hcloudPrimaryIPPool,omitempty"`
+	FailureDomains      clusterv1.FailureDomains `json:"failureDomains,omitempty"`
+	Conditions          clusterv1.Conditions     `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
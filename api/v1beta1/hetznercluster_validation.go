@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"net"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func validateHCloudFirewalls(firewalls []HCloudFirewallSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, firewall := range firewalls {
+		for j, rule := range firewall.Rules {
+			path := field.NewPath("spec", "hcloudFirewalls").Index(i).Child("rules").Index(j)
+
+			if rule.Protocol == "tcp" || rule.Protocol == "udp" {
+				if rule.Port == nil {
+					allErrs = append(allErrs, field.Required(path.Child("port"), "port is required for protocols tcp and udp"))
+				}
+			} else if rule.Port != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("port"), *rule.Port, "port can only be set for protocols tcp and udp"))
+			}
+
+			if rule.Direction == "in" && len(rule.SourceIPs) == 0 {
+				allErrs = append(allErrs, field.Required(path.Child("sourceIPs"), "sourceIPs are required for inbound rules"))
+			}
+			if rule.Direction == "out" && len(rule.DestinationIPs) == 0 {
+				allErrs = append(allErrs, field.Required(path.Child("destinationIPs"), "destinationIPs are required for outbound rules"))
+			}
+
+			for k, cidr := range rule.SourceIPs {
+				if _, _, err := net.ParseCIDR(cidr); err != nil {
+					allErrs = append(allErrs, field.Invalid(path.Child("sourceIPs").Index(k), cidr, "invalid CIDR"))
+				}
+			}
+			for k, cidr := range rule.DestinationIPs {
+				if _, _, err := net.ParseCIDR(cidr); err != nil {
+					allErrs = append(allErrs, field.Invalid(path.Child("destinationIPs").Index(k), cidr, "invalid CIDR"))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateControlPlaneFloatingIP(spec HetznerClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	floatingIP := spec.ControlPlaneFloatingIP
+	if floatingIP == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "controlPlaneFloatingIP")
+
+	if spec.ControlPlaneLoadBalancer.Enabled {
+		allErrs = append(allErrs, field.Invalid(
+			field.NewPath("spec", "controlPlaneLoadBalancer", "enabled"),
+			spec.ControlPlaneLoadBalancer.Enabled,
+			"load balancer has to be disabled if controlPlaneFloatingIP is specified",
+		))
+	}
+
+	if floatingIP.IP == nil {
+		if floatingIP.Type == ControlPlaneFloatingIPTypeRobot {
+			allErrs = append(allErrs, field.Required(path.Child("ip"), "ip is required for failover IPs of Hetzner Robot"))
+		} else if floatingIP.Region == "" && len(spec.ControlPlaneRegions) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("region"), "region or controlPlaneRegions is required to create a floating IP"))
+		}
+	} else if net.ParseIP(*floatingIP.IP) == nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("ip"), *floatingIP.IP, "invalid IP address"))
+	}
+
+	if floatingIP.Region != "" {
+		if floatingIP.Type == ControlPlaneFloatingIPTypeRobot {
+			allErrs = append(allErrs, field.Invalid(path.Child("region"), floatingIP.Region, "region can only be set for floating IPs of HCloud"))
+		} else if _, ok := regionNetworkZoneMap[string(floatingIP.Region)]; !ok {
+			allErrs = append(allErrs, field.Invalid(path.Child("region"), floatingIP.Region, "wrong region. Should be fsn1, nbg1, hel1, ash, hil or sin"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateHCloudNetworkVSwitch(network HCloudNetworkSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	vSwitch := network.VSwitch
+	if vSwitch == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "hcloudNetwork", "vSwitch")
+
+	if !network.Enabled {
+		allErrs = append(allErrs, field.Invalid(
+			field.NewPath("spec", "hcloudNetwork", "enabled"),
+			network.Enabled,
+			"network has to be enabled if vSwitch is specified",
+		))
+	}
+
+	_, subnet, err := net.ParseCIDR(vSwitch.SubnetCIDRBlock)
+	if err != nil {
+		return append(allErrs, field.Invalid(path.Child("subnetCidrBlock"), vSwitch.SubnetCIDRBlock, "invalid CIDR block"))
+	}
+
+	if _, networkRange, err := net.ParseCIDR(network.CIDRBlock); err == nil && !containsNetwork(networkRange, subnet) {
+		allErrs = append(allErrs, field.Invalid(path.Child("subnetCidrBlock"), vSwitch.SubnetCIDRBlock, "subnet has to be part of the cidrBlock of the network"))
+	}
+
+	if _, cloudSubnet, err := net.ParseCIDR(network.SubnetCIDRBlock); err == nil &&
+		(cloudSubnet.Contains(subnet.IP) || subnet.Contains(cloudSubnet.IP)) {
+		allErrs = append(allErrs, field.Invalid(path.Child("subnetCidrBlock"), vSwitch.SubnetCIDRBlock, "subnet must not overlap with the subnetCidrBlock of the network"))
+	}
+
+	return allErrs
+}
+
+// containsNetwork returns true if the subnet is part of the network.
+func containsNetwork(network, subnet *net.IPNet) bool {
+	networkOnes, _ := network.Mask.Size()
+	subnetOnes, _ := subnet.Mask.Size()
+	return network.Contains(subnet.IP) && subnetOnes >= networkOnes
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateHCloudFirewalls(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []HCloudFirewallRule
+		want  *field.Error
+	}{
+		{
+			name: "Valid inbound rule",
+			rules: []HCloudFirewallRule{
+				{Direction: "in", Protocol: "tcp", Port: ptr.To("6443"), SourceIPs: []string{"0.0.0.0/0", "::/0"}},
+			},
+			want: nil,
+		},
+		{
+			name: "Valid outbound rule",
+			rules: []HCloudFirewallRule{
+				{Direction: "out", Protocol: "icmp", DestinationIPs: []string{"10.0.0.0/8"}},
+			},
+			want: nil,
+		},
+		{
+			name: "Port required for tcp",
+			rules: []HCloudFirewallRule{
+				{Direction: "in", Protocol: "tcp", SourceIPs: []string{"0.0.0.0/0"}},
+			},
+			want: field.Required(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("port"), "port is required for protocols tcp and udp"),
+		},
+		{
+			name: "Port not allowed for icmp",
+			rules: []HCloudFirewallRule{
+				{Direction: "in", Protocol: "icmp", Port: ptr.To("22"), SourceIPs: []string{"0.0.0.0/0"}},
+			},
+			want: field.Invalid(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("port"), "22", "port can only be set for protocols tcp and udp"),
+		},
+		{
+			name: "Source IPs required for inbound rules",
+			rules: []HCloudFirewallRule{
+				{Direction: "in", Protocol: "udp", Port: ptr.To("53")},
+			},
+			want: field.Required(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("sourceIPs"), "sourceIPs are required for inbound rules"),
+		},
+		{
+			name: "Destination IPs required for outbound rules",
+			rules: []HCloudFirewallRule{
+				{Direction: "out", Protocol: "gre"},
+			},
+			want: field.Required(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("destinationIPs"), "destinationIPs are required for outbound rules"),
+		},
+		{
+			name: "Invalid CIDR",
+			rules: []HCloudFirewallRule{
+				{Direction: "in", Protocol: "esp", SourceIPs: []string{"10.0.0.1"}},
+			},
+			want: field.Invalid(field.NewPath("spec", "hcloudFirewalls").Index(0).Child("rules").Index(0).Child("sourceIPs").Index(0), "10.0.0.1", "invalid CIDR"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudFirewalls([]HCloudFirewallSpec{{Name: "firewall", Rules: tt.rules}})
+			if tt.want != nil {
+				assert.Equal(t, field.ErrorList{tt.want}, got)
+			} else {
+				assert.Empty(t, got)
+			}
+		})
+	}
+}
+
+func TestValidateControlPlaneFloatingIP(t *testing.T) {
+	path := field.NewPath("spec", "controlPlaneFloatingIP")
+
+	tests := []struct {
+		name       string
+		floatingIP *ControlPlaneFloatingIPSpec
+		regions    []Region
+		lbEnabled  bool
+		want       *field.Error
+	}{
+		{
+			name:       "No floating IP",
+			floatingIP: nil,
+			lbEnabled:  true,
+			want:       nil,
+		},
+		{
+			name:       "Valid HCloud floating IP",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeHCloud, Region: "fsn1"},
+			want:       nil,
+		},
+		{
+			name:       "Valid Robot failover IP",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeRobot, IP: ptr.To("198.51.100.1")},
+			want:       nil,
+		},
+		{
+			name:       "HCloud floating IP in first control plane region",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeHCloud},
+			regions:    []Region{"nbg1"},
+			want:       nil,
+		},
+		{
+			name:       "Load balancer enabled",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeHCloud, Region: "fsn1"},
+			lbEnabled:  true,
+			want:       field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "enabled"), true, "load balancer has to be disabled if controlPlaneFloatingIP is specified"),
+		},
+		{
+			name:       "IP required for Robot failover IP",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeRobot},
+			want:       field.Required(path.Child("ip"), "ip is required for failover IPs of Hetzner Robot"),
+		},
+		{
+			name:       "Region required for new HCloud floating IP",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeHCloud},
+			want:       field.Required(path.Child("region"), "region or controlPlaneRegions is required to create a floating IP"),
+		},
+		{
+			name:       "Invalid IP",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeHCloud, IP: ptr.To("198.51.100")},
+			want:       field.Invalid(path.Child("ip"), "198.51.100", "invalid IP address"),
+		},
+		{
+			name:       "Region not allowed for Robot failover IP",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeRobot, IP: ptr.To("198.51.100.1"), Region: "fsn1"},
+			want:       field.Invalid(path.Child("region"), Region("fsn1"), "region can only be set for floating IPs of HCloud"),
+		},
+		{
+			name:       "Invalid region",
+			floatingIP: &ControlPlaneFloatingIPSpec{Type: ControlPlaneFloatingIPTypeHCloud, Region: "fsn2"},
+			want:       field.Invalid(path.Child("region"), Region("fsn2"), "wrong region. Should be fsn1, nbg1, hel1, ash, hil or sin"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateControlPlaneFloatingIP(HetznerClusterSpec{
+				ControlPlaneFloatingIP:   tt.floatingIP,
+				ControlPlaneRegions:      tt.regions,
+				ControlPlaneLoadBalancer: LoadBalancerSpec{Enabled: tt.lbEnabled},
+			})
+			if tt.want != nil {
+				assert.Equal(t, field.ErrorList{tt.want}, got)
+			} else {
+				assert.Empty(t, got)
+			}
+		})
+	}
+}
+
+func TestValidateHCloudNetworkVSwitch(t *testing.T) {
+	path := field.NewPath("spec", "hcloudNetwork", "vSwitch", "subnetCidrBlock")
+
+	tests := []struct {
+		name    string
+		enabled bool
+		vSwitch *HCloudNetworkVSwitchSpec
+		want    *field.Error
+	}{
+		{
+			name:    "No vSwitch",
+			enabled: false,
+			vSwitch: nil,
+			want:    nil,
+		},
+		{
+			name:    "Valid vSwitch",
+			enabled: true,
+			vSwitch: &HCloudNetworkVSwitchSpec{VLANID: 4000, SubnetCIDRBlock: "10.0.1.0/24"},
+			want:    nil,
+		},
+		{
+			name:    "Network disabled",
+			enabled: false,
+			vSwitch: &HCloudNetworkVSwitchSpec{VLANID: 4000, SubnetCIDRBlock: "10.0.1.0/24"},
+			want:    field.Invalid(field.NewPath("spec", "hcloudNetwork", "enabled"), false, "network has to be enabled if vSwitch is specified"),
+		},
+		{
+			name:    "Invalid CIDR block",
+			enabled: true,
+			vSwitch: &HCloudNetworkVSwitchSpec{VLANID: 4000, SubnetCIDRBlock: "10.0.1.0"},
+			want:    field.Invalid(path, "10.0.1.0", "invalid CIDR block"),
+		},
+		{
+			name:    "Subnet outside of network",
+			enabled: true,
+			vSwitch: &HCloudNetworkVSwitchSpec{VLANID: 4000, SubnetCIDRBlock: "10.1.0.0/24"},
+			want:    field.Invalid(path, "10.1.0.0/24", "subnet has to be part of the cidrBlock of the network"),
+		},
+		{
+			name:    "Subnet overlaps with cloud subnet",
+			enabled: true,
+			vSwitch: &HCloudNetworkVSwitchSpec{VLANID: 4000, SubnetCIDRBlock: "10.0.0.0/23"},
+			want:    field.Invalid(path, "10.0.0.0/23", "subnet must not overlap with the subnetCidrBlock of the network"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateHCloudNetworkVSwitch(HCloudNetworkSpec{
+				Enabled:         tt.enabled,
+				CIDRBlock:       "10.0.0.0/16",
+				SubnetCIDRBlock: "10.0.0.0/24",
+				VSwitch:         tt.vSwitch,
+			})
+			if tt.want != nil {
+				assert.Equal(t, field.ErrorList{tt.want}, got)
+			} else {
+				assert.Empty(t, got)
+			}
+		})
+	}
+}
@@ -113,7 +113,7 @@ func (r *HetznerCluster) ValidateCreate() (admission.Warnings, error) {
 
 	// Check whether controlPlaneEndpoint is specified if allow empty is not set or false
 
-	if !allowEmptyControlPlaneAddress && !r.Spec.ControlPlaneLoadBalancer.Enabled {
+	if !allowEmptyControlPlaneAddress && !r.Spec.ControlPlaneLoadBalancer.Enabled && r.Spec.ControlPlaneFloatingIP == nil {
 		if r.Spec.ControlPlaneEndpoint == nil ||
 			r.Spec.ControlPlaneEndpoint.Host == "" ||
 			r.Spec.ControlPlaneEndpoint.Port == 0 {
@@ -121,7 +121,7 @@ func (r *HetznerCluster) ValidateCreate() (admission.Warnings, error) {
 				field.Invalid(
 					field.NewPath("spec", "controlPlaneEndpoint"),
 					r.Spec.ControlPlaneEndpoint,
-					"controlPlaneEndpoint has to be specified if controlPlaneLoadBalancer is not enabled",
+					"controlPlaneEndpoint has to be specified if neither controlPlaneLoadBalancer nor controlPlaneFloatingIP is enabled",
 				),
 			)
 		}
@@ -131,6 +131,10 @@ func (r *HetznerCluster) ValidateCreate() (admission.Warnings, error) {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateHCloudFirewalls(r.Spec.HCloudFirewalls)...)
+	allErrs = append(allErrs, validateControlPlaneFloatingIP(r.Spec)...)
+	allErrs = append(allErrs, validateHCloudNetworkVSwitch(r.Spec.HCloudNetwork)...)
+
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -200,10 +204,19 @@ func (r *HetznerCluster) ValidateUpdate(old runtime.Object) (admission.Warnings,
 		)
 	}
 
+	// The floating IP of the control plane is immutable
+	if !reflect.DeepEqual(oldC.Spec.ControlPlaneFloatingIP, r.Spec.ControlPlaneFloatingIP) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "controlPlaneFloatingIP"), r.Spec.ControlPlaneFloatingIP, "field is immutable"),
+		)
+	}
+
 	if err := r.validateHetznerSecretKey(); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, validateHCloudFirewalls(r.Spec.HCloudFirewalls)...)
+
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -49,6 +49,15 @@ const (
 
 	// MachineNameTagKey tags related MachineNameTag.
 	MachineNameTagKey = "machine." + NameHetznerProviderPrefix + "name"
+
+	// VolumeNameTagKey tags the volumes of a machine with the name of the volume in the spec of the machine.
+	VolumeNameTagKey = "volume." + NameHetznerProviderPrefix + "name"
+
+	// PrimaryIPPoolTagKey tags the primary IPs that are kept in the pool of a cluster.
+	PrimaryIPPoolTagKey = "primary-ip-pool." + NameHetznerProviderPrefix + "machine-type"
+
+	// PrimaryIPPoolControlPlane is the value of PrimaryIPPoolTagKey for the primary IPs of control plane servers.
+	PrimaryIPPoolControlPlane = "control_plane"
 )
 
 // ClusterHetznerCloudProviderTagKey generates the key for resources associated a cluster's HCloud cloud provider.
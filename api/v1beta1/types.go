@@ -66,6 +66,27 @@ type HetznerSSHKeys struct {
 	RobotRescueSecretRef SSHSecretRef `json:"robotRescueSecretRef,omitempty"`
 }
 
+// SSHJumpHost defines a bastion host through which the controller connects to the bare metal servers via SSH.
+type SSHJumpHost struct {
+	// Address is the host name or IP address of the jump host, optionally followed by the port, e.g.
+	// "bastion.example.com:2222". Port 22 is used if no port is given.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+
+	// User is the user that logs in on the jump host.
+	// +optional
+	// +kubebuilder:default=root
+	User string `json:"user,omitempty"`
+
+	// SecretRef defines the reference to the secret where the SSH key for the jump host is stored.
+	SecretRef SSHSecretRef `json:"secretRef"`
+
+	// HostKeyFingerprint is the SHA256 fingerprint of the host key of the jump host, e.g. "SHA256:abc...".
+	// The controller refuses to connect to a jump host with a different host key.
+	// +kubebuilder:validation:Pattern=`^SHA256:.+$`
+	HostKeyFingerprint string `json:"hostKeyFingerprint"`
+}
+
 // SSHKey defines the SSHKey for HCloud.
 type SSHKey struct {
 	// Name defines the name of the SSH key.
@@ -79,6 +100,33 @@ type SSHKey struct {
 // HCloudMachineType defines the HCloud Machine type.
 type HCloudMachineType string
 
+// HCloudImageSelectionPolicy defines which image is used if several images match an image selector.
+type HCloudImageSelectionPolicy string
+
+const (
+	// HCloudImageSelectionPolicyNewest uses the image that has been created last.
+	HCloudImageSelectionPolicyNewest HCloudImageSelectionPolicy = "Newest"
+	// HCloudImageSelectionPolicyUnique requires that exactly one image matches.
+	HCloudImageSelectionPolicyUnique HCloudImageSelectionPolicy = "Unique"
+)
+
+// HCloudImageSelector selects images in Hetzner's Cloud API by their labels.
+type HCloudImageSelector struct {
+	// LabelSelector is a label selector of Hetzner's Cloud API, e.g. "os==ubuntu,k8s-version=={{ .KubernetesVersion }}".
+	// It is a Go template with the fields KubernetesVersion (the version of the Machine) and
+	// Architecture (the architecture of the server type, either x86 or arm).
+	// Only images with the architecture of the server type are considered.
+	// +kubebuilder:validation:MinLength=1
+	LabelSelector string `json:"labelSelector"`
+
+	// Policy defines which image is used if several images match. Newest uses the image that has been created last,
+	// Unique requires that exactly one image matches.
+	// +optional
+	// +kubebuilder:validation:Enum=Newest;Unique
+	// +kubebuilder:default=Newest
+	Policy HCloudImageSelectionPolicy `json:"policy,omitempty"`
+}
+
 // ResourceLifecycle configures the lifecycle of a resource.
 type ResourceLifecycle string
 
@@ -99,6 +147,56 @@ type HCloudPlacementGroupStatus struct {
 	Type   string  `json:"type,omitempty"`
 }
 
+// HCloudFirewallSpec defines a firewall in Hetzner's Cloud API that is managed by the cluster.
+type HCloudFirewallSpec struct {
+	// Name of the firewall. The firewall in HCloud API is called "<cluster name>-<name>".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Rules of the firewall. If there are no rules, all inbound traffic is blocked.
+	// +optional
+	Rules []HCloudFirewallRule `json:"rules,omitempty"`
+
+	// LabelSelector selects the servers of the cluster the firewall is applied to, e.g. "machine_type==control_plane".
+	// It is combined with the label of the cluster, so that only servers of the cluster are selected.
+	// If it is not set, the firewall is applied to all HCloud servers of the cluster.
+	// +optional
+	LabelSelector *string `json:"labelSelector,omitempty"`
+}
+
+// HCloudFirewallRule defines a rule of a firewall in Hetzner's Cloud API.
+type HCloudFirewallRule struct {
+	// Direction of the traffic the rule applies to.
+	// +kubebuilder:validation:Enum=in;out
+	Direction string `json:"direction"`
+
+	// Protocol of the traffic the rule applies to.
+	// +kubebuilder:validation:Enum=tcp;udp;icmp;esp;gre
+	Protocol string `json:"protocol"`
+
+	// Port or port range, e.g. "80" or "30000-32767". Only used and required for the protocols tcp and udp.
+	// +optional
+	Port *string `json:"port,omitempty"`
+
+	// SourceIPs are the CIDRs traffic is allowed from. Only used and required for inbound rules.
+	// +optional
+	SourceIPs []string `json:"sourceIPs,omitempty"`
+
+	// DestinationIPs are the CIDRs traffic is allowed to. Only used and required for outbound rules.
+	// +optional
+	DestinationIPs []string `json:"destinationIPs,omitempty"`
+
+	// Description of the rule.
+	// +optional
+	Description *string `json:"description,omitempty"`
+}
+
+// HCloudFirewallStatus returns the status of a firewall.
+type HCloudFirewallStatus struct {
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
 // HetznerSecretRef defines all the names of the secret and the relevant keys needed to access Hetzner API.
 type HetznerSecretRef struct {
 	// Name defines the name of the secret.
@@ -141,6 +239,93 @@ type PublicNetworkSpec struct {
 	// +optional
 	// +kubebuilder:default=true
 	EnableIPv6 bool `json:"enableIPv6"`
+
+	// PrimaryIPv4 is the name of an existing primary IPv4 in Hetzner's Cloud API that is assigned to the server.
+	// The primary IP must not be assigned to another server and defines the location of the server.
+	// +optional
+	PrimaryIPv4 *string `json:"primaryIPv4,omitempty"`
+
+	// PrimaryIPv6 is the name of an existing primary IPv6 in Hetzner's Cloud API that is assigned to the server.
+	// The primary IP must not be assigned to another server and defines the location of the server.
+	// +optional
+	PrimaryIPv6 *string `json:"primaryIPv6,omitempty"`
+
+	// UsePrimaryIPPool defines whether the primary IPs of the server are kept in a pool of the cluster when the
+	// server is deleted, so that they are assigned to the next server that is created in the same location.
+	// It is only supported for control planes and ignored for other machines.
+	// +optional
+	UsePrimaryIPPool bool `json:"usePrimaryIPPool,omitempty"`
+}
+
+// HCloudPrimaryIPStatus returns the status of a primary IP in the pool of the cluster.
+type HCloudPrimaryIPStatus struct {
+	ID   int64  `json:"id,omitempty"`
+	IP   string `json:"ip,omitempty"`
+	Type string `json:"type,omitempty"`
+
+	// Region is the location of the primary IP. It can only be assigned to servers in the same location.
+	// +optional
+	Region Region `json:"region,omitempty"`
+
+	// ServerID is the ID of the server the primary IP is assigned to.
+	// +optional
+	ServerID int64 `json:"serverID,omitempty"`
+}
+
+// ControlPlaneFloatingIPType defines the type of the floating IP of the control plane.
+type ControlPlaneFloatingIPType string
+
+const (
+	// ControlPlaneFloatingIPTypeHCloud is a floating IP in Hetzner's Cloud API that is assigned to HCloud servers.
+	ControlPlaneFloatingIPTypeHCloud ControlPlaneFloatingIPType = "hcloud"
+	// ControlPlaneFloatingIPTypeRobot is a failover IP in Hetzner's Robot API that is routed to bare metal servers.
+	ControlPlaneFloatingIPTypeRobot ControlPlaneFloatingIPType = "robot"
+)
+
+// ControlPlaneFloatingIPSpec defines a floating IP that is used as control plane endpoint instead of a load balancer.
+// The IP is assigned to one healthy control plane node and moved to another one if the API server cannot be reached anymore.
+// The nodes have to configure the IP on their network interface themselves, e.g. via cloud-init.
+type ControlPlaneFloatingIPSpec struct {
+	// Type defines whether a floating IP of HCloud or a failover IP of Hetzner Robot is used.
+	// HCloud floating IPs can only be assigned to HCloud servers, Robot failover IPs only to bare metal servers.
+	// +optional
+	// +kubebuilder:validation:Enum=hcloud;robot
+	// +kubebuilder:default=hcloud
+	Type ControlPlaneFloatingIPType `json:"type,omitempty"`
+
+	// IP is the address of an existing floating IP or failover IP. It is required for failover IPs of Hetzner Robot.
+	// If it is not specified for HCloud, a floating IP is created and deleted together with the cluster.
+	// +optional
+	IP *string `json:"ip,omitempty"`
+
+	// Region is the home location of a floating IP that is created in HCloud. It defaults to the first control plane region.
+	// +optional
+	Region Region `json:"region,omitempty"`
+
+	// Port defines the API Server port. It must be a valid port range (1-65535). If omitted, the default value is 6443.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=6443
+	Port int `json:"port,omitempty"`
+}
+
+// ControlPlaneFloatingIPStatus defines the observed state of the floating IP of the control plane.
+type ControlPlaneFloatingIPStatus struct {
+	// ID is the ID of the floating IP in HCloud. It is not set for failover IPs of Hetzner Robot.
+	// +optional
+	ID int64 `json:"id,omitempty"`
+
+	// IP is the address of the floating IP.
+	IP string `json:"ip,omitempty"`
+
+	// ServerID is the ID of the HCloud server the floating IP is assigned to.
+	// +optional
+	ServerID int64 `json:"serverID,omitempty"`
+
+	// ActiveServerIP is the main IP of the bare metal server the failover IP is routed to.
+	// +optional
+	ActiveServerIP string `json:"activeServerIP,omitempty"`
 }
 
 // LoadBalancerSpec defines the desired state of the Control Plane load balancer.
@@ -237,6 +422,53 @@ type HCloudNetworkSpec struct {
 	// +kubebuilder:default=eu-central
 	// +optional
 	NetworkZone HCloudNetworkZone `json:"networkZone,omitempty"`
+
+	// VSwitch connects bare metal servers with the network via a Hetzner Robot vSwitch.
+	// +optional
+	VSwitch *HCloudNetworkVSwitchSpec `json:"vSwitch,omitempty"`
+}
+
+// HCloudNetworkVSwitchSpec defines a Hetzner Robot vSwitch that connects bare metal servers with the HCloud network.
+type HCloudNetworkVSwitchSpec struct {
+	// ID is the ID of an existing vSwitch in Hetzner Robot. If not set, the vSwitch with the name of the
+	// HetznerCluster is used. If there is none, it is created.
+	// +optional
+	ID *int `json:"id,omitempty"`
+
+	// VLANID is the VLAN ID of the vSwitch.
+	// +kubebuilder:validation:Minimum=4000
+	// +kubebuilder:validation:Maximum=4091
+	VLANID int `json:"vlanID"`
+
+	// SubnetCIDRBlock defines the cidrBlock of the vSwitch subnet of the HCloud Network. The bare metal
+	// servers get their private IPs from this range. It has to be part of the cidrBlock of the network.
+	// +kubebuilder:default="10.0.1.0/24"
+	// +optional
+	SubnetCIDRBlock string `json:"subnetCidrBlock,omitempty"`
+}
+
+// VSwitchStatus defines the observed state of the vSwitch.
+type VSwitchStatus struct {
+	// ID is the ID of the vSwitch in Hetzner Robot.
+	ID int `json:"id"`
+
+	// Created is true if the vSwitch was created by the controller. Only created vSwitches are cancelled
+	// when the HetznerCluster is deleted.
+	// +optional
+	Created bool `json:"created,omitempty"`
+
+	// Servers are the bare metal servers that are connected to the vSwitch.
+	// +optional
+	Servers []VSwitchServerStatus `json:"servers,omitempty"`
+}
+
+// VSwitchServerStatus defines a bare metal server that is connected to the vSwitch.
+type VSwitchServerStatus struct {
+	// ServerID is the ID of the bare metal server in Hetzner Robot.
+	ServerID int `json:"serverID"`
+
+	// IP is the private IP of the server in the vSwitch subnet.
+	IP string `json:"ip"`
 }
 
 // NetworkStatus defines the observed state of the HCloud Private Network.
@@ -44,6 +44,64 @@ func (in *BTRFSDefinition) DeepCopy() *BTRFSDefinition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BurnInResult) DeepCopyInto(out *BurnInResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BurnInResult.
+func (in *BurnInResult) DeepCopy() *BurnInResult {
+	if in == nil {
+		return nil
+	}
+	out := new(BurnInResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BurnInSpec) DeepCopyInto(out *BurnInSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BurnInSpec.
+func (in *BurnInSpec) DeepCopy() *BurnInSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BurnInSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BurnInStatus) DeepCopyInto(out *BurnInStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]BurnInResult, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BurnInStatus.
+func (in *BurnInStatus) DeepCopy() *BurnInStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BurnInStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPU) DeepCopyInto(out *CPU) {
 	*out = *in
@@ -64,6 +122,41 @@ func (in *CPU) DeepCopy() *CPU {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneFloatingIPSpec) DeepCopyInto(out *ControlPlaneFloatingIPSpec) {
+	*out = *in
+	if in.IP != nil {
+		in, out := &in.IP, &out.IP
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneFloatingIPSpec.
+func (in *ControlPlaneFloatingIPSpec) DeepCopy() *ControlPlaneFloatingIPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneFloatingIPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneFloatingIPStatus) DeepCopyInto(out *ControlPlaneFloatingIPStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneFloatingIPStatus.
+func (in *ControlPlaneFloatingIPStatus) DeepCopy() *ControlPlaneFloatingIPStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneFloatingIPStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerGeneratedStatus) DeepCopyInto(out *ControllerGeneratedStatus) {
 	*out = *in
@@ -97,6 +190,28 @@ func (in *ControllerGeneratedStatus) DeepCopyInto(out *ControllerGeneratedStatus
 		in, out := &in.LastUpdated, &out.LastUpdated
 		*out = (*in).DeepCopy()
 	}
+	if in.BurnIn != nil {
+		in, out := &in.BurnIn, &out.BurnIn
+		*out = new(BurnInStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastDiskHealthCheck != nil {
+		in, out := &in.LastDiskHealthCheck, &out.LastDiskHealthCheck
+		*out = (*in).DeepCopy()
+	}
+	if in.ProvisioningLogs != nil {
+		in, out := &in.ProvisioningLogs, &out.ProvisioningLogs
+		*out = new(ProvisioningLogsStatus)
+		**out = **in
+	}
+	if in.RobotLinuxInstallStarted != nil {
+		in, out := &in.RobotLinuxInstallStarted, &out.RobotLinuxInstallStarted
+		*out = (*in).DeepCopy()
+	}
+	if in.ReleasedAt != nil {
+		in, out := &in.ReleasedAt, &out.ReleasedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -116,6 +231,129 @@ func (in *ControllerGeneratedStatus) DeepCopy() *ControllerGeneratedStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskHealthCheckSpec) DeepCopyInto(out *DiskHealthCheckSpec) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskHealthCheckSpec.
+func (in *DiskHealthCheckSpec) DeepCopy() *DiskHealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskHealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskImage) DeepCopyInto(out *DiskImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskImage.
+func (in *DiskImage) DeepCopy() *DiskImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudFirewallRule) DeepCopyInto(out *HCloudFirewallRule) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(string)
+		**out = **in
+	}
+	if in.SourceIPs != nil {
+		in, out := &in.SourceIPs, &out.SourceIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DestinationIPs != nil {
+		in, out := &in.DestinationIPs, &out.DestinationIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudFirewallRule.
+func (in *HCloudFirewallRule) DeepCopy() *HCloudFirewallRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudFirewallRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudFirewallSpec) DeepCopyInto(out *HCloudFirewallSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HCloudFirewallRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudFirewallSpec.
+func (in *HCloudFirewallSpec) DeepCopy() *HCloudFirewallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudFirewallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudFirewallStatus) DeepCopyInto(out *HCloudFirewallStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudFirewallStatus.
+func (in *HCloudFirewallStatus) DeepCopy() *HCloudFirewallStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudFirewallStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudImageSelector) DeepCopyInto(out *HCloudImageSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudImageSelector.
+func (in *HCloudImageSelector) DeepCopy() *HCloudImageSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudImageSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HCloudMachine) DeepCopyInto(out *HCloudMachine) {
 	*out = *in
@@ -183,6 +421,21 @@ func (in *HCloudMachineSpec) DeepCopyInto(out *HCloudMachineSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FallbackTypes != nil {
+		in, out := &in.FallbackTypes, &out.FallbackTypes
+		*out = make([]HCloudMachineType, len(*in))
+		copy(*out, *in)
+	}
+	if in.FallbackRegions != nil {
+		in, out := &in.FallbackRegions, &out.FallbackRegions
+		*out = make([]Region, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageSelector != nil {
+		in, out := &in.ImageSelector, &out.ImageSelector
+		*out = new(HCloudImageSelector)
+		**out = **in
+	}
 	if in.SSHKeys != nil {
 		in, out := &in.SSHKeys, &out.SSHKeys
 		*out = make([]SSHKey, len(*in))
@@ -196,7 +449,14 @@ func (in *HCloudMachineSpec) DeepCopyInto(out *HCloudMachineSpec) {
 	if in.PublicNetwork != nil {
 		in, out := &in.PublicNetwork, &out.PublicNetwork
 		*out = new(PublicNetworkSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]HCloudVolumeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -223,6 +483,11 @@ func (in *HCloudMachineStatus) DeepCopyInto(out *HCloudMachineStatus) {
 		*out = make([]SSHKey, len(*in))
 		copy(*out, *in)
 	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]HCloudVolumeStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.InstanceState != nil {
 		in, out := &in.InstanceState, &out.InstanceState
 		*out = new(hcloud.ServerStatus)
@@ -381,6 +646,11 @@ func (in *HCloudMachineTemplateStatus) DeepCopy() *HCloudMachineTemplateStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HCloudNetworkSpec) DeepCopyInto(out *HCloudNetworkSpec) {
 	*out = *in
+	if in.VSwitch != nil {
+		in, out := &in.VSwitch, &out.VSwitch
+		*out = new(HCloudNetworkVSwitchSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudNetworkSpec.
@@ -393,6 +663,26 @@ func (in *HCloudNetworkSpec) DeepCopy() *HCloudNetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudNetworkVSwitchSpec) DeepCopyInto(out *HCloudNetworkVSwitchSpec) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudNetworkVSwitchSpec.
+func (in *HCloudNetworkVSwitchSpec) DeepCopy() *HCloudNetworkVSwitchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudNetworkVSwitchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HCloudPlacementGroupSpec) DeepCopyInto(out *HCloudPlacementGroupSpec) {
 	*out = *in
@@ -428,6 +718,21 @@ func (in *HCloudPlacementGroupStatus) DeepCopy() *HCloudPlacementGroupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudPrimaryIPStatus) DeepCopyInto(out *HCloudPrimaryIPStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudPrimaryIPStatus.
+func (in *HCloudPrimaryIPStatus) DeepCopy() *HCloudPrimaryIPStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudPrimaryIPStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HCloudRemediation) DeepCopyInto(out *HCloudRemediation) {
 	*out = *in
@@ -640,6 +945,46 @@ func (in *HCloudRemediationTemplateStatus) DeepCopy() *HCloudRemediationTemplate
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudVolumeSpec) DeepCopyInto(out *HCloudVolumeSpec) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(string)
+		**out = **in
+	}
+	if in.MountPath != nil {
+		in, out := &in.MountPath, &out.MountPath
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudVolumeSpec.
+func (in *HCloudVolumeSpec) DeepCopy() *HCloudVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HCloudVolumeStatus) DeepCopyInto(out *HCloudVolumeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HCloudVolumeStatus.
+func (in *HCloudVolumeStatus) DeepCopy() *HCloudVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HCloudVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HardwareDetails) DeepCopyInto(out *HardwareDetails) {
 	*out = *in
@@ -666,6 +1011,21 @@ func (in *HardwareDetails) DeepCopy() *HardwareDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareRequirements) DeepCopyInto(out *HardwareRequirements) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareRequirements.
+func (in *HardwareRequirements) DeepCopy() *HardwareRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HetznerBareMetalHost) DeepCopyInto(out *HetznerBareMetalHost) {
 	*out = *in
@@ -733,6 +1093,31 @@ func (in *HetznerBareMetalHostSpec) DeepCopyInto(out *HetznerBareMetalHostSpec)
 		*out = new(RootDeviceHints)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RootDeviceHintsPolicy != nil {
+		in, out := &in.RootDeviceHintsPolicy, &out.RootDeviceHintsPolicy
+		*out = new(RootDeviceHintsPolicy)
+		**out = **in
+	}
+	if in.BurnIn != nil {
+		in, out := &in.BurnIn, &out.BurnIn
+		*out = new(BurnInSpec)
+		**out = **in
+	}
+	if in.DiskHealthCheck != nil {
+		in, out := &in.DiskHealthCheck, &out.DiskHealthCheck
+		*out = new(DiskHealthCheckSpec)
+		**out = **in
+	}
+	if in.ProvisioningLogsHistoryLimit != nil {
+		in, out := &in.ProvisioningLogsHistoryLimit, &out.ProvisioningLogsHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.SSHJumpHost != nil {
+		in, out := &in.SSHJumpHost, &out.SSHJumpHost
+		*out = new(SSHJumpHost)
+		**out = **in
+	}
 	if in.ConsumerRef != nil {
 		in, out := &in.ConsumerRef, &out.ConsumerRef
 		*out = new(v1.ObjectReference)
@@ -771,6 +1156,115 @@ func (in *HetznerBareMetalHostStatus) DeepCopy() *HetznerBareMetalHostStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HetznerBareMetalInventory) DeepCopyInto(out *HetznerBareMetalInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerBareMetalInventory.
+func (in *HetznerBareMetalInventory) DeepCopy() *HetznerBareMetalInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(HetznerBareMetalInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HetznerBareMetalInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HetznerBareMetalInventoryList) DeepCopyInto(out *HetznerBareMetalInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HetznerBareMetalInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerBareMetalInventoryList.
+func (in *HetznerBareMetalInventoryList) DeepCopy() *HetznerBareMetalInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(HetznerBareMetalInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HetznerBareMetalInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HetznerBareMetalInventorySpec) DeepCopyInto(out *HetznerBareMetalInventorySpec) {
+	*out = *in
+	out.HetznerSecret = in.HetznerSecret
+	in.Filter.DeepCopyInto(&out.Filter)
+	in.HostTemplate.DeepCopyInto(&out.HostTemplate)
+	out.SyncInterval = in.SyncInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerBareMetalInventorySpec.
+func (in *HetznerBareMetalInventorySpec) DeepCopy() *HetznerBareMetalInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HetznerBareMetalInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HetznerBareMetalInventoryStatus) DeepCopyInto(out *HetznerBareMetalInventoryStatus) {
+	*out = *in
+	if in.CancelledServers != nil {
+		in, out := &in.CancelledServers, &out.CancelledServers
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HetznerBareMetalInventoryStatus.
+func (in *HetznerBareMetalInventoryStatus) DeepCopy() *HetznerBareMetalInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HetznerBareMetalInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HetznerBareMetalMachine) DeepCopyInto(out *HetznerBareMetalMachine) {
 	*out = *in
@@ -1251,24 +1745,41 @@ func (in *HetznerClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HetznerClusterSpec) DeepCopyInto(out *HetznerClusterSpec) {
 	*out = *in
-	out.HCloudNetwork = in.HCloudNetwork
+	in.HCloudNetwork.DeepCopyInto(&out.HCloudNetwork)
 	if in.ControlPlaneRegions != nil {
 		in, out := &in.ControlPlaneRegions, &out.ControlPlaneRegions
 		*out = make([]Region, len(*in))
 		copy(*out, *in)
 	}
 	in.SSHKeys.DeepCopyInto(&out.SSHKeys)
+	if in.SSHJumpHost != nil {
+		in, out := &in.SSHJumpHost, &out.SSHJumpHost
+		*out = new(SSHJumpHost)
+		**out = **in
+	}
 	if in.ControlPlaneEndpoint != nil {
 		in, out := &in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint
 		*out = new(apiv1beta1.APIEndpoint)
 		**out = **in
 	}
 	in.ControlPlaneLoadBalancer.DeepCopyInto(&out.ControlPlaneLoadBalancer)
+	if in.ControlPlaneFloatingIP != nil {
+		in, out := &in.ControlPlaneFloatingIP, &out.ControlPlaneFloatingIP
+		*out = new(ControlPlaneFloatingIPSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.HCloudPlacementGroups != nil {
 		in, out := &in.HCloudPlacementGroups, &out.HCloudPlacementGroups
 		*out = make([]HCloudPlacementGroupSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.HCloudFirewalls != nil {
+		in, out := &in.HCloudFirewalls, &out.HCloudFirewalls
+		*out = make([]HCloudFirewallSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.HetznerSecret = in.HetznerSecret
 }
 
@@ -1290,11 +1801,21 @@ func (in *HetznerClusterStatus) DeepCopyInto(out *HetznerClusterStatus) {
 		*out = new(NetworkStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VSwitch != nil {
+		in, out := &in.VSwitch, &out.VSwitch
+		*out = new(VSwitchStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ControlPlaneLoadBalancer != nil {
 		in, out := &in.ControlPlaneLoadBalancer, &out.ControlPlaneLoadBalancer
 		*out = new(LoadBalancerStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ControlPlaneFloatingIP != nil {
+		in, out := &in.ControlPlaneFloatingIP, &out.ControlPlaneFloatingIP
+		*out = new(ControlPlaneFloatingIPStatus)
+		**out = **in
+	}
 	if in.HCloudPlacementGroups != nil {
 		in, out := &in.HCloudPlacementGroups, &out.HCloudPlacementGroups
 		*out = make([]HCloudPlacementGroupStatus, len(*in))
@@ -1302,6 +1823,16 @@ func (in *HetznerClusterStatus) DeepCopyInto(out *HetznerClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HCloudFirewalls != nil {
+		in, out := &in.HCloudFirewalls, &out.HCloudFirewalls
+		*out = make([]HCloudFirewallStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.HCloudPrimaryIPPool != nil {
+		in, out := &in.HCloudPrimaryIPPool, &out.HCloudPrimaryIPPool
+		*out = make([]HCloudPrimaryIPStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.FailureDomains != nil {
 		in, out := &in.FailureDomains, &out.FailureDomains
 		*out = make(apiv1beta1.FailureDomains, len(*in))
@@ -1488,6 +2019,11 @@ func (in *HostSelector) DeepCopyInto(out *HostSelector) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HardwareRequirements != nil {
+		in, out := &in.HardwareRequirements, &out.HardwareRequirements
+		*out = new(HardwareRequirements)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostSelector.
@@ -1538,6 +2074,16 @@ func (in *Image) DeepCopy() *Image {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstallImage) DeepCopyInto(out *InstallImage) {
 	*out = *in
+	if in.RobotLinux != nil {
+		in, out := &in.RobotLinux, &out.RobotLinux
+		*out = new(RobotLinuxInstall)
+		**out = **in
+	}
+	if in.DiskImage != nil {
+		in, out := &in.DiskImage, &out.DiskImage
+		*out = new(DiskImage)
+		**out = **in
+	}
 	out.Image = in.Image
 	if in.Partitions != nil {
 		in, out := &in.Partitions, &out.Partitions
@@ -1566,6 +2112,60 @@ func (in *InstallImage) DeepCopy() *InstallImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryFilter) DeepCopyInto(out *InventoryFilter) {
+	*out = *in
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostSelector != nil {
+		in, out := &in.HostSelector, &out.HostSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryFilter.
+func (in *InventoryFilter) DeepCopy() *InventoryFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryHostTemplate) DeepCopyInto(out *InventoryHostTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryHostTemplate.
+func (in *InventoryHostTemplate) DeepCopy() *InventoryHostTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryHostTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LVMDefinition) DeepCopyInto(out *LVMDefinition) {
 	*out = *in
@@ -1713,9 +2313,34 @@ func (in *Partition) DeepCopy() *Partition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningLogsStatus) DeepCopyInto(out *ProvisioningLogsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningLogsStatus.
+func (in *ProvisioningLogsStatus) DeepCopy() *ProvisioningLogsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningLogsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublicNetworkSpec) DeepCopyInto(out *PublicNetworkSpec) {
 	*out = *in
+	if in.PrimaryIPv4 != nil {
+		in, out := &in.PrimaryIPv4, &out.PrimaryIPv4
+		*out = new(string)
+		**out = **in
+	}
+	if in.PrimaryIPv6 != nil {
+		in, out := &in.PrimaryIPv6, &out.PrimaryIPv6
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicNetworkSpec.
@@ -1783,6 +2408,21 @@ func (in *RemediationStrategy) DeepCopy() *RemediationStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RobotLinuxInstall) DeepCopyInto(out *RobotLinuxInstall) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RobotLinuxInstall.
+func (in *RobotLinuxInstall) DeepCopy() *RobotLinuxInstall {
+	if in == nil {
+		return nil
+	}
+	out := new(RobotLinuxInstall)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RootDeviceHints) DeepCopyInto(out *RootDeviceHints) {
 	*out = *in
@@ -1799,6 +2439,37 @@ func (in *RootDeviceHints) DeepCopy() *RootDeviceHints {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RootDeviceHintsPolicy) DeepCopyInto(out *RootDeviceHintsPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RootDeviceHintsPolicy.
+func (in *RootDeviceHintsPolicy) DeepCopy() *RootDeviceHintsPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RootDeviceHintsPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHJumpHost) DeepCopyInto(out *SSHJumpHost) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHJumpHost.
+func (in *SSHJumpHost) DeepCopy() *SSHJumpHost {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHJumpHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSHKey) DeepCopyInto(out *SSHKey) {
 	*out = *in
@@ -1935,3 +2606,38 @@ func (in *Storage) DeepCopy() *Storage {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSwitchServerStatus) DeepCopyInto(out *VSwitchServerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSwitchServerStatus.
+func (in *VSwitchServerStatus) DeepCopy() *VSwitchServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSwitchServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSwitchStatus) DeepCopyInto(out *VSwitchStatus) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]VSwitchServerStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSwitchStatus.
+func (in *VSwitchStatus) DeepCopy() *VSwitchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VSwitchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
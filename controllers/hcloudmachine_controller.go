@@ -57,6 +57,8 @@ type HCloudMachineReconciler struct {
 	APIReader           client.Reader
 	HCloudClientFactory hcloudclient.Factory
 	WatchFilterValue    string
+
+	workloadClients *scope.WorkloadClientCache
 }
 
 //+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
@@ -140,6 +142,8 @@ func (r *HCloudMachineReconciler) Reconcile(ctx context.Context, req reconcile.R
 			HCloudClient:   hcc,
 			HetznerSecret:  hetznerSecret,
 			APIReader:      r.APIReader,
+
+			WorkloadClientCache: r.workloadClients,
 		},
 		Machine:       machine,
 		HCloudMachine: hcloudMachine,
@@ -185,6 +189,11 @@ func (r *HCloudMachineReconciler) reconcileDelete(ctx context.Context, machineSc
 	if result != emptyResult {
 		return result, nil
 	}
+	// the client of the workload cluster is not needed anymore if the whole cluster is deleted
+	if !machineScope.Cluster.DeletionTimestamp.IsZero() {
+		r.workloadClients.Delete(client.ObjectKeyFromObject(machineScope.Cluster))
+	}
+
 	// Machine is deleted so remove the finalizer.
 	controllerutil.RemoveFinalizer(machineScope.HCloudMachine, infrav1.HCloudMachineFinalizer)
 	controllerutil.RemoveFinalizer(machineScope.HCloudMachine, infrav1.DeprecatedHCloudMachineFinalizer)
@@ -218,6 +227,8 @@ func (r *HCloudMachineReconciler) reconcileNormal(ctx context.Context, machineSc
 func (r *HCloudMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	log := ctrl.LoggerFrom(ctx)
 
+	r.workloadClients = scope.NewWorkloadClientCache()
+
 	clusterToObjectFunc, err := util.ClusterToTypedObjectsMapper(r.Client, &infrav1.HCloudMachineList{}, mgr.GetScheme())
 	if err != nil {
 		return fmt.Errorf("failed to create mapper for Cluster to HCloudMachines: %w", err)
@@ -62,6 +62,8 @@ type HetznerBareMetalHostReconciler struct {
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalhosts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalhosts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalhosts/finalizers,verbs=update
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
 
 // Reconcile implements the reconcilement of HetznerBareMetalHost objects.
 func (r *HetznerBareMetalHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
@@ -109,6 +111,14 @@ func (r *HetznerBareMetalHostReconciler) Reconcile(ctx context.Context, req ctrl
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// Keep the hardware labels in sync with the hardware details, so that hosts can be selected by them.
+	if host.SetHardwareLabels(bmHost) {
+		if err := r.Update(ctx, bmHost); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update (after SetHardwareLabels): %w", err)
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	// Certain cases need to be handled here and not later in the host state machine.
 	// If res != nil, then we should return, otherwise not.
 	res, err = r.reconcileSelectedStates(ctx, bmHost)
@@ -177,6 +187,14 @@ func (r *HetznerBareMetalHostReconciler) Reconcile(ctx context.Context, req ctrl
 		return res, nil
 	}
 
+	sshJumpHostSecret, res, err := r.getSSHJumpHostSecret(ctx, *secretManager, bmHost, hetznerCluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if res != emptyResult {
+		return res, nil
+	}
+
 	// Create the scope.
 	hostScope, err := scope.NewBareMetalHostScope(scope.BareMetalHostScopeParams{
 		Logger:                  log,
@@ -189,6 +207,7 @@ func (r *HetznerBareMetalHostReconciler) Reconcile(ctx context.Context, req ctrl
 		SSHClientFactory:        r.SSHClientFactory,
 		OSSSHSecret:             osSSHSecret,
 		RescueSSHSecret:         rescueSSHSecret,
+		SSHJumpHostSecret:       sshJumpHostSecret,
 		SecretManager:           secretManager,
 	})
 	if err != nil {
@@ -225,6 +244,11 @@ func (r *HetznerBareMetalHostReconciler) reconcileSelectedStates(ctx context.Con
 			needsUpdate = true
 		} else if bmHost.NeedsProvisioning() {
 			bmHost.Spec.Status.ProvisioningState = infrav1.StatePreparing
+			if bmHost.Spec.ExternallyProvisioned {
+				bmHost.Spec.Status.ProvisioningState = infrav1.StateAdopting
+				// the host key of the running operating system gets pinned on the first connection
+				bmHost.Spec.Status.SSHStatus.OSHostKeyFingerprint = ""
+			}
 			needsUpdate = true
 		}
 		if needsUpdate {
@@ -316,6 +340,45 @@ func (r *HetznerBareMetalHostReconciler) getSecrets(
 	return osSSHSecret, rescueSSHSecret, res, nil
 }
 
+// getSSHJumpHostSecret returns the secret with the ssh key of the jump host, or nil if the host is connected directly.
+func (r *HetznerBareMetalHostReconciler) getSSHJumpHostSecret(
+	ctx context.Context,
+	secretManager secretutil.SecretManager,
+	bmHost *infrav1.HetznerBareMetalHost,
+	hetznerCluster *infrav1.HetznerCluster,
+) (*corev1.Secret, ctrl.Result, error) {
+	jumpHost := bmHost.GetSSHJumpHost(hetznerCluster)
+	if jumpHost == nil || bmHost.Spec.Status.SSHSpec == nil {
+		return nil, reconcile.Result{}, nil
+	}
+
+	secretNamespacedName := types.NamespacedName{Namespace: bmHost.Namespace, Name: jumpHost.SecretRef.Name}
+	secret, err := secretManager.ObtainSecret(ctx, secretNamespacedName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			msg := fmt.Sprintf("%s: %s", infrav1.ErrorMessageMissingSSHJumpHostSecret, err.Error())
+			conditions.MarkFalse(
+				bmHost,
+				infrav1.CredentialsAvailableCondition,
+				infrav1.SSHJumpHostSecretMissingReason,
+				clusterv1.ConditionSeverityError,
+				"%s",
+				msg,
+			)
+			record.Warnf(bmHost, infrav1.SSHJumpHostSecretMissingReason, msg)
+			conditions.SetSummary(bmHost)
+			result, err := host.SaveHostAndReturn(ctx, r.Client, bmHost)
+			if result != (reconcile.Result{}) || err != nil {
+				return nil, result, err
+			}
+
+			return nil, reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+		}
+		return nil, reconcile.Result{}, fmt.Errorf("failed to get secret of ssh jump host: %w", err)
+	}
+	return secret, reconcile.Result{}, nil
+}
+
 func getAndValidateRobotCredentials(
 	ctx context.Context,
 	namespace string,
@@ -339,22 +402,27 @@ func getAndValidateRobotCredentials(
 		return robotclient.Credentials{}, err
 	}
 
+	return validateRobotCredentials(hetznerSecret, hetznerCluster.Spec.HetznerSecret)
+}
+
+// validateRobotCredentials returns the credentials of the robot API that are stored in the secret.
+func validateRobotCredentials(hetznerSecret *corev1.Secret, secretRef infrav1.HetznerSecretRef) (robotclient.Credentials, error) {
 	creds := robotclient.Credentials{
-		Username: string(hetznerSecret.Data[hetznerCluster.Spec.HetznerSecret.Key.HetznerRobotUser]),
-		Password: string(hetznerSecret.Data[hetznerCluster.Spec.HetznerSecret.Key.HetznerRobotPassword]),
+		Username: string(hetznerSecret.Data[secretRef.Key.HetznerRobotUser]),
+		Password: string(hetznerSecret.Data[secretRef.Key.HetznerRobotPassword]),
 	}
 
 	// Validate token
 	if creds.Username == "" {
 		return robotclient.Credentials{}, &bmclient.CredentialsValidationError{
 			Message: fmt.Sprintf("secret %s/%s: Missing Hetzner robot api connection detail '%s' in credentials",
-				namespace, hetznerCluster.Spec.HetznerSecret.Name, hetznerCluster.Spec.HetznerSecret.Key.HetznerRobotUser),
+				hetznerSecret.Namespace, hetznerSecret.Name, secretRef.Key.HetznerRobotUser),
 		}
 	}
 	if creds.Password == "" {
 		return robotclient.Credentials{}, &bmclient.CredentialsValidationError{
 			Message: fmt.Sprintf("secret %s/%s: Missing Hetzner robot api connection detail '%s' in credentials",
-				namespace, hetznerCluster.Spec.HetznerSecret.Name, hetznerCluster.Spec.HetznerSecret.Key.HetznerRobotPassword),
+				hetznerSecret.Namespace, hetznerSecret.Name, secretRef.Key.HetznerRobotPassword),
 		}
 	}
 
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	bmclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/inventory"
+)
+
+// HetznerBareMetalInventoryReconciler reconciles a HetznerBareMetalInventory object.
+type HetznerBareMetalInventoryReconciler struct {
+	client.Client
+	APIReader          client.Reader
+	RobotClientFactory robotclient.Factory
+	WatchFilterValue   string
+}
+
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalinventories,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalinventories/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerbaremetalinventories/finalizers,verbs=update
+
+// Reconcile creates HetznerBareMetalHosts for the servers of Hetzner Robot that match the inventory.
+func (r *HetznerBareMetalInventoryReconciler) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	bareMetalInventory := &infrav1.HetznerBareMetalInventory{}
+	if err := r.Get(ctx, req.NamespacedName, bareMetalInventory); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !bareMetalInventory.DeletionTimestamp.IsZero() {
+		// The hosts are not deleted together with the inventory, as they might be in use.
+		return reconcile.Result{}, nil
+	}
+
+	log = log.WithValues("HetznerBareMetalInventory", klog.KObj(bareMetalInventory))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	robotCreds, err := r.getRobotCredentials(ctx, bareMetalInventory)
+	if err != nil {
+		return r.robotCredentialsErrorResult(ctx, err, bareMetalInventory)
+	}
+
+	inventoryScope, err := scope.NewBareMetalInventoryScope(scope.BareMetalInventoryScopeParams{
+		Logger:             log,
+		Client:             r.Client,
+		RobotClient:        r.RobotClientFactory.NewClient(robotCreds),
+		BareMetalInventory: bareMetalInventory,
+	})
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to create scope: %w", err)
+	}
+
+	// Always close the scope when exiting this function so we can persist any HetznerBareMetalInventory changes.
+	defer func() {
+		conditions.SetSummary(bareMetalInventory)
+		if err := inventoryScope.Close(ctx, patch.WithStatusObservedGeneration{}); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	return inventory.NewService(inventoryScope).Reconcile(ctx)
+}
+
+// getRobotCredentials reads the credentials of the robot API from the secret of the inventory. Unlike for
+// HetznerClusters, the secret is not claimed, as it is usually shared with clusters.
+func (r *HetznerBareMetalInventoryReconciler) getRobotCredentials(ctx context.Context, bareMetalInventory *infrav1.HetznerBareMetalInventory) (robotclient.Credentials, error) {
+	secretNamespacedName := types.NamespacedName{Namespace: bareMetalInventory.Namespace, Name: bareMetalInventory.Spec.HetznerSecret.Name}
+
+	var hetznerSecret corev1.Secret
+	if err := r.APIReader.Get(ctx, secretNamespacedName, &hetznerSecret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return robotclient.Credentials{},
+				&secretutil.ResolveSecretRefError{Message: fmt.Sprintf("The Hetzner secret %s does not exist", secretNamespacedName)}
+		}
+		return robotclient.Credentials{}, err
+	}
+
+	return validateRobotCredentials(&hetznerSecret, bareMetalInventory.Spec.HetznerSecret)
+}
+
+func (r *HetznerBareMetalInventoryReconciler) robotCredentialsErrorResult(
+	ctx context.Context,
+	err error,
+	bareMetalInventory *infrav1.HetznerBareMetalInventory,
+) (reconcile.Result, error) {
+	conditions.MarkFalse(bareMetalInventory,
+		infrav1.InventorySyncedCondition,
+		infrav1.RobotCredentialsInvalidReason,
+		clusterv1.ConditionSeverityError,
+		"%s",
+		err.Error(),
+	)
+	conditions.SetSummary(bareMetalInventory)
+	if err := r.Client.Status().Update(ctx, bareMetalInventory); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update: %w", err)
+	}
+
+	// We requeue if the secret cannot be found, as we will not know if it is created at some point in the future.
+	var resolveErr *secretutil.ResolveSecretRefError
+	if errors.As(err, &resolveErr) {
+		return reconcile.Result{RequeueAfter: secretErrorRetryDelay}, nil
+	}
+
+	// The secret is not watched, therefore we requeue also if the credentials are incomplete.
+	var validationErr *bmclient.CredentialsValidationError
+	if errors.As(err, &validationErr) {
+		return reconcile.Result{RequeueAfter: bareMetalInventory.Spec.SyncInterval.Duration}, nil
+	}
+	return reconcile.Result{}, fmt.Errorf("failed to get robot credentials: %w", err)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HetznerBareMetalInventoryReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.HetznerBareMetalInventory{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+}
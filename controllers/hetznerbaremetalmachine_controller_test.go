@@ -741,6 +741,13 @@ var _ = Describe("HetznerBareMetalMachineReconciler", func() {
 				Expect(testEnv.Cleanup(ctx, testNs, hbmmt)).To(Succeed())
 			})
 
+			It("should not allow creation without partitions for installimage", func() {
+				hbmmtWithoutPartitions := hbmmt.DeepCopy()
+				hbmmtWithoutPartitions.ObjectMeta = metav1.ObjectMeta{Name: bmMachineName + "-no-partitions", Namespace: testNs.Name}
+				hbmmtWithoutPartitions.Spec.Template.Spec.InstallImage.Partitions = nil
+				Expect(testEnv.Client.Create(ctx, hbmmtWithoutPartitions)).ToNot(Succeed())
+			})
+
 			It("should not allow update of InstallImage", func() {
 				Expect(testEnv.Get(ctx, key, hbmmt)).To(Succeed())
 
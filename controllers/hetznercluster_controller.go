@@ -59,10 +59,16 @@ import (
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	bmclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/vswitch"
 	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/firewall"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/floatingip"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/loadbalancer"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/network"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/placementgroup"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/primaryip"
 )
 
 const (
@@ -75,6 +81,7 @@ type HetznerClusterReconciler struct {
 	RateLimitWaitTime              time.Duration
 	APIReader                      client.Reader
 	HCloudClientFactory            hcloudclient.Factory
+	RobotClientFactory             robotclient.Factory
 	targetClusterManagersStopCh    map[types.NamespacedName]chan struct{}
 	targetClusterManagersLock      sync.Mutex
 	TargetClusterManagersWaitGroup *sync.WaitGroup
@@ -86,6 +93,7 @@ type HetznerClusterReconciler struct {
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hetznerclusters/finalizers,verbs=update
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=hcloudmachines,verbs=get;list;watch
 
 // Reconcile manages the lifecycle of a HetznerCluster object.
 func (r *HetznerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -132,6 +140,16 @@ func (r *HetznerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 	hcloudClient := r.HCloudClientFactory.NewClient(hcloudToken)
 
+	// the robot client is only needed to route the failover IP of the control plane and to manage the vSwitch
+	var robotClient robotclient.Client
+	if needsRobotClient(hetznerCluster) {
+		robotCreds, err := getAndValidateRobotCredentials(ctx, req.Namespace, hetznerCluster, secretManager)
+		if err != nil {
+			return robotCredentialsErrorResult(ctx, err, hetznerCluster, r.Client)
+		}
+		robotClient = r.RobotClientFactory.NewClient(robotCreds)
+	}
+
 	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
 		Client:         r.Client,
 		APIReader:      r.APIReader,
@@ -139,6 +157,7 @@ func (r *HetznerClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		Cluster:        cluster,
 		HetznerCluster: hetznerCluster,
 		HCloudClient:   hcloudClient,
+		RobotClient:    robotClient,
 		HetznerSecret:  hetznerSecret,
 	})
 	if err != nil {
@@ -189,6 +208,11 @@ func (r *HetznerClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 	// set failure domains in status using information in spec
 	clusterScope.SetStatusFailureDomain(clusterScope.GetSpecRegion())
 
+	// reconcile the vSwitch before the network, so that it can be linked to the network
+	if err := vswitch.NewService(clusterScope).Reconcile(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile vSwitch for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// reconcile the network
 	if err := network.NewService(clusterScope).Reconcile(ctx); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile network for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
@@ -210,6 +234,21 @@ func (r *HetznerClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile placement groups for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// reconcile the firewalls
+	if err := firewall.NewService(clusterScope).Reconcile(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile firewalls for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// reconcile the pool of primary IPs
+	if err := primaryip.NewService(clusterScope).Reconcile(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile primary IP pool for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// reconcile the floating IP of the control plane
+	if err := floatingip.NewService(clusterScope).Reconcile(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to reconcile control plane floating IP for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	processControlPlaneEndpoint(hetznerCluster)
 
 	// delete deprecated conditions of old clusters
@@ -246,10 +285,47 @@ func (r *HetznerClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 	// target cluster secret is ready
 	conditions.MarkTrue(hetznerCluster, infrav1.TargetClusterSecretReadyCondition)
 
+	// the API server behind the floating IP has to be checked regularly
+	if hetznerCluster.Spec.ControlPlaneFloatingIP != nil {
+		return reconcile.Result{RequeueAfter: floatingip.HealthCheckInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
 func processControlPlaneEndpoint(hetznerCluster *infrav1.HetznerCluster) {
+	if floatingIP := hetznerCluster.Spec.ControlPlaneFloatingIP; floatingIP != nil {
+		if hetznerCluster.Status.ControlPlaneFloatingIP != nil && hetznerCluster.Status.ControlPlaneFloatingIP.IP != "" {
+			defaultHost := hetznerCluster.Status.ControlPlaneFloatingIP.IP
+			defaultPort := int32(floatingIP.Port) //nolint:gosec // Validation for the port range (1 to 65535) is already done via kubebuilder.
+
+			if hetznerCluster.Spec.ControlPlaneEndpoint == nil {
+				hetznerCluster.Spec.ControlPlaneEndpoint = &clusterv1.APIEndpoint{
+					Host: defaultHost,
+					Port: defaultPort,
+				}
+			} else {
+				if hetznerCluster.Spec.ControlPlaneEndpoint.Host == "" {
+					hetznerCluster.Spec.ControlPlaneEndpoint.Host = defaultHost
+				}
+				if hetznerCluster.Spec.ControlPlaneEndpoint.Port == 0 {
+					hetznerCluster.Spec.ControlPlaneEndpoint.Port = defaultPort
+				}
+			}
+			conditions.MarkTrue(hetznerCluster, infrav1.ControlPlaneEndpointSetCondition)
+			hetznerCluster.Status.Ready = true
+		} else {
+			const msg = "enabled floating IP but floating IP not ready yet"
+			conditions.MarkFalse(hetznerCluster,
+				infrav1.ControlPlaneEndpointSetCondition,
+				infrav1.ControlPlaneEndpointNotSetReason,
+				clusterv1.ConditionSeverityWarning,
+				msg)
+			hetznerCluster.Status.Ready = false
+		}
+		return
+	}
+
 	if hetznerCluster.Spec.ControlPlaneLoadBalancer.Enabled {
 		if hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4 != "<nil>" {
 			defaultHost := hetznerCluster.Status.ControlPlaneLoadBalancer.IPv4
@@ -351,11 +427,31 @@ func (r *HetznerClusterReconciler) reconcileDelete(ctx context.Context, clusterS
 		return reconcile.Result{}, fmt.Errorf("failed to delete network for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// delete the vSwitch
+	if err := vswitch.NewService(clusterScope).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete vSwitch for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// delete the placement groups
 	if err := placementgroup.NewService(clusterScope).Delete(ctx); err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to delete placement groups for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
 	}
 
+	// delete the firewalls
+	if err := firewall.NewService(clusterScope).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete firewalls for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// delete the floating IP of the control plane
+	if err := floatingip.NewService(clusterScope).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete control plane floating IP for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
+	// delete the pool of primary IPs
+	if err := primaryip.NewService(clusterScope).Delete(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to delete primary IP pool for HetznerCluster %s/%s: %w", hetznerCluster.Namespace, hetznerCluster.Name, err)
+	}
+
 	// Stop CSR manager
 	r.targetClusterManagersLock.Lock()
 	defer r.targetClusterManagersLock.Unlock()
@@ -468,6 +564,43 @@ func hcloudTokenErrorResult(
 	return res, err
 }
 
+func robotCredentialsErrorResult(
+	ctx context.Context,
+	err error,
+	hetznerCluster *infrav1.HetznerCluster,
+	client client.Client,
+) (res ctrl.Result, reterr error) {
+	// the robot client is used for the failover IP of the control plane and for the vSwitch
+	conditionType := infrav1.ControlPlaneFloatingIPReadyCondition
+	if hetznerCluster.Spec.ControlPlaneFloatingIP == nil {
+		conditionType = infrav1.VSwitchReadyCondition
+	}
+	conditions.MarkFalse(hetznerCluster,
+		conditionType,
+		infrav1.RobotCredentialsInvalidReason,
+		clusterv1.ConditionSeverityError,
+		"%s",
+		err.Error(),
+	)
+	conditions.SetSummary(hetznerCluster)
+	if err := client.Status().Update(ctx, hetznerCluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update: %w", err)
+	}
+
+	// We requeue if the secret cannot be found, as we will not know if it is created at some point in the future.
+	var resolveErr *secretutil.ResolveSecretRefError
+	if errors.As(err, &resolveErr) {
+		return ctrl.Result{RequeueAfter: secretErrorRetryDelay}, nil
+	}
+
+	// No need to reconcile again if credentials are missing, as it will be triggered as soon as the secret is updated.
+	var validationErr *bmclient.CredentialsValidationError
+	if errors.As(err, &validationErr) {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, fmt.Errorf("failed to get robot credentials: %w", err)
+}
+
 func reconcileTargetSecret(ctx context.Context, clusterScope *scope.ClusterScope) (res reconcile.Result, reterr error) {
 	// Checking if control plane is ready
 	clientConfig, err := clusterScope.ClientConfig(ctx)
@@ -752,6 +885,11 @@ func (r *HetznerClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 			handler.EnqueueRequestsFromMapFunc(r.clusterToHetznerCluster),
 			builder.WithPredicates(IgnoreInsignificantClusterStatusUpdates(log)),
 		).
+		Watches(
+			&infrav1.HetznerBareMetalHost{},
+			handler.EnqueueRequestsFromMapFunc(r.bareMetalHostToHetznerCluster),
+			builder.WithPredicates(vSwitchRelevantHostChanges()),
+		).
 		Complete(r)
 	if err != nil {
 		return fmt.Errorf("error creating controller: %w", err)
@@ -798,6 +936,55 @@ func (r *HetznerClusterReconciler) clusterToHetznerCluster(ctx context.Context,
 	}
 }
 
+// needsRobotClient returns true if the HetznerCluster uses resources of Hetzner Robot.
+func needsRobotClient(hetznerCluster *infrav1.HetznerCluster) bool {
+	if floatingIP := hetznerCluster.Spec.ControlPlaneFloatingIP; floatingIP != nil && floatingIP.Type == infrav1.ControlPlaneFloatingIPTypeRobot {
+		return true
+	}
+	return hetznerCluster.Spec.HCloudNetwork.Enabled && hetznerCluster.Spec.HCloudNetwork.VSwitch != nil
+}
+
+// bareMetalHostToHetznerCluster maps a HetznerBareMetalHost to the HetznerCluster that uses it, so that the
+// server gets connected to the vSwitch of the cluster.
+func (r *HetznerClusterReconciler) bareMetalHostToHetznerCluster(_ context.Context, o client.Object) []reconcile.Request {
+	host, ok := o.(*infrav1.HetznerBareMetalHost)
+	if !ok {
+		panic(fmt.Sprintf("Expected a HetznerBareMetalHost but got a %T", o))
+	}
+
+	if host.Spec.Status.HetznerClusterRef == "" {
+		return nil
+	}
+
+	return []ctrl.Request{
+		{
+			NamespacedName: client.ObjectKey{Namespace: host.Namespace, Name: host.Spec.Status.HetznerClusterRef},
+		},
+	}
+}
+
+// vSwitchRelevantHostChanges is a predicate that only lets pass changes of HetznerBareMetalHosts that affect
+// the servers connected to the vSwitch.
+func vSwitchRelevantHostChanges() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldHost, ok := e.ObjectOld.(*infrav1.HetznerBareMetalHost)
+			if !ok {
+				return true
+			}
+			newHost, ok := e.ObjectNew.(*infrav1.HetznerBareMetalHost)
+			if !ok {
+				return true
+			}
+			return (oldHost.Spec.ConsumerRef == nil) != (newHost.Spec.ConsumerRef == nil) ||
+				oldHost.Spec.Status.HetznerClusterRef != newHost.Spec.Status.HetznerClusterRef
+		},
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
 // IgnoreInsignificantClusterStatusUpdates is a predicate used for ignoring insignificant HetznerCluster.Status updates.
 func IgnoreInsignificantClusterStatusUpdates(logger logr.Logger) predicate.Funcs {
 	return predicate.Funcs{
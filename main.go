@@ -77,6 +77,7 @@ var (
 	logLevel                           string
 	syncPeriod                         time.Duration
 	rateLimitWaitTime                  time.Duration
+	hcloudClientCacheTTL               time.Duration
 )
 
 func main() {
@@ -95,6 +96,7 @@ func main() {
 	fs.StringVar(&logLevel, "log-level", "info", "Specifies log level. Options are 'debug', 'info' and 'error'")
 	fs.DurationVar(&syncPeriod, "sync-period", 3*time.Minute, "The minimum interval at which watched resources are reconciled (e.g. 3m)")
 	fs.DurationVar(&rateLimitWaitTime, "rate-limit", 5*time.Minute, "The rate limiting for HCloud controller (e.g. 5m)")
+	fs.DurationVar(&hcloudClientCacheTTL, "hcloud-client-cache-ttl", 0, "Duration for which results of HCloud read calls like server types, SSH keys, images and servers are cached (e.g. 1m). Caching is disabled if zero.")
 	fs.BoolVar(&hcloudclient.DebugAPICalls, "debug-hcloud-api-calls", false, "Debug all calls to the hcloud API.")
 
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
@@ -139,6 +141,9 @@ func main() {
 	ctx := ctrl.SetupSignalHandler()
 
 	hcloudClientFactory := hcloudclient.NewFactory()
+	if hcloudClientCacheTTL > 0 {
+		hcloudClientFactory = hcloudclient.NewCachingFactory(hcloudClientFactory, hcloudClientCacheTTL)
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -148,6 +153,7 @@ func main() {
 		APIReader:                      mgr.GetAPIReader(),
 		RateLimitWaitTime:              rateLimitWaitTime,
 		HCloudClientFactory:            hcloudClientFactory,
+		RobotClientFactory:             robotclient.NewFactory(),
 		WatchFilterValue:               watchFilterValue,
 		DisableCSRApproval:             disableCSRApproval,
 		TargetClusterManagersWaitGroup: &wg,
@@ -209,6 +215,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.HetznerBareMetalInventoryReconciler{
+		Client:             mgr.GetClient(),
+		APIReader:          mgr.GetAPIReader(),
+		RobotClientFactory: robotclient.NewFactory(),
+		WatchFilterValue:   watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HetznerBareMetalInventory")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.HCloudRemediationReconciler{
 		Client:              mgr.GetClient(),
 		APIReader:           mgr.GetAPIReader(),
@@ -282,6 +298,10 @@ func setUpWebhookWithManager(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "HetznerBareMetalRemediationTemplate")
 		os.Exit(1)
 	}
+	if err := (&infrastructurev1beta1.HetznerBareMetalInventory{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "HetznerBareMetalInventory")
+		os.Exit(1)
+	}
 	if err := (&infrastructurev1beta1.HCloudRemediation{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "HCloudRemediation")
 		os.Exit(1)
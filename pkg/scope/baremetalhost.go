@@ -46,6 +46,7 @@ type BareMetalHostScopeParams struct {
 	SSHClientFactory        sshclient.Factory
 	OSSSHSecret             *corev1.Secret
 	RescueSSHSecret         *corev1.Secret
+	SSHJumpHostSecret       *corev1.Secret
 	SecretManager           *secretutil.SecretManager
 }
 
@@ -90,6 +91,7 @@ func NewBareMetalHostScope(params BareMetalHostScopeParams) (*BareMetalHostScope
 		HetznerBareMetalMachine: params.HetznerBareMetalMachine,
 		OSSSHSecret:             params.OSSSHSecret,
 		RescueSSHSecret:         params.RescueSSHSecret,
+		SSHJumpHostSecret:       params.SSHJumpHostSecret,
 		SecretManager:           params.SecretManager,
 	}, nil
 }
@@ -107,6 +109,7 @@ type BareMetalHostScope struct {
 	Cluster                 *clusterv1.Cluster
 	OSSSHSecret             *corev1.Secret
 	RescueSSHSecret         *corev1.Secret
+	SSHJumpHostSecret       *corev1.Secret
 }
 
 // Name returns the HetznerCluster name.
@@ -141,7 +144,10 @@ func (s *BareMetalHostScope) GetRawBootstrapData(ctx context.Context) ([]byte, e
 
 // Hostname returns the desired host name.
 func (s *BareMetalHostScope) Hostname() (hostname string) {
-	if s.hasConstantHostname() {
+	if s.HetznerBareMetalHost.Spec.Status.AdoptedHostname != "" {
+		// an adopted server keeps its hostname
+		hostname = s.HetznerBareMetalHost.Spec.Status.AdoptedHostname
+	} else if s.hasConstantHostname() {
 		hostname = fmt.Sprintf("%s%s-%v", infrav1.BareMetalHostNamePrefix, s.Cluster.Name, s.HetznerBareMetalHost.Spec.ServerID)
 	} else {
 		hostname = infrav1.BareMetalHostNamePrefix + s.HetznerBareMetalHost.Spec.ConsumerRef.Name
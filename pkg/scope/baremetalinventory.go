@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
+)
+
+// BareMetalInventoryScopeParams defines the input parameters used to create a new Scope.
+type BareMetalInventoryScopeParams struct {
+	Logger             logr.Logger
+	Client             client.Client
+	RobotClient        robotclient.Client
+	BareMetalInventory *infrav1.HetznerBareMetalInventory
+}
+
+// NewBareMetalInventoryScope creates a new Scope from the supplied parameters.
+// This is meant to be called for each reconcile iteration.
+func NewBareMetalInventoryScope(params BareMetalInventoryScopeParams) (*BareMetalInventoryScope, error) {
+	if params.BareMetalInventory == nil {
+		return nil, errors.New("failed to generate new scope from nil BareMetalInventory")
+	}
+	if params.Client == nil {
+		return nil, errors.New("cannot create baremetal inventory scope without client")
+	}
+	if params.RobotClient == nil {
+		return nil, errors.New("cannot create baremetal inventory scope without robot client")
+	}
+
+	patchHelper, err := patch.NewHelper(params.BareMetalInventory, params.Client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init patch helper: %w", err)
+	}
+
+	return &BareMetalInventoryScope{
+		Logger:             params.Logger,
+		Client:             params.Client,
+		RobotClient:        params.RobotClient,
+		patchHelper:        patchHelper,
+		BareMetalInventory: params.BareMetalInventory,
+	}, nil
+}
+
+// BareMetalInventoryScope defines the basic context for an actuator to operate upon.
+type BareMetalInventoryScope struct {
+	logr.Logger
+	Client             client.Client
+	RobotClient        robotclient.Client
+	patchHelper        *patch.Helper
+	BareMetalInventory *infrav1.HetznerBareMetalInventory
+}
+
+// Close closes the current scope persisting the inventory status.
+func (s *BareMetalInventoryScope) Close(ctx context.Context, opts ...patch.Option) error {
+	return s.patchHelper.Patch(ctx, s.BareMetalInventory, opts...)
+}
+
+// Name returns the BareMetalInventory name.
+func (s *BareMetalInventoryScope) Name() string {
+	return s.BareMetalInventory.Name
+}
+
+// Namespace returns the namespace name.
+func (s *BareMetalInventoryScope) Namespace() string {
+	return s.BareMetalInventory.Namespace
+}
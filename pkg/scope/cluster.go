@@ -34,6 +34,7 @@ import (
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
 	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
 )
 
@@ -44,8 +45,11 @@ type ClusterScopeParams struct {
 	Logger         logr.Logger
 	HetznerSecret  *corev1.Secret
 	HCloudClient   hcloudclient.Client
+	RobotClient    robotclient.Client
 	Cluster        *clusterv1.Cluster
 	HetznerCluster *infrav1.HetznerCluster
+	// WorkloadClientCache is optional. If it is set, clients of the workload cluster are taken from it.
+	WorkloadClientCache *WorkloadClientCache
 }
 
 // NewClusterScope creates a new Scope from the supplied parameters.
@@ -81,8 +85,11 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 		Cluster:        params.Cluster,
 		HetznerCluster: params.HetznerCluster,
 		HCloudClient:   params.HCloudClient,
+		RobotClient:    params.RobotClient,
 		patchHelper:    helper,
 		hetznerSecret:  params.HetznerSecret,
+
+		workloadClientCache: params.WorkloadClientCache,
 	}, nil
 }
 
@@ -94,7 +101,11 @@ type ClusterScope struct {
 	patchHelper   *patch.Helper
 	hetznerSecret *corev1.Secret
 
+	workloadClientCache *WorkloadClientCache
+
 	HCloudClient hcloudclient.Client
+	// RobotClient is only set if the cluster uses a failover IP of Hetzner Robot as control plane endpoint.
+	RobotClient robotclient.Client
 
 	Cluster        *clusterv1.Cluster
 	HetznerCluster *infrav1.HetznerCluster
@@ -148,6 +159,15 @@ func (s *ClusterScope) ControlPlaneAPIEndpointPort() int32 {
 
 // ClientConfig return a kubernetes client config for the cluster context.
 func (s *ClusterScope) ClientConfig(ctx context.Context) (clientcmd.ClientConfig, error) {
+	kubeconfig, err := s.kubeconfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.NewClientConfigFromBytes(kubeconfig)
+}
+
+// kubeconfig returns the kubeconfig of the workload cluster.
+func (s *ClusterScope) kubeconfig(ctx context.Context) ([]byte, error) {
 	cluster := client.ObjectKey{
 		Name:      fmt.Sprintf("%s-%s", s.Cluster.Name, secret.Kubeconfig),
 		Namespace: s.Cluster.Namespace,
@@ -158,11 +178,11 @@ func (s *ClusterScope) ClientConfig(ctx context.Context) (clientcmd.ClientConfig
 	if err != nil {
 		return nil, fmt.Errorf("failed to acquire secret: %w", err)
 	}
-	kubeconfigBytes, ok := kubeconfigSecret.Data[secret.KubeconfigDataName]
+	kubeconfig, ok := kubeconfigSecret.Data[secret.KubeconfigDataName]
 	if !ok {
 		return nil, fmt.Errorf("missing key %q in secret data", secret.KubeconfigDataName)
 	}
-	return clientcmd.NewClientConfigFromBytes(kubeconfigBytes)
+	return kubeconfig, nil
 }
 
 // ClientConfigWithAPIEndpoint returns a client config.
@@ -184,6 +204,20 @@ func (s *ClusterScope) ClientConfigWithAPIEndpoint(ctx context.Context, endpoint
 	return clientcmd.NewDefaultClientConfig(raw, &clientcmd.ConfigOverrides{}), nil
 }
 
+// WorkloadClient returns a client for the workload cluster. The client is taken from the cache of workload
+// clients if the scope has one.
+func (s *ClusterScope) WorkloadClient(ctx context.Context) (client.Client, error) {
+	kubeconfig, err := s.kubeconfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.workloadClientCache == nil {
+		return newWorkloadClient(kubeconfig)
+	}
+	return s.workloadClientCache.get(client.ObjectKeyFromObject(s.Cluster), kubeconfig)
+}
+
 // ListMachines returns HCloudMachines.
 func (s *ClusterScope) ListMachines(ctx context.Context) ([]*clusterv1.Machine, []*infrav1.HCloudMachine, error) {
 	// get and index Machines by HCloudMachine name
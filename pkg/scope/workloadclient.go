@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WorkloadClientCache caches the clients of workload clusters across reconciliations, so that the REST mapper
+// of a client does not have to be set up again for every reconciliation. A client is created again if the
+// kubeconfig of the workload cluster changes.
+type WorkloadClientCache struct {
+	lock    sync.Mutex
+	clients map[types.NamespacedName]cachedWorkloadClient
+}
+
+type cachedWorkloadClient struct {
+	kubeconfig []byte
+	client     client.Client
+}
+
+// NewWorkloadClientCache creates an empty cache of workload cluster clients.
+func NewWorkloadClientCache() *WorkloadClientCache {
+	return &WorkloadClientCache{
+		clients: make(map[types.NamespacedName]cachedWorkloadClient),
+	}
+}
+
+// get returns the cached client of the cluster or creates a new one with the given kubeconfig.
+func (c *WorkloadClientCache) get(cluster types.NamespacedName, kubeconfig []byte) (client.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cached, found := c.clients[cluster]; found && bytes.Equal(cached.kubeconfig, kubeconfig) {
+		return cached.client, nil
+	}
+
+	workloadClient, err := newWorkloadClient(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	c.clients[cluster] = cachedWorkloadClient{kubeconfig: kubeconfig, client: workloadClient}
+	return workloadClient, nil
+}
+
+// Delete removes the client of the cluster from the cache.
+func (c *WorkloadClientCache) Delete(cluster types.NamespacedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.clients, cluster)
+}
+
+func newWorkloadClient(kubeconfig []byte) (client.Client, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rest config: %w", err)
+	}
+
+	return client.New(restConfig, client.Options{})
+}
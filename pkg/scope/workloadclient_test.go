@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testKubeconfig(server string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: workload
+  cluster:
+    server: %s
+users:
+- name: admin
+  user:
+    token: token
+contexts:
+- name: workload
+  context:
+    cluster: workload
+    user: admin
+current-context: workload
+`, server))
+}
+
+var _ = Describe("WorkloadClientCache", func() {
+	cluster := types.NamespacedName{Name: "cluster", Namespace: "default"}
+
+	It("reuses the client as long as the kubeconfig does not change", func() {
+		cache := NewWorkloadClientCache()
+
+		first, err := cache.get(cluster, testKubeconfig("https://192.0.2.1:6443"))
+		Expect(err).To(Succeed())
+
+		second, err := cache.get(cluster, testKubeconfig("https://192.0.2.1:6443"))
+		Expect(err).To(Succeed())
+		Expect(second).To(BeIdenticalTo(first))
+
+		third, err := cache.get(cluster, testKubeconfig("https://192.0.2.2:6443"))
+		Expect(err).To(Succeed())
+		Expect(third).ToNot(BeIdenticalTo(first))
+	})
+
+	It("creates a new client after the cluster has been deleted", func() {
+		cache := NewWorkloadClientCache()
+
+		first, err := cache.get(cluster, testKubeconfig("https://192.0.2.1:6443"))
+		Expect(err).To(Succeed())
+
+		cache.Delete(cluster)
+
+		second, err := cache.get(cluster, testKubeconfig("https://192.0.2.1:6443"))
+		Expect(err).To(Succeed())
+		Expect(second).ToNot(BeIdenticalTo(first))
+	})
+
+	It("fails for invalid kubeconfigs", func() {
+		_, err := NewWorkloadClientCache().get(cluster, []byte("invalid"))
+		Expect(err).ToNot(Succeed())
+	})
+})
@@ -19,10 +19,8 @@ package baremetal
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
 	"fmt"
-	"math/big"
 	"net"
 	"strings"
 	"time"
@@ -173,6 +171,7 @@ func (s *Service) Delete(ctx context.Context) (res reconcile.Result, err error)
 
 		// deprovisiong is done - remove all references of host
 		host.Spec.ConsumerRef = nil
+		host.Spec.Status.ReleasedAt = ptr.To(metav1.Now())
 		host.Spec.Status.HetznerClusterRef = ""
 		host.SetDeletionTimestamp(nil)
 		host.OwnerReferences = s.removeOwnerRef(host.OwnerReferences)
@@ -251,6 +250,9 @@ func (s *Service) update(ctx context.Context) error {
 		s.scope.BareMetalMachine.Status.FailureReason = nil
 	}
 
+	// pass a requested reprovisioning on to the host
+	s.reconcileReprovision(host)
+
 	// ensure that the references are correctly set on host
 	s.setReferencesOnHost(host)
 
@@ -392,6 +394,11 @@ func (s *Service) chooseHost(ctx context.Context) (
 
 	labelSelector := s.getLabelSelector()
 
+	hwReq, err := newHardwareRequirements(s.scope.BareMetalMachine.Spec.HostSelector.HardwareRequirements)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
 	// count all hosts that are not in use already
 	unusedHostsCounter := 0
 
@@ -416,7 +423,7 @@ func (s *Service) chooseHost(ctx context.Context) (
 		// from now on each "continue" should add an entry
 		// to mapOfSkipReasons.
 		unusedHostsCounter++
-		if s.skipHost(labelSelector, host, mapOfSkipReasons) {
+		if s.skipHost(labelSelector, hwReq, host, mapOfSkipReasons) {
 			continue
 		}
 
@@ -447,13 +454,11 @@ func (s *Service) chooseHost(ctx context.Context) (
 	}
 
 	// we found available hosts - choose one
-	randomNumber, err := rand.Int(rand.Reader, big.NewInt(int64(len(availableHosts))))
+	chosenHost, err := selectHost(s.scope.BareMetalMachine.Spec.HostSelector.Policy, availableHosts)
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("failed to create random number: %w", err)
+		return nil, nil, "", err
 	}
 
-	chosenHost := availableHosts[randomNumber.Int64()]
-
 	helper, err := patch.NewHelper(chosenHost, s.scope.Client)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("failed to create patch helper: %w", err)
@@ -462,7 +467,7 @@ func (s *Service) chooseHost(ctx context.Context) (
 	return chosenHost, helper, "", nil
 }
 
-func (s *Service) skipHost(labelSelector labels.Selector, host infrav1.HetznerBareMetalHost, mapOfSkipReasons map[string]int) bool {
+func (s *Service) skipHost(labelSelector labels.Selector, hwReq *hardwareRequirements, host infrav1.HetznerBareMetalHost, mapOfSkipReasons map[string]int) bool {
 	// This comes first, because we should not look too deep into machines
 	// which are not in our scope.
 	if !labelSelector.Matches(labels.Set(host.ObjectMeta.Labels)) {
@@ -489,6 +494,18 @@ func (s *Service) skipHost(labelSelector labels.Selector, host infrav1.HetznerBa
 		return true
 	}
 
+	// Hosts without hardware details are skipped, as it cannot be known whether they fulfill the requirements.
+	if hwReq != nil {
+		if host.Spec.Status.HardwareDetails == nil {
+			mapOfSkipReasons["hbmh-has-unknown-hardware-details"]++
+			return true
+		}
+		if hwReq.mismatch(host.Spec.Status.HardwareDetails) != "" {
+			mapOfSkipReasons["hbmh-does-not-fulfill-hardware-requirements"]++
+			return true
+		}
+	}
+
 	if host.Spec.RootDeviceHints == nil ||
 		(host.Spec.RootDeviceHints.WWN == "" && len(host.Spec.RootDeviceHints.Raid.WWN) == 0) {
 		// Even if there are no rootDeviceHints specified, the host should be picked.
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"cmp"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+// hardwareRequirements are the hardware requirements of a machine with the compiled regular expression of the
+// CPU model, so that it does not have to be compiled for every host.
+type hardwareRequirements struct {
+	*infrav1.HardwareRequirements
+	cpuModel *regexp.Regexp
+}
+
+// newHardwareRequirements compiles the hardware requirements. It returns nil if there are no requirements.
+func newHardwareRequirements(req *infrav1.HardwareRequirements) (*hardwareRequirements, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	compiled := &hardwareRequirements{HardwareRequirements: req}
+	if req.CPUModelRegex != "" {
+		re, err := regexp.Compile(req.CPUModelRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU model regex %q: %w", req.CPUModelRegex, err)
+		}
+		compiled.cpuModel = re
+	}
+	return compiled, nil
+}
+
+// mismatch returns the reasons why the hardware details do not fulfill the requirements.
+// The result is empty if all requirements are fulfilled.
+func (req *hardwareRequirements) mismatch(details *infrav1.HardwareDetails) string {
+	var reasons []string
+
+	if details.RAMGB < req.MinRAMGB {
+		reasons = append(reasons, fmt.Sprintf("RAM %d GB < %d GB", details.RAMGB, req.MinRAMGB))
+	}
+	if details.CPU.Cores < req.MinCPUCores {
+		reasons = append(reasons, fmt.Sprintf("CPU cores %d < %d", details.CPU.Cores, req.MinCPUCores))
+	}
+	if details.CPU.Threads < req.MinCPUThreads {
+		reasons = append(reasons, fmt.Sprintf("CPU threads %d < %d", details.CPU.Threads, req.MinCPUThreads))
+	}
+	if req.CPUArch != "" && details.CPU.Arch != req.CPUArch {
+		reasons = append(reasons, fmt.Sprintf("CPU arch %q != %q", details.CPU.Arch, req.CPUArch))
+	}
+	if req.cpuModel != nil && !req.cpuModel.MatchString(details.CPU.Model) {
+		reasons = append(reasons, fmt.Sprintf("CPU model %q does not match %q", details.CPU.Model, req.CPUModelRegex))
+	}
+
+	if req.MinDiskCount > 0 {
+		diskType := req.DiskType
+		if diskType == "" {
+			diskType = infrav1.DiskTypeAny
+		}
+		var diskCount int
+		for _, disk := range details.Storage {
+			if disk.MatchesDiskType(diskType) && int(disk.SizeGB) >= req.MinDiskSizeGB {
+				diskCount++
+			}
+		}
+		if diskCount < req.MinDiskCount {
+			reasons = append(reasons, fmt.Sprintf("disks of type %s with at least %d GB %d < %d",
+				diskType, req.MinDiskSizeGB, diskCount, req.MinDiskCount))
+		}
+	}
+
+	if req.MinNICSpeedMbps > 0 {
+		var maxSpeed int
+		for _, nic := range details.NIC {
+			maxSpeed = max(maxSpeed, nic.SpeedMbps)
+		}
+		if maxSpeed < req.MinNICSpeedMbps {
+			reasons = append(reasons, fmt.Sprintf("NIC speed %d Mbps < %d Mbps", maxSpeed, req.MinNICSpeedMbps))
+		}
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+// selectHost chooses one of the available hosts according to the policy.
+func selectHost(policy infrav1.HostSelectionPolicy, availableHosts []*infrav1.HetznerBareMetalHost) (*infrav1.HetznerBareMetalHost, error) {
+	switch policy {
+	case infrav1.HostSelectionPolicySmallestSufficient:
+		return slices.MinFunc(availableHosts, compareHostSize), nil
+
+	case infrav1.HostSelectionPolicyLeastRecentlyUsed:
+		return slices.MinFunc(availableHosts, compareHostReleasedAt), nil
+	}
+
+	randomNumber, err := rand.Int(rand.Reader, big.NewInt(int64(len(availableHosts))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create random number: %w", err)
+	}
+	return availableHosts[randomNumber.Int64()], nil
+}
+
+// compareHostSize orders hosts by RAM, CPU threads and storage. Hosts without hardware details come last.
+func compareHostSize(a, b *infrav1.HetznerBareMetalHost) int {
+	detailsA, detailsB := a.Spec.Status.HardwareDetails, b.Spec.Status.HardwareDetails
+	switch {
+	case detailsA == nil && detailsB == nil:
+		return strings.Compare(a.Name, b.Name)
+	case detailsA == nil:
+		return 1
+	case detailsB == nil:
+		return -1
+	}
+
+	return cmp.Or(
+		cmp.Compare(detailsA.RAMGB, detailsB.RAMGB),
+		cmp.Compare(detailsA.CPU.Threads, detailsB.CPU.Threads),
+		cmp.Compare(storageSize(detailsA.Storage), storageSize(detailsB.Storage)),
+		strings.Compare(a.Name, b.Name),
+	)
+}
+
+// compareHostReleasedAt orders hosts by the time they were released. Hosts that have never been used come first.
+func compareHostReleasedAt(a, b *infrav1.HetznerBareMetalHost) int {
+	releasedA, releasedB := a.Spec.Status.ReleasedAt, b.Spec.Status.ReleasedAt
+	switch {
+	case releasedA == nil && releasedB == nil:
+		return strings.Compare(a.Name, b.Name)
+	case releasedA == nil:
+		return -1
+	case releasedB == nil:
+		return 1
+	}
+
+	return cmp.Or(releasedA.Time.Compare(releasedB.Time), strings.Compare(a.Name, b.Name))
+}
+
+func storageSize(storage []infrav1.Storage) infrav1.Capacity {
+	var size infrav1.Capacity
+	for _, disk := range storage {
+		size += disk.SizeBytes
+	}
+	return size
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+func hostWithHardware(name string, ramGB, threads int, storage ...infrav1.Storage) *infrav1.HetznerBareMetalHost {
+	return &infrav1.HetznerBareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: infrav1.HetznerBareMetalHostSpec{
+			Status: infrav1.ControllerGeneratedStatus{
+				ProvisioningState: infrav1.StateNone,
+				HardwareDetails: &infrav1.HardwareDetails{
+					RAMGB:   ramGB,
+					CPU:     infrav1.CPU{Arch: "x86_64", Model: "AMD Ryzen 9 5950X 16-Core Processor", Cores: threads / 2, Threads: threads},
+					NIC:     []infrav1.NIC{{Name: "eth0", SpeedMbps: 1000}},
+					Storage: storage,
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("hardwareRequirements", func() {
+	details := hostWithHardware("host", 64, 32,
+		infrav1.Storage{SizeGB: 512, WWN: "eui.1"},
+		infrav1.Storage{SizeGB: 512, WWN: "eui.2"},
+		infrav1.Storage{SizeGB: 4000, WWN: "0x1", HCTL: "0:0:0:0", Rota: true},
+	).Spec.Status.HardwareDetails
+
+	DescribeTable("mismatch",
+		func(req infrav1.HardwareRequirements, expectedMismatch string) {
+			hwReq, err := newHardwareRequirements(&req)
+			Expect(err).To(Succeed())
+			Expect(hwReq.mismatch(details)).To(Equal(expectedMismatch))
+		},
+		Entry("no requirements", infrav1.HardwareRequirements{}, ""),
+		Entry("all requirements fulfilled", infrav1.HardwareRequirements{
+			MinRAMGB:        64,
+			MinCPUCores:     16,
+			MinCPUThreads:   32,
+			CPUArch:         "x86_64",
+			CPUModelRegex:   "^AMD Ryzen 9",
+			MinDiskCount:    2,
+			MinDiskSizeGB:   500,
+			DiskType:        infrav1.DiskTypeNVMe,
+			MinNICSpeedMbps: 1000,
+		}, ""),
+		Entry("not enough RAM and wrong CPU model", infrav1.HardwareRequirements{
+			MinRAMGB:      128,
+			CPUModelRegex: "EPYC",
+		}, `RAM 64 GB < 128 GB, CPU model "AMD Ryzen 9 5950X 16-Core Processor" does not match "EPYC"`),
+		Entry("not enough disks", infrav1.HardwareRequirements{
+			MinDiskCount: 2,
+			DiskType:     infrav1.DiskTypeHDD,
+		}, "disks of type HDD with at least 0 GB 1 < 2"),
+		Entry("NIC too slow", infrav1.HardwareRequirements{
+			MinNICSpeedMbps: 10000,
+		}, "NIC speed 1000 Mbps < 10000 Mbps"),
+	)
+
+	It("fails for an invalid CPU model regex", func() {
+		_, err := newHardwareRequirements(&infrav1.HardwareRequirements{CPUModelRegex: "AMD Ryzen("})
+		Expect(err).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("chooseHost with hardware requirements", func() {
+	small := hostWithHardware("small", 64, 16)
+	medium := hostWithHardware("medium", 128, 32)
+	large := hostWithHardware("large", 256, 64)
+	unknown := &infrav1.HetznerBareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{Name: "unknown", Namespace: "default"},
+		Spec:       infrav1.HetznerBareMetalHostSpec{Status: infrav1.ControllerGeneratedStatus{ProvisioningState: infrav1.StateNone}},
+	}
+
+	chooseHost := func(hostSelector infrav1.HostSelector, hosts ...client.Object) (*infrav1.HetznerBareMetalHost, string) {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(hosts...).Build()
+		bmMachine := &infrav1.HetznerBareMetalMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "bm-machine", Namespace: "default"},
+			Spec:       infrav1.HetznerBareMetalMachineSpec{HostSelector: hostSelector},
+		}
+
+		host, _, reason, err := newTestService(bmMachine, c).chooseHost(context.Background())
+		Expect(err).To(Succeed())
+		return host, reason
+	}
+
+	It("chooses the smallest host that fulfills the requirements", func() {
+		host, _ := chooseHost(infrav1.HostSelector{
+			HardwareRequirements: &infrav1.HardwareRequirements{MinRAMGB: 100},
+			Policy:               infrav1.HostSelectionPolicySmallestSufficient,
+		}, small.DeepCopy(), large.DeepCopy(), medium.DeepCopy(), unknown.DeepCopy())
+		Expect(host.Name).To(Equal("medium"))
+	})
+
+	It("does not choose hosts with unknown hardware", func() {
+		host, reason := chooseHost(infrav1.HostSelector{
+			HardwareRequirements: &infrav1.HardwareRequirements{MinRAMGB: 512},
+			Policy:               infrav1.HostSelectionPolicySmallestSufficient,
+		}, small.DeepCopy(), large.DeepCopy(), unknown.DeepCopy())
+		Expect(host).To(BeNil())
+		Expect(reason).To(Equal("No available host of 3 found: hbmh-does-not-fulfill-hardware-requirements: 2, hbmh-has-unknown-hardware-details: 1"))
+	})
+
+	It("chooses hosts with unknown hardware if there are no hardware requirements", func() {
+		host, _ := chooseHost(infrav1.HostSelector{}, unknown.DeepCopy())
+		Expect(host.Name).To(Equal("unknown"))
+	})
+
+	It("gives a reason if no host fulfills the requirements", func() {
+		host, reason := chooseHost(infrav1.HostSelector{
+			HardwareRequirements: &infrav1.HardwareRequirements{MinRAMGB: 512},
+		}, small.DeepCopy(), large.DeepCopy())
+		Expect(host).To(BeNil())
+		Expect(reason).To(Equal("No available host of 2 found: hbmh-does-not-fulfill-hardware-requirements: 2"))
+	})
+
+	It("chooses the least recently used host", func() {
+		releasedLongAgo := small.DeepCopy()
+		releasedLongAgo.Spec.Status.ReleasedAt = ptr.To(metav1.NewTime(time.Now().Add(-time.Hour)))
+		releasedRecently := medium.DeepCopy()
+		releasedRecently.Spec.Status.ReleasedAt = ptr.To(metav1.Now())
+
+		host, _ := chooseHost(infrav1.HostSelector{Policy: infrav1.HostSelectionPolicyLeastRecentlyUsed},
+			releasedRecently, releasedLongAgo)
+		Expect(host.Name).To(Equal("small"))
+
+		host, _ = chooseHost(infrav1.HostSelector{Policy: infrav1.HostSelectionPolicyLeastRecentlyUsed},
+			releasedRecently, releasedLongAgo, large.DeepCopy())
+		Expect(host.Name).To(Equal("large"))
+	})
+})
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package baremetal
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+// reconcileReprovision passes a reprovisioning that has been requested with the ReprovisionAnnotation on to the
+// host once the host is provisioned. The host gets the current image and user data of the machine. The progress
+// is reported in the ReprovisionSucceeded condition until the host is provisioned again.
+func (s *Service) reconcileReprovision(host *infrav1.HetznerBareMetalHost) {
+	bmMachine := s.scope.BareMetalMachine
+	state := host.Spec.Status.ProvisioningState
+	_, hostRequested := host.Annotations[infrav1.ReprovisionAnnotation]
+
+	if _, requested := bmMachine.Annotations[infrav1.ReprovisionAnnotation]; requested {
+		if state != infrav1.StateProvisioned {
+			conditions.MarkFalse(
+				bmMachine,
+				infrav1.ReprovisionSucceededCondition,
+				infrav1.ReprovisionPendingReason,
+				clusterv1.ConditionSeverityInfo,
+				"waiting for host %s to be provisioned, current state %q",
+				host.Name,
+				state,
+			)
+			return
+		}
+
+		if host.Annotations == nil {
+			host.Annotations = make(map[string]string)
+		}
+		host.Annotations[infrav1.ReprovisionAnnotation] = ""
+		host.Spec.Status.InstallImage = &bmMachine.Spec.InstallImage
+		if s.scope.Machine.Spec.Bootstrap.DataSecretName != nil {
+			host.Spec.Status.UserData = &corev1.SecretReference{Namespace: s.scope.Namespace(), Name: *s.scope.Machine.Spec.Bootstrap.DataSecretName}
+		}
+		delete(bmMachine.Annotations, infrav1.ReprovisionAnnotation)
+
+		conditions.MarkFalse(
+			bmMachine,
+			infrav1.ReprovisionSucceededCondition,
+			infrav1.ReprovisioningReason,
+			clusterv1.ConditionSeverityInfo,
+			"reprovisioning host %s",
+			host.Name,
+		)
+		record.Eventf(bmMachine, "ReprovisioningStarted", "Reprovisioning host %s with image %s", host.Name, bmMachine.Spec.InstallImage.Image.String())
+		return
+	}
+
+	if conditions.GetReason(bmMachine, infrav1.ReprovisionSucceededCondition) != infrav1.ReprovisioningReason ||
+		!conditions.IsFalse(bmMachine, infrav1.ReprovisionSucceededCondition) {
+		return
+	}
+
+	if state == infrav1.StateProvisioned && !hostRequested {
+		conditions.MarkTrue(bmMachine, infrav1.ReprovisionSucceededCondition)
+		record.Eventf(bmMachine, "ReprovisioningSucceeded", "Host %s has been reprovisioned", host.Name)
+		return
+	}
+
+	conditions.MarkFalse(
+		bmMachine,
+		infrav1.ReprovisionSucceededCondition,
+		infrav1.ReprovisioningReason,
+		clusterv1.ConditionSeverityInfo,
+		"reprovisioning host %s, current state %q",
+		host.Name,
+		state,
+	)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package baremetal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+var _ = Describe("reconcileReprovision", func() {
+	var (
+		bmMachine *infrav1.HetznerBareMetalMachine
+		host      *infrav1.HetznerBareMetalHost
+		service   *Service
+	)
+
+	BeforeEach(func() {
+		bmMachine = &infrav1.HetznerBareMetalMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "bm-machine",
+				Namespace:   "default",
+				Annotations: map[string]string{infrav1.ReprovisionAnnotation: ""},
+			},
+			Spec: infrav1.HetznerBareMetalMachineSpec{
+				InstallImage: infrav1.InstallImage{Image: infrav1.Image{Name: "ubuntu-24.04", URL: "https://example.com/ubuntu-24.04.tar.gz"}},
+			},
+		}
+		host = &infrav1.HetznerBareMetalHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: "default"},
+			Spec: infrav1.HetznerBareMetalHostSpec{
+				Status: infrav1.ControllerGeneratedStatus{
+					ProvisioningState: infrav1.StateProvisioned,
+					InstallImage:      &infrav1.InstallImage{Image: infrav1.Image{Name: "ubuntu-22.04", URL: "https://example.com/ubuntu-22.04.tar.gz"}},
+				},
+			},
+		}
+		service = newTestService(bmMachine, nil)
+		service.scope.Machine = &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap-data-new")}},
+		}
+	})
+
+	It("waits until the host is provisioned", func() {
+		host.Spec.Status.ProvisioningState = infrav1.StateEnsureProvisioned
+
+		service.reconcileReprovision(host)
+
+		Expect(bmMachine.Annotations).To(HaveKey(infrav1.ReprovisionAnnotation))
+		Expect(host.Annotations).ToNot(HaveKey(infrav1.ReprovisionAnnotation))
+		Expect(conditions.GetReason(bmMachine, infrav1.ReprovisionSucceededCondition)).To(Equal(infrav1.ReprovisionPendingReason))
+	})
+
+	It("passes the request with the current image and user data on to the host and reports the progress", func() {
+		service.reconcileReprovision(host)
+
+		Expect(bmMachine.Annotations).ToNot(HaveKey(infrav1.ReprovisionAnnotation))
+		Expect(host.Annotations).To(HaveKey(infrav1.ReprovisionAnnotation))
+		Expect(host.Spec.Status.InstallImage.Image.Name).To(Equal("ubuntu-24.04"))
+		Expect(host.Spec.Status.UserData.Name).To(Equal("bootstrap-data-new"))
+		Expect(conditions.GetReason(bmMachine, infrav1.ReprovisionSucceededCondition)).To(Equal(infrav1.ReprovisioningReason))
+
+		// the host has not picked up the request yet
+		service.reconcileReprovision(host)
+		Expect(conditions.IsFalse(bmMachine, infrav1.ReprovisionSucceededCondition)).To(BeTrue())
+
+		delete(host.Annotations, infrav1.ReprovisionAnnotation)
+		host.Spec.Status.ProvisioningState = infrav1.StateImageInstalling
+		service.reconcileReprovision(host)
+		Expect(conditions.GetMessage(bmMachine, infrav1.ReprovisionSucceededCondition)).To(ContainSubstring(string(infrav1.StateImageInstalling)))
+
+		host.Spec.Status.ProvisioningState = infrav1.StateProvisioned
+		service.reconcileReprovision(host)
+		Expect(conditions.IsTrue(bmMachine, infrav1.ReprovisionSucceededCondition)).To(BeTrue())
+	})
+
+	It("does nothing without a request", func() {
+		delete(bmMachine.Annotations, infrav1.ReprovisionAnnotation)
+
+		service.reconcileReprovision(host)
+
+		Expect(host.Annotations).ToNot(HaveKey(infrav1.ReprovisionAnnotation))
+		Expect(conditions.Get(bmMachine, infrav1.ReprovisionSucceededCondition)).To(BeNil())
+	})
+})
@@ -7,6 +7,7 @@ import (
 	models "github.com/syself/hrobot-go/models"
 
 	v1beta1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -22,6 +23,216 @@ func (_m *Client) EXPECT() *Client_Expecter {
 	return &Client_Expecter{mock: &_m.Mock}
 }
 
+// AddServersToVSwitch provides a mock function with given fields: id, serverIDs
+func (_m *Client) AddServersToVSwitch(id int, serverIDs []int) error {
+	ret := _m.Called(id, serverIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddServersToVSwitch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []int) error); ok {
+		r0 = rf(id, serverIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_AddServersToVSwitch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddServersToVSwitch'
+type Client_AddServersToVSwitch_Call struct {
+	*mock.Call
+}
+
+// AddServersToVSwitch is a helper method to define mock.On call
+//   - id int
+//   - serverIDs []int
+func (_e *Client_Expecter) AddServersToVSwitch(id interface{}, serverIDs interface{}) *Client_AddServersToVSwitch_Call {
+	return &Client_AddServersToVSwitch_Call{Call: _e.mock.On("AddServersToVSwitch", id, serverIDs)}
+}
+
+func (_c *Client_AddServersToVSwitch_Call) Run(run func(id int, serverIDs []int)) *Client_AddServersToVSwitch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].([]int))
+	})
+	return _c
+}
+
+func (_c *Client_AddServersToVSwitch_Call) Return(_a0 error) *Client_AddServersToVSwitch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_AddServersToVSwitch_Call) RunAndReturn(run func(int, []int) error) *Client_AddServersToVSwitch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelVSwitch provides a mock function with given fields: id
+func (_m *Client) CancelVSwitch(id int) error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelVSwitch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_CancelVSwitch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelVSwitch'
+type Client_CancelVSwitch_Call struct {
+	*mock.Call
+}
+
+// CancelVSwitch is a helper method to define mock.On call
+//   - id int
+func (_e *Client_Expecter) CancelVSwitch(id interface{}) *Client_CancelVSwitch_Call {
+	return &Client_CancelVSwitch_Call{Call: _e.mock.On("CancelVSwitch", id)}
+}
+
+func (_c *Client_CancelVSwitch_Call) Run(run func(id int)) *Client_CancelVSwitch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *Client_CancelVSwitch_Call) Return(_a0 error) *Client_CancelVSwitch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_CancelVSwitch_Call) RunAndReturn(run func(int) error) *Client_CancelVSwitch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateVSwitch provides a mock function with given fields: name, vlanID
+func (_m *Client) CreateVSwitch(name string, vlanID int) (*robotclient.VSwitch, error) {
+	ret := _m.Called(name, vlanID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVSwitch")
+	}
+
+	var r0 *robotclient.VSwitch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) (*robotclient.VSwitch, error)); ok {
+		return rf(name, vlanID)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) *robotclient.VSwitch); ok {
+		r0 = rf(name, vlanID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*robotclient.VSwitch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(name, vlanID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_CreateVSwitch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateVSwitch'
+type Client_CreateVSwitch_Call struct {
+	*mock.Call
+}
+
+// CreateVSwitch is a helper method to define mock.On call
+//   - name string
+//   - vlanID int
+func (_e *Client_Expecter) CreateVSwitch(name interface{}, vlanID interface{}) *Client_CreateVSwitch_Call {
+	return &Client_CreateVSwitch_Call{Call: _e.mock.On("CreateVSwitch", name, vlanID)}
+}
+
+func (_c *Client_CreateVSwitch_Call) Run(run func(name string, vlanID int)) *Client_CreateVSwitch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *Client_CreateVSwitch_Call) Return(_a0 *robotclient.VSwitch, _a1 error) *Client_CreateVSwitch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_CreateVSwitch_Call) RunAndReturn(run func(string, int) (*robotclient.VSwitch, error)) *Client_CreateVSwitch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteBootLinux provides a mock function with given fields: id
+func (_m *Client) DeleteBootLinux(id int) (*models.Linux, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBootLinux")
+	}
+
+	var r0 *models.Linux
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*models.Linux, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) *models.Linux); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Linux)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_DeleteBootLinux_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBootLinux'
+type Client_DeleteBootLinux_Call struct {
+	*mock.Call
+}
+
+// DeleteBootLinux is a helper method to define mock.On call
+//   - id int
+func (_e *Client_Expecter) DeleteBootLinux(id interface{}) *Client_DeleteBootLinux_Call {
+	return &Client_DeleteBootLinux_Call{Call: _e.mock.On("DeleteBootLinux", id)}
+}
+
+func (_c *Client_DeleteBootLinux_Call) Run(run func(id int)) *Client_DeleteBootLinux_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *Client_DeleteBootLinux_Call) Return(_a0 *models.Linux, _a1 error) *Client_DeleteBootLinux_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_DeleteBootLinux_Call) RunAndReturn(run func(int) (*models.Linux, error)) *Client_DeleteBootLinux_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteBootRescue provides a mock function with given fields: id
 func (_m *Client) DeleteBootRescue(id int) (*models.Rescue, error) {
 	ret := _m.Called(id)
@@ -138,6 +349,64 @@ func (_c *Client_GetBMServer_Call) RunAndReturn(run func(int) (*models.Server, e
 	return _c
 }
 
+// GetBootLinux provides a mock function with given fields: id
+func (_m *Client) GetBootLinux(id int) (*models.Linux, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBootLinux")
+	}
+
+	var r0 *models.Linux
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*models.Linux, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) *models.Linux); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Linux)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetBootLinux_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBootLinux'
+type Client_GetBootLinux_Call struct {
+	*mock.Call
+}
+
+// GetBootLinux is a helper method to define mock.On call
+//   - id int
+func (_e *Client_Expecter) GetBootLinux(id interface{}) *Client_GetBootLinux_Call {
+	return &Client_GetBootLinux_Call{Call: _e.mock.On("GetBootLinux", id)}
+}
+
+func (_c *Client_GetBootLinux_Call) Run(run func(id int)) *Client_GetBootLinux_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *Client_GetBootLinux_Call) Return(_a0 *models.Linux, _a1 error) *Client_GetBootLinux_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetBootLinux_Call) RunAndReturn(run func(int) (*models.Linux, error)) *Client_GetBootLinux_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetBootRescue provides a mock function with given fields: id
 func (_m *Client) GetBootRescue(id int) (*models.Rescue, error) {
 	ret := _m.Called(id)
@@ -196,6 +465,64 @@ func (_c *Client_GetBootRescue_Call) RunAndReturn(run func(int) (*models.Rescue,
 	return _c
 }
 
+// GetFailoverIP provides a mock function with given fields: ip
+func (_m *Client) GetFailoverIP(ip string) (*models.Failover, error) {
+	ret := _m.Called(ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFailoverIP")
+	}
+
+	var r0 *models.Failover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*models.Failover, error)); ok {
+		return rf(ip)
+	}
+	if rf, ok := ret.Get(0).(func(string) *models.Failover); ok {
+		r0 = rf(ip)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Failover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetFailoverIP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFailoverIP'
+type Client_GetFailoverIP_Call struct {
+	*mock.Call
+}
+
+// GetFailoverIP is a helper method to define mock.On call
+//   - ip string
+func (_e *Client_Expecter) GetFailoverIP(ip interface{}) *Client_GetFailoverIP_Call {
+	return &Client_GetFailoverIP_Call{Call: _e.mock.On("GetFailoverIP", ip)}
+}
+
+func (_c *Client_GetFailoverIP_Call) Run(run func(ip string)) *Client_GetFailoverIP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Client_GetFailoverIP_Call) Return(_a0 *models.Failover, _a1 error) *Client_GetFailoverIP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetFailoverIP_Call) RunAndReturn(run func(string) (*models.Failover, error)) *Client_GetFailoverIP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetReboot provides a mock function with given fields: _a0
 func (_m *Client) GetReboot(_a0 int) (*models.Reset, error) {
 	ret := _m.Called(_a0)
@@ -254,6 +581,64 @@ func (_c *Client_GetReboot_Call) RunAndReturn(run func(int) (*models.Reset, erro
 	return _c
 }
 
+// GetVSwitch provides a mock function with given fields: id
+func (_m *Client) GetVSwitch(id int) (*robotclient.VSwitch, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVSwitch")
+	}
+
+	var r0 *robotclient.VSwitch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) (*robotclient.VSwitch, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(int) *robotclient.VSwitch); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*robotclient.VSwitch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetVSwitch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVSwitch'
+type Client_GetVSwitch_Call struct {
+	*mock.Call
+}
+
+// GetVSwitch is a helper method to define mock.On call
+//   - id int
+func (_e *Client_Expecter) GetVSwitch(id interface{}) *Client_GetVSwitch_Call {
+	return &Client_GetVSwitch_Call{Call: _e.mock.On("GetVSwitch", id)}
+}
+
+func (_c *Client_GetVSwitch_Call) Run(run func(id int)) *Client_GetVSwitch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *Client_GetVSwitch_Call) Return(_a0 *robotclient.VSwitch, _a1 error) *Client_GetVSwitch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetVSwitch_Call) RunAndReturn(run func(int) (*robotclient.VSwitch, error)) *Client_GetVSwitch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListBMServers provides a mock function with given fields:
 func (_m *Client) ListBMServers() ([]models.Server, error) {
 	ret := _m.Called()
@@ -368,6 +753,63 @@ func (_c *Client_ListSSHKeys_Call) RunAndReturn(run func() ([]models.Key, error)
 	return _c
 }
 
+// ListVSwitches provides a mock function with given fields:
+func (_m *Client) ListVSwitches() ([]robotclient.VSwitch, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListVSwitches")
+	}
+
+	var r0 []robotclient.VSwitch
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]robotclient.VSwitch, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []robotclient.VSwitch); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]robotclient.VSwitch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ListVSwitches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListVSwitches'
+type Client_ListVSwitches_Call struct {
+	*mock.Call
+}
+
+// ListVSwitches is a helper method to define mock.On call
+func (_e *Client_Expecter) ListVSwitches() *Client_ListVSwitches_Call {
+	return &Client_ListVSwitches_Call{Call: _e.mock.On("ListVSwitches")}
+}
+
+func (_c *Client_ListVSwitches_Call) Run(run func()) *Client_ListVSwitches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_ListVSwitches_Call) Return(_a0 []robotclient.VSwitch, _a1 error) *Client_ListVSwitches_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ListVSwitches_Call) RunAndReturn(run func() ([]robotclient.VSwitch, error)) *Client_ListVSwitches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RebootBMServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) RebootBMServer(_a0 int, _a1 v1beta1.RebootType) (*models.ResetPost, error) {
 	ret := _m.Called(_a0, _a1)
@@ -427,6 +869,53 @@ func (_c *Client_RebootBMServer_Call) RunAndReturn(run func(int, v1beta1.RebootT
 	return _c
 }
 
+// RemoveServersFromVSwitch provides a mock function with given fields: id, serverIDs
+func (_m *Client) RemoveServersFromVSwitch(id int, serverIDs []int) error {
+	ret := _m.Called(id, serverIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveServersFromVSwitch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, []int) error); ok {
+		r0 = rf(id, serverIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_RemoveServersFromVSwitch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveServersFromVSwitch'
+type Client_RemoveServersFromVSwitch_Call struct {
+	*mock.Call
+}
+
+// RemoveServersFromVSwitch is a helper method to define mock.On call
+//   - id int
+//   - serverIDs []int
+func (_e *Client_Expecter) RemoveServersFromVSwitch(id interface{}, serverIDs interface{}) *Client_RemoveServersFromVSwitch_Call {
+	return &Client_RemoveServersFromVSwitch_Call{Call: _e.mock.On("RemoveServersFromVSwitch", id, serverIDs)}
+}
+
+func (_c *Client_RemoveServersFromVSwitch_Call) Run(run func(id int, serverIDs []int)) *Client_RemoveServersFromVSwitch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].([]int))
+	})
+	return _c
+}
+
+func (_c *Client_RemoveServersFromVSwitch_Call) Return(_a0 error) *Client_RemoveServersFromVSwitch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_RemoveServersFromVSwitch_Call) RunAndReturn(run func(int, []int) error) *Client_RemoveServersFromVSwitch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetBMServerName provides a mock function with given fields: _a0, _a1
 func (_m *Client) SetBMServerName(_a0 int, _a1 string) (*models.Server, error) {
 	ret := _m.Called(_a0, _a1)
@@ -486,6 +975,67 @@ func (_c *Client_SetBMServerName_Call) RunAndReturn(run func(int, string) (*mode
 	return _c
 }
 
+// SetBootLinux provides a mock function with given fields: id, dist, lang, fingerprint
+func (_m *Client) SetBootLinux(id int, dist string, lang string, fingerprint string) (*models.Linux, error) {
+	ret := _m.Called(id, dist, lang, fingerprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBootLinux")
+	}
+
+	var r0 *models.Linux
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, string, string, string) (*models.Linux, error)); ok {
+		return rf(id, dist, lang, fingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(int, string, string, string) *models.Linux); ok {
+		r0 = rf(id, dist, lang, fingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Linux)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, string, string, string) error); ok {
+		r1 = rf(id, dist, lang, fingerprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_SetBootLinux_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetBootLinux'
+type Client_SetBootLinux_Call struct {
+	*mock.Call
+}
+
+// SetBootLinux is a helper method to define mock.On call
+//   - id int
+//   - dist string
+//   - lang string
+//   - fingerprint string
+func (_e *Client_Expecter) SetBootLinux(id interface{}, dist interface{}, lang interface{}, fingerprint interface{}) *Client_SetBootLinux_Call {
+	return &Client_SetBootLinux_Call{Call: _e.mock.On("SetBootLinux", id, dist, lang, fingerprint)}
+}
+
+func (_c *Client_SetBootLinux_Call) Run(run func(id int, dist string, lang string, fingerprint string)) *Client_SetBootLinux_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Client_SetBootLinux_Call) Return(_a0 *models.Linux, _a1 error) *Client_SetBootLinux_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_SetBootLinux_Call) RunAndReturn(run func(int, string, string, string) (*models.Linux, error)) *Client_SetBootLinux_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetBootRescue provides a mock function with given fields: id, fingerprint
 func (_m *Client) SetBootRescue(id int, fingerprint string) (*models.Rescue, error) {
 	ret := _m.Called(id, fingerprint)
@@ -545,6 +1095,65 @@ func (_c *Client_SetBootRescue_Call) RunAndReturn(run func(int, string) (*models
 	return _c
 }
 
+// SetFailoverIP provides a mock function with given fields: ip, activeServerIP
+func (_m *Client) SetFailoverIP(ip string, activeServerIP string) (*models.Failover, error) {
+	ret := _m.Called(ip, activeServerIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetFailoverIP")
+	}
+
+	var r0 *models.Failover
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*models.Failover, error)); ok {
+		return rf(ip, activeServerIP)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *models.Failover); ok {
+		r0 = rf(ip, activeServerIP)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Failover)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(ip, activeServerIP)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_SetFailoverIP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetFailoverIP'
+type Client_SetFailoverIP_Call struct {
+	*mock.Call
+}
+
+// SetFailoverIP is a helper method to define mock.On call
+//   - ip string
+//   - activeServerIP string
+func (_e *Client_Expecter) SetFailoverIP(ip interface{}, activeServerIP interface{}) *Client_SetFailoverIP_Call {
+	return &Client_SetFailoverIP_Call{Call: _e.mock.On("SetFailoverIP", ip, activeServerIP)}
+}
+
+func (_c *Client_SetFailoverIP_Call) Run(run func(ip string, activeServerIP string)) *Client_SetFailoverIP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Client_SetFailoverIP_Call) Return(_a0 *models.Failover, _a1 error) *Client_SetFailoverIP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_SetFailoverIP_Call) RunAndReturn(run func(string, string) (*models.Failover, error)) *Client_SetFailoverIP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetSSHKey provides a mock function with given fields: name, publickey
 func (_m *Client) SetSSHKey(name string, publickey string) (*models.Key, error) {
 	ret := _m.Called(name, publickey)
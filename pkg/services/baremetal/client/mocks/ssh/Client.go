@@ -490,6 +490,106 @@ func (_c *Client_ExecuteInstallImage_Call) RunAndReturn(run func(bool) sshclient
 	return _c
 }
 
+// ExecutePostInstallScript provides a mock function with given fields:
+func (_m *Client) ExecutePostInstallScript() sshclient.Output {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecutePostInstallScript")
+	}
+
+	var r0 sshclient.Output
+	if rf, ok := ret.Get(0).(func() sshclient.Output); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sshclient.Output)
+	}
+
+	return r0
+}
+
+// Client_ExecutePostInstallScript_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecutePostInstallScript'
+type Client_ExecutePostInstallScript_Call struct {
+	*mock.Call
+}
+
+// ExecutePostInstallScript is a helper method to define mock.On call
+func (_e *Client_Expecter) ExecutePostInstallScript() *Client_ExecutePostInstallScript_Call {
+	return &Client_ExecutePostInstallScript_Call{Call: _e.mock.On("ExecutePostInstallScript")}
+}
+
+func (_c *Client_ExecutePostInstallScript_Call) Run(run func()) *Client_ExecutePostInstallScript_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_ExecutePostInstallScript_Call) Return(_a0 sshclient.Output) *Client_ExecutePostInstallScript_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_ExecutePostInstallScript_Call) RunAndReturn(run func() sshclient.Output) *Client_ExecutePostInstallScript_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBurnInState provides a mock function with given fields:
+func (_m *Client) GetBurnInState() (sshclient.BurnInState, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBurnInState")
+	}
+
+	var r0 sshclient.BurnInState
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (sshclient.BurnInState, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() sshclient.BurnInState); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sshclient.BurnInState)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetBurnInState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBurnInState'
+type Client_GetBurnInState_Call struct {
+	*mock.Call
+}
+
+// GetBurnInState is a helper method to define mock.On call
+func (_e *Client_Expecter) GetBurnInState() *Client_GetBurnInState_Call {
+	return &Client_GetBurnInState_Call{Call: _e.mock.On("GetBurnInState")}
+}
+
+func (_c *Client_GetBurnInState_Call) Run(run func()) *Client_GetBurnInState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_GetBurnInState_Call) Return(_a0 sshclient.BurnInState, _a1 error) *Client_GetBurnInState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetBurnInState_Call) RunAndReturn(run func() (sshclient.BurnInState, error)) *Client_GetBurnInState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCloudInitOutput provides a mock function with given fields:
 func (_m *Client) GetCloudInitOutput() sshclient.Output {
 	ret := _m.Called()
@@ -1085,6 +1185,113 @@ func (_c *Client_GetInstallImageState_Call) RunAndReturn(run func() (sshclient.I
 	return _c
 }
 
+// GetRaidStatus provides a mock function with given fields:
+func (_m *Client) GetRaidStatus() sshclient.Output {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRaidStatus")
+	}
+
+	var r0 sshclient.Output
+	if rf, ok := ret.Get(0).(func() sshclient.Output); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sshclient.Output)
+	}
+
+	return r0
+}
+
+// Client_GetRaidStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRaidStatus'
+type Client_GetRaidStatus_Call struct {
+	*mock.Call
+}
+
+// GetRaidStatus is a helper method to define mock.On call
+func (_e *Client_Expecter) GetRaidStatus() *Client_GetRaidStatus_Call {
+	return &Client_GetRaidStatus_Call{Call: _e.mock.On("GetRaidStatus")}
+}
+
+func (_c *Client_GetRaidStatus_Call) Run(run func()) *Client_GetRaidStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_GetRaidStatus_Call) Return(_a0 sshclient.Output) *Client_GetRaidStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_GetRaidStatus_Call) RunAndReturn(run func() sshclient.Output) *Client_GetRaidStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetResultOfBurnIn provides a mock function with given fields:
+func (_m *Client) GetResultOfBurnIn() (string, int, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResultOfBurnIn")
+	}
+
+	var r0 string
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func() (string, int, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() int); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Client_GetResultOfBurnIn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResultOfBurnIn'
+type Client_GetResultOfBurnIn_Call struct {
+	*mock.Call
+}
+
+// GetResultOfBurnIn is a helper method to define mock.On call
+func (_e *Client_Expecter) GetResultOfBurnIn() *Client_GetResultOfBurnIn_Call {
+	return &Client_GetResultOfBurnIn_Call{Call: _e.mock.On("GetResultOfBurnIn")}
+}
+
+func (_c *Client_GetResultOfBurnIn_Call) Run(run func()) *Client_GetResultOfBurnIn_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_GetResultOfBurnIn_Call) Return(output string, exitStatus int, err error) *Client_GetResultOfBurnIn_Call {
+	_c.Call.Return(output, exitStatus, err)
+	return _c
+}
+
+func (_c *Client_GetResultOfBurnIn_Call) RunAndReturn(run func() (string, int, error)) *Client_GetResultOfBurnIn_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetResultOfInstallImage provides a mock function with given fields:
 func (_m *Client) GetResultOfInstallImage() (string, error) {
 	ret := _m.Called()
@@ -1140,6 +1347,123 @@ func (_c *Client_GetResultOfInstallImage_Call) RunAndReturn(run func() (string,
 	return _c
 }
 
+// GetResultOfWriteDiskImage provides a mock function with given fields:
+func (_m *Client) GetResultOfWriteDiskImage() (string, int, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResultOfWriteDiskImage")
+	}
+
+	var r0 string
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func() (string, int, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() int); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Client_GetResultOfWriteDiskImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResultOfWriteDiskImage'
+type Client_GetResultOfWriteDiskImage_Call struct {
+	*mock.Call
+}
+
+// GetResultOfWriteDiskImage is a helper method to define mock.On call
+func (_e *Client_Expecter) GetResultOfWriteDiskImage() *Client_GetResultOfWriteDiskImage_Call {
+	return &Client_GetResultOfWriteDiskImage_Call{Call: _e.mock.On("GetResultOfWriteDiskImage")}
+}
+
+func (_c *Client_GetResultOfWriteDiskImage_Call) Run(run func()) *Client_GetResultOfWriteDiskImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_GetResultOfWriteDiskImage_Call) Return(output string, exitStatus int, err error) *Client_GetResultOfWriteDiskImage_Call {
+	_c.Call.Return(output, exitStatus, err)
+	return _c
+}
+
+func (_c *Client_GetResultOfWriteDiskImage_Call) RunAndReturn(run func() (string, int, error)) *Client_GetResultOfWriteDiskImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWriteDiskImageState provides a mock function with given fields:
+func (_m *Client) GetWriteDiskImageState() (sshclient.DiskImageState, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWriteDiskImageState")
+	}
+
+	var r0 sshclient.DiskImageState
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (sshclient.DiskImageState, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() sshclient.DiskImageState); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sshclient.DiskImageState)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_GetWriteDiskImageState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWriteDiskImageState'
+type Client_GetWriteDiskImageState_Call struct {
+	*mock.Call
+}
+
+// GetWriteDiskImageState is a helper method to define mock.On call
+func (_e *Client_Expecter) GetWriteDiskImageState() *Client_GetWriteDiskImageState_Call {
+	return &Client_GetWriteDiskImageState_Call{Call: _e.mock.On("GetWriteDiskImageState")}
+}
+
+func (_c *Client_GetWriteDiskImageState_Call) Run(run func()) *Client_GetWriteDiskImageState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_GetWriteDiskImageState_Call) Return(_a0 sshclient.DiskImageState, _a1 error) *Client_GetWriteDiskImageState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_GetWriteDiskImageState_Call) RunAndReturn(run func() (sshclient.DiskImageState, error)) *Client_GetWriteDiskImageState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Reboot provides a mock function with given fields:
 func (_m *Client) Reboot() sshclient.Output {
 	ret := _m.Called()
@@ -1230,6 +1554,143 @@ func (_c *Client_ResetKubeadm_Call) RunAndReturn(run func() sshclient.Output) *C
 	return _c
 }
 
+// ResetWriteDiskImage provides a mock function with given fields:
+func (_m *Client) ResetWriteDiskImage() sshclient.Output {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetWriteDiskImage")
+	}
+
+	var r0 sshclient.Output
+	if rf, ok := ret.Get(0).(func() sshclient.Output); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(sshclient.Output)
+	}
+
+	return r0
+}
+
+// Client_ResetWriteDiskImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetWriteDiskImage'
+type Client_ResetWriteDiskImage_Call struct {
+	*mock.Call
+}
+
+// ResetWriteDiskImage is a helper method to define mock.On call
+func (_e *Client_Expecter) ResetWriteDiskImage() *Client_ResetWriteDiskImage_Call {
+	return &Client_ResetWriteDiskImage_Call{Call: _e.mock.On("ResetWriteDiskImage")}
+}
+
+func (_c *Client_ResetWriteDiskImage_Call) Run(run func()) *Client_ResetWriteDiskImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Client_ResetWriteDiskImage_Call) Return(_a0 sshclient.Output) *Client_ResetWriteDiskImage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_ResetWriteDiskImage_Call) RunAndReturn(run func() sshclient.Output) *Client_ResetWriteDiskImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartBurnIn provides a mock function with given fields: opts
+func (_m *Client) StartBurnIn(opts sshclient.BurnInOptions) sshclient.Output {
+	ret := _m.Called(opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartBurnIn")
+	}
+
+	var r0 sshclient.Output
+	if rf, ok := ret.Get(0).(func(sshclient.BurnInOptions) sshclient.Output); ok {
+		r0 = rf(opts)
+	} else {
+		r0 = ret.Get(0).(sshclient.Output)
+	}
+
+	return r0
+}
+
+// Client_StartBurnIn_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartBurnIn'
+type Client_StartBurnIn_Call struct {
+	*mock.Call
+}
+
+// StartBurnIn is a helper method to define mock.On call
+//   - opts sshclient.BurnInOptions
+func (_e *Client_Expecter) StartBurnIn(opts interface{}) *Client_StartBurnIn_Call {
+	return &Client_StartBurnIn_Call{Call: _e.mock.On("StartBurnIn", opts)}
+}
+
+func (_c *Client_StartBurnIn_Call) Run(run func(opts sshclient.BurnInOptions)) *Client_StartBurnIn_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(sshclient.BurnInOptions))
+	})
+	return _c
+}
+
+func (_c *Client_StartBurnIn_Call) Return(_a0 sshclient.Output) *Client_StartBurnIn_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_StartBurnIn_Call) RunAndReturn(run func(sshclient.BurnInOptions) sshclient.Output) *Client_StartBurnIn_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartWriteDiskImage provides a mock function with given fields: opts
+func (_m *Client) StartWriteDiskImage(opts sshclient.DiskImageOptions) sshclient.Output {
+	ret := _m.Called(opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartWriteDiskImage")
+	}
+
+	var r0 sshclient.Output
+	if rf, ok := ret.Get(0).(func(sshclient.DiskImageOptions) sshclient.Output); ok {
+		r0 = rf(opts)
+	} else {
+		r0 = ret.Get(0).(sshclient.Output)
+	}
+
+	return r0
+}
+
+// Client_StartWriteDiskImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartWriteDiskImage'
+type Client_StartWriteDiskImage_Call struct {
+	*mock.Call
+}
+
+// StartWriteDiskImage is a helper method to define mock.On call
+//   - opts sshclient.DiskImageOptions
+func (_e *Client_Expecter) StartWriteDiskImage(opts interface{}) *Client_StartWriteDiskImage_Call {
+	return &Client_StartWriteDiskImage_Call{Call: _e.mock.On("StartWriteDiskImage", opts)}
+}
+
+func (_c *Client_StartWriteDiskImage_Call) Run(run func(opts sshclient.DiskImageOptions)) *Client_StartWriteDiskImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(sshclient.DiskImageOptions))
+	})
+	return _c
+}
+
+func (_c *Client_StartWriteDiskImage_Call) Return(_a0 sshclient.Output) *Client_StartWriteDiskImage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_StartWriteDiskImage_Call) RunAndReturn(run func(sshclient.DiskImageOptions) sshclient.Output) *Client_StartWriteDiskImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UntarTGZ provides a mock function with given fields:
 func (_m *Client) UntarTGZ() sshclient.Output {
 	ret := _m.Called()
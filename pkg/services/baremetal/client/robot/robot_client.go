@@ -18,9 +18,15 @@ limitations under the License.
 package robotclient
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"runtime/debug"
+	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
 	hrobot "github.com/syself/hrobot-go"
@@ -30,6 +36,8 @@ import (
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 )
 
+const robotBaseURL = "https://robot-ws.your-server.de"
+
 // Client collects all methods used by the controller in the robot API.
 type Client interface {
 	ValidateCredentials() error
@@ -43,7 +51,34 @@ type Client interface {
 	SetBootRescue(id int, fingerprint string) (*models.Rescue, error)
 	GetBootRescue(id int) (*models.Rescue, error)
 	DeleteBootRescue(id int) (*models.Rescue, error)
+	SetBootLinux(id int, dist, lang, fingerprint string) (*models.Linux, error)
+	GetBootLinux(id int) (*models.Linux, error)
+	DeleteBootLinux(id int) (*models.Linux, error)
 	GetReboot(int) (*models.Reset, error)
+	GetFailoverIP(ip string) (*models.Failover, error)
+	SetFailoverIP(ip, activeServerIP string) (*models.Failover, error)
+	ListVSwitches() ([]VSwitch, error)
+	GetVSwitch(id int) (*VSwitch, error)
+	CreateVSwitch(name string, vlanID int) (*VSwitch, error)
+	CancelVSwitch(id int) error
+	AddServersToVSwitch(id int, serverIDs []int) error
+	RemoveServersFromVSwitch(id int, serverIDs []int) error
+}
+
+// VSwitch is a vSwitch of the Hetzner Robot API.
+type VSwitch struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	VLANID    int             `json:"vlan"`
+	Cancelled bool            `json:"cancelled"`
+	Servers   []VSwitchServer `json:"server"`
+}
+
+// VSwitchServer is a server that is connected to a vSwitch.
+type VSwitchServer struct {
+	ServerIP     string `json:"server_ip"`
+	ServerNumber int    `json:"server_number"`
+	Status       string `json:"status"`
 }
 
 // Factory is the interface for creating new Client objects.
@@ -81,7 +116,10 @@ func (f *factory) NewClient(creds Credentials) Client {
 		},
 	}
 	return &realHetznerRobotClient{
-		client: hrobot.NewBasicAuthClientWithCustomHttpClient(creds.Username, creds.Password, client),
+		client:     hrobot.NewBasicAuthClientWithCustomHttpClient(creds.Username, creds.Password, client),
+		httpClient: client,
+		userName:   creds.Username,
+		password:   creds.Password,
 	}
 }
 
@@ -97,9 +135,10 @@ func NewFactory() Factory {
 var _ = Client(&realHetznerRobotClient{})
 
 type realHetznerRobotClient struct {
-	client   hrobot.RobotClient
-	userName string
-	password string
+	client     hrobot.RobotClient
+	httpClient *http.Client
+	userName   string
+	password   string
 }
 
 func (c *realHetznerRobotClient) UserName() string {
@@ -154,6 +193,131 @@ func (c *realHetznerRobotClient) DeleteBootRescue(id int) (*models.Rescue, error
 	return c.client.BootRescueDelete(id)
 }
 
+func (c *realHetznerRobotClient) SetBootLinux(id int, dist, lang, fingerprint string) (*models.Linux, error) {
+	return c.client.BootLinuxSet(id, &models.LinuxSetInput{Dist: dist, Lang: lang, AuthorizedKey: fingerprint})
+}
+
+func (c *realHetznerRobotClient) GetBootLinux(id int) (*models.Linux, error) {
+	return c.client.BootLinuxGet(id)
+}
+
+func (c *realHetznerRobotClient) DeleteBootLinux(id int) (*models.Linux, error) {
+	return c.client.BootLinuxDelete(id)
+}
+
 func (c *realHetznerRobotClient) GetReboot(id int) (*models.Reset, error) {
 	return c.client.ResetGet(id)
 }
+
+func (c *realHetznerRobotClient) GetFailoverIP(ip string) (*models.Failover, error) {
+	return c.client.FailoverGet(ip)
+}
+
+// SetFailoverIP routes the failover IP to the server with the given main IP.
+func (c *realHetznerRobotClient) SetFailoverIP(ip, activeServerIP string) (*models.Failover, error) {
+	formData := url.Values{}
+	formData.Set("active_server_ip", activeServerIP)
+
+	var failoverResp models.FailoverResponse
+	if err := c.doRequest(http.MethodPost, fmt.Sprintf("/failover/%s", ip), formData, &failoverResp); err != nil {
+		return nil, err
+	}
+	return &failoverResp.Failover, nil
+}
+
+func (c *realHetznerRobotClient) ListVSwitches() ([]VSwitch, error) {
+	var vSwitches []VSwitch
+	if err := c.doRequest(http.MethodGet, "/vswitch", nil, &vSwitches); err != nil {
+		return nil, err
+	}
+	return vSwitches, nil
+}
+
+func (c *realHetznerRobotClient) GetVSwitch(id int) (*VSwitch, error) {
+	var vSwitch VSwitch
+	if err := c.doRequest(http.MethodGet, fmt.Sprintf("/vswitch/%d", id), nil, &vSwitch); err != nil {
+		return nil, err
+	}
+	return &vSwitch, nil
+}
+
+func (c *realHetznerRobotClient) CreateVSwitch(name string, vlanID int) (*VSwitch, error) {
+	formData := url.Values{}
+	formData.Set("name", name)
+	formData.Set("vlan", strconv.Itoa(vlanID))
+
+	var vSwitch VSwitch
+	if err := c.doRequest(http.MethodPost, "/vswitch", formData, &vSwitch); err != nil {
+		return nil, err
+	}
+	return &vSwitch, nil
+}
+
+func (c *realHetznerRobotClient) CancelVSwitch(id int) error {
+	formData := url.Values{}
+	formData.Set("cancellation_date", "now")
+
+	return c.doRequest(http.MethodDelete, fmt.Sprintf("/vswitch/%d", id), formData, nil)
+}
+
+func (c *realHetznerRobotClient) AddServersToVSwitch(id int, serverIDs []int) error {
+	return c.doRequest(http.MethodPost, fmt.Sprintf("/vswitch/%d/server", id), serverFormData(serverIDs), nil)
+}
+
+func (c *realHetznerRobotClient) RemoveServersFromVSwitch(id int, serverIDs []int) error {
+	return c.doRequest(http.MethodDelete, fmt.Sprintf("/vswitch/%d/server", id), serverFormData(serverIDs), nil)
+}
+
+func serverFormData(serverIDs []int) url.Values {
+	formData := url.Values{}
+	for _, id := range serverIDs {
+		formData.Add("server[]", strconv.Itoa(id))
+	}
+	return formData
+}
+
+// doRequest sends a request to the robot API and decodes the response into result, if it is not nil.
+// The hrobot-go library does not support failover routing and vSwitches, therefore these requests are done here.
+func (c *realHetznerRobotClient) doRequest(method, path string, formData url.Values, result interface{}) error {
+	var body io.Reader
+	if formData != nil {
+		body = strings.NewReader(formData.Encode())
+	}
+
+	req, err := http.NewRequest(method, robotBaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if formData != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.SetBasicAuth(c.userName, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errorResponse models.ErrorResponse
+		if err := json.Unmarshal(respBody, &errorResponse); err != nil || errorResponse.Error.Code == "" {
+			return fmt.Errorf("server responded with status code %v", resp.StatusCode)
+		}
+		return errorResponse.Error
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
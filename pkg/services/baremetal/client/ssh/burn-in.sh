@@ -0,0 +1,156 @@
+#!/bin/bash
+
+# Copyright 2024 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# This script gets copied from the controller into the rescue system
+# of the bare-metal machine. It validates the hardware before the image
+# gets installed.
+
+set -uo pipefail
+
+function usage() {
+    echo "$0 [--smart] [--memtester-mb N] [--min-disk-read-mbps N] [--min-nic-speed-mbps N]"
+    echo "    Run burn-in checks. Checks that are not given are skipped."
+    echo "    Every result is printed as one line:"
+    echo "    RESULT<tab>check<tab>target<tab>pass|fail<tab>message"
+    echo "    Exit 0: All checks passed."
+    echo "    Exit 1: At least one check failed."
+    echo "    Exit 3: Some other error (like invalid arguments)"
+}
+
+smart=false
+memtester_mb=0
+min_disk_read_mbps=0
+min_nic_speed_mbps=0
+
+while [ $# -gt 0 ]; do
+    case "$1" in
+    --smart)
+        smart=true
+        ;;
+    --memtester-mb)
+        memtester_mb="$2"
+        shift
+        ;;
+    --min-disk-read-mbps)
+        min_disk_read_mbps="$2"
+        shift
+        ;;
+    --min-nic-speed-mbps)
+        min_nic_speed_mbps="$2"
+        shift
+        ;;
+    *)
+        echo "unknown argument $1"
+        usage
+        exit 3
+        ;;
+    esac
+    shift
+done
+
+failed=false
+
+function result() {
+    local check="$1" target="$2" status="$3" message="$4"
+    if [ "$status" != "pass" ]; then
+        failed=true
+    fi
+    printf 'RESULT\t%s\t%s\t%s\t%s\n' "$check" "$target" "$status" "$(echo "$message" | tr '\t\n' '  ')"
+}
+
+function ensure_installed() {
+    local cmd="$1" package="$2"
+    if ! type "$cmd" >/dev/null 2>&1; then
+        echo "INFO: $cmd not installed yet. Installing $package"
+        DEBIAN_FRONTEND=noninteractive apt-get install -y -qq "$package" >/dev/null 2>&1 || true
+    fi
+}
+
+# print "device target" of all disks. The WWN is used as target, as device names are not stable.
+function disks() {
+    lsblk -dn -o NAME,TYPE,WWN | awk '$2 == "disk" { print $1, ($3 == "" ? $1 : $3) }'
+}
+
+if [ "$smart" = true ]; then
+    ensure_installed smartctl smartmontools
+    while read -r device target; do
+        out=$(smartctl -H "/dev/$device" 2>&1)
+        if echo "$out" | grep -qE 'PASSED|SMART Health Status: OK'; then
+            result smart "$target" pass "SMART health status passed"
+        else
+            result smart "$target" fail "$(echo "$out" | grep -vE '^(smartctl [0-9]|Copyright|=+ START OF|$)' | head -5)"
+        fi
+    done < <(disks)
+fi
+
+if [ "$memtester_mb" -gt 0 ]; then
+    ensure_installed memtester memtester
+    if out=$(memtester "${memtester_mb}M" 1 2>&1); then
+        result memtester "${memtester_mb}M" pass "no errors found"
+    else
+        result memtester "${memtester_mb}M" fail "$(echo "$out" | grep -iE 'fail|error' | head -5)"
+    fi
+fi
+
+if [ "$min_disk_read_mbps" -gt 0 ]; then
+    ensure_installed fio fio
+    ensure_installed jq jq
+    while read -r device target; do
+        bw_kib=$(fio --name=burn-in --filename="/dev/$device" --readonly --rw=read --bs=1M --direct=1 \
+            --ioengine=libaio --iodepth=16 --runtime=30 --time_based --output-format=json 2>/dev/null |
+            jq -r '.jobs[0].read.bw // 0' 2>/dev/null)
+        bw_kib="${bw_kib%.*}"
+        if ! [[ "$bw_kib" =~ ^[0-9]+$ ]]; then
+            result fio "$target" fail "failed to measure sequential read"
+            continue
+        fi
+        mbps=$((bw_kib / 1024))
+        if [ "$mbps" -ge "$min_disk_read_mbps" ]; then
+            result fio "$target" pass "sequential read ${mbps} MB/s"
+        else
+            result fio "$target" fail "sequential read ${mbps} MB/s < ${min_disk_read_mbps} MB/s"
+        fi
+    done < <(disks)
+fi
+
+if [ "$min_nic_speed_mbps" -gt 0 ]; then
+    max_speed=0
+    max_nic=""
+    for nic in /sys/class/net/*; do
+        name=$(basename "$nic")
+        if [ "$name" = lo ] || [ "$(cat "$nic/operstate" 2>/dev/null)" != up ]; then
+            continue
+        fi
+        speed=$(cat "$nic/speed" 2>/dev/null)
+        if ! [[ "$speed" =~ ^[0-9]+$ ]]; then
+            speed=0
+        fi
+        if [ "$speed" -gt "$max_speed" ]; then
+            max_speed="$speed"
+            max_nic="$name"
+        fi
+    done
+    if [ "$max_speed" -ge "$min_nic_speed_mbps" ]; then
+        result nic-speed "$max_nic" pass "link speed ${max_speed} Mbps"
+    else
+        result nic-speed "$max_nic" fail "link speed ${max_speed} Mbps < ${min_nic_speed_mbps} Mbps"
+    fi
+fi
+
+if [ "$failed" = true ]; then
+    exit 1
+fi
+exit 0
@@ -0,0 +1,345 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshclient
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// connIdleTimeout is the time after which an unused connection gets closed. It is long enough to keep the
+// connection open during one reconcile and between reconciles that follow each other quickly.
+const connIdleTimeout = 30 * time.Second
+
+// connPool keeps one SSH connection per server and private key open, so that consecutive commands only
+// need a new session and not a new handshake each.
+type connPool struct {
+	mu    sync.Mutex
+	conns map[connKey]*pooledConn
+}
+
+type connKey struct {
+	address       string
+	user          string
+	privateKeySum [sha256.Size]byte
+
+	// jumpHost is empty if the server is connected directly.
+	jumpHost       string
+	jumpHostKeySum [sha256.Size]byte
+}
+
+// endpoint defines an SSH server together with the credentials to log in.
+type endpoint struct {
+	address       string
+	user          string
+	privateKey    string
+	verifyHostKey func(fingerprint string) error
+}
+
+func newConnKey(target endpoint, jumpHost *JumpHost) connKey {
+	key := connKey{
+		address:       target.address,
+		user:          target.user,
+		privateKeySum: sha256.Sum256([]byte(target.privateKey)),
+	}
+	if jumpHost != nil {
+		key.jumpHost = jumpHost.User + "@" + jumpHost.Address
+		key.jumpHostKeySum = sha256.Sum256([]byte(jumpHost.PrivateKey))
+	}
+	return key
+}
+
+type pooledConn struct {
+	mu          sync.Mutex
+	client      *ssh.Client
+	fingerprint string
+	inUse       int
+	lastUsed    time.Time
+	idleTimer   *time.Timer
+}
+
+func newConnPool() *connPool {
+	return &connPool{conns: make(map[connKey]*pooledConn)}
+}
+
+// get returns a connection to the server. If a jump host is given, the connection is tunneled through a pooled
+// connection to the jump host. Every connection that has been returned has to be released with the returned
+// function. If the connection is broken, it gets closed on release. Errors that occur while connecting can be
+// classified by IsConnectionRefusedError, IsTimeoutError etc.
+func (p *connPool) get(target endpoint, jumpHost *JumpHost) (client *ssh.Client, release func(broken bool), err error) {
+	key := newConnKey(target, jumpHost)
+
+	p.mu.Lock()
+	conn, ok := p.conns[key]
+	if !ok {
+		conn = &pooledConn{}
+		p.conns[key] = conn
+	}
+	p.mu.Unlock()
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.client != nil && !isAlive(conn.client) {
+		conn.client.Close()
+		conn.client = nil
+	}
+
+	if conn.client == nil {
+		var fingerprint string
+		if jumpHost == nil {
+			client, fingerprint, err = dial(target)
+		} else {
+			client, fingerprint, err = p.dialViaJumpHost(target, jumpHost)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		conn.client = client
+		conn.fingerprint = fingerprint
+	} else if target.verifyHostKey != nil {
+		// the host key has been verified when the connection was established, but the caller might have
+		// pinned another host key in between.
+		if err := target.verifyHostKey(conn.fingerprint); err != nil {
+			return nil, nil, fmt.Errorf("failed to dial ssh. Error message: %w. DialErr: %w", err, errSSHDialFailed)
+		}
+	}
+
+	conn.inUse++
+	client = conn.client
+	release = func(broken bool) {
+		conn.mu.Lock()
+		defer conn.mu.Unlock()
+		conn.inUse--
+		conn.lastUsed = time.Now()
+		if broken && conn.client == client {
+			conn.client.Close()
+			conn.client = nil
+		}
+		if conn.idleTimer == nil {
+			conn.idleTimer = time.AfterFunc(connIdleTimeout, func() { p.closeIfIdle(key, conn) })
+		} else {
+			conn.idleTimer.Reset(connIdleTimeout)
+		}
+	}
+	return client, release, nil
+}
+
+func (p *connPool) closeIfIdle(key connKey, conn *pooledConn) {
+	// conn.mu has to be locked before p.mu, as get holds the lock of a connection while it gets the connection
+	// to the jump host.
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn.inUse > 0 || time.Since(conn.lastUsed) < connIdleTimeout {
+		return
+	}
+	if conn.client != nil {
+		conn.client.Close()
+		conn.client = nil
+	}
+	if p.conns[key] == conn {
+		delete(p.conns, key)
+	}
+}
+
+// isAlive sends a keep-alive request to find out whether the server is still reachable via the connection,
+// e.g. the connection breaks after a reboot.
+func isAlive(client *ssh.Client) bool {
+	errCh := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		return err == nil
+	case <-time.After(sshTimeOut):
+		return false
+	}
+}
+
+// dial connects to the server and returns the SHA256 fingerprint of its host key.
+func dial(target endpoint) (*ssh.Client, string, error) {
+	config, fingerprint, err := clientConfig(target)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Connect to the remote server and perform the SSH handshake.
+	client, err := ssh.Dial("tcp", target.address, config)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to dial ssh. Error message: %w. DialErr: %w", err, errSSHDialFailed)
+	}
+	return client, *fingerprint, nil
+}
+
+// dialViaJumpHost connects to the server through the jump host and returns the SHA256 fingerprint of the host
+// key of the server. Errors of the connection to the jump host are wrapped in ErrJumpHostConnectionFailed, so
+// that they are not mistaken for errors of the server.
+func (p *connPool) dialViaJumpHost(target endpoint, jumpHost *JumpHost) (*ssh.Client, string, error) {
+	// the host key of the jump host is never pinned on first use, so it has to be verified always.
+	if jumpHost.VerifyHostKey == nil {
+		return nil, "", fmt.Errorf("%w %s: %w", ErrJumpHostConnectionFailed, jumpHost.Address, errJumpHostKeyNotVerified)
+	}
+
+	bastion, releaseBastion, err := p.get(endpoint{
+		address:       jumpHost.Address,
+		user:          jumpHost.User,
+		privateKey:    jumpHost.PrivateKey,
+		verifyHostKey: jumpHost.VerifyHostKey,
+	}, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w %s: %s", ErrJumpHostConnectionFailed, jumpHost.Address, err.Error())
+	}
+
+	config, fingerprint, err := clientConfig(target)
+	if err != nil {
+		releaseBastion(false)
+		return nil, "", err
+	}
+
+	conn, err := dialThrough(bastion, target.address)
+	if err != nil {
+		var openErr *ssh.OpenChannelError
+		if !errors.As(err, &openErr) && !errors.Is(err, ErrTimeout) {
+			// the connection to the jump host is broken.
+			releaseBastion(true)
+			return nil, "", fmt.Errorf("%w %s: %s", ErrJumpHostConnectionFailed, jumpHost.Address, err.Error())
+		}
+		releaseBastion(false)
+		return nil, "", fmt.Errorf("failed to dial ssh. Error message: %w. DialErr: %w", err, errSSHDialFailed)
+	}
+
+	client, err := newClientConn(conn, target.address, config)
+	if err != nil {
+		releaseBastion(false)
+		return nil, "", fmt.Errorf("failed to dial ssh. Error message: %w. DialErr: %w", err, errSSHDialFailed)
+	}
+
+	// the connection to the jump host stays in use as long as the connection to the server is open.
+	go func() {
+		_ = client.Wait()
+		releaseBastion(false)
+	}()
+	return client, *fingerprint, nil
+}
+
+// clientConfig returns the config to log in on the server. The returned fingerprint is set to the fingerprint of
+// the host key of the server during the handshake.
+func clientConfig(target endpoint) (*ssh.ClientConfig, *string, error) {
+	// Create the Signer for this private key.
+	signer, err := ssh.ParsePrivateKey([]byte(target.privateKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse private key: %w", err)
+	}
+
+	var fingerprint string
+	config := &ssh.ClientConfig{
+		User: target.user,
+		Auth: []ssh.AuthMethod{
+			// Use the PublicKeys method for remote authentication.
+			ssh.PublicKeys(signer),
+		},
+		HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			fingerprint = ssh.FingerprintSHA256(key)
+			if target.verifyHostKey == nil {
+				return nil
+			}
+			return target.verifyHostKey(fingerprint)
+		},
+		Timeout: sshTimeOut,
+	}
+	return config, &fingerprint, nil
+}
+
+// dialThrough opens a TCP connection to the address from the jump host. Errors of the jump host that concern the
+// server, e.g. because it refuses the connection, are mapped to ErrConnectionRefused and ErrTimeout, so that they
+// get classified like errors of direct connections.
+func dialThrough(bastion *ssh.Client, address string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		conn, err := bastion.Dial("tcp", address)
+		resultCh <- result{conn: conn, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-resultCh:
+	case <-time.After(sshTimeOut):
+		go func() {
+			if late := <-resultCh; late.conn != nil {
+				late.conn.Close()
+			}
+		}()
+		return nil, fmt.Errorf("dial tcp %s via jump host: %w", address, ErrTimeout)
+	}
+
+	var openErr *ssh.OpenChannelError
+	if errors.As(res.err, &openErr) && openErr.Reason == ssh.ConnectionFailed {
+		msg := strings.ToLower(openErr.Message)
+		switch {
+		case strings.Contains(msg, "refused"):
+			return nil, fmt.Errorf("%w: dial tcp %s via jump host: %w", openErr, address, ErrConnectionRefused)
+		case strings.Contains(msg, "timed out"):
+			return nil, fmt.Errorf("%w: dial tcp %s via jump host: %w", openErr, address, ErrTimeout)
+		}
+	}
+	return res.conn, res.err
+}
+
+// newClientConn performs the SSH handshake on a connection that has been opened through the jump host. As such
+// connections do not support deadlines, the connection gets closed if the handshake takes too long.
+func newClientConn(conn net.Conn, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	type result struct {
+		client *ssh.Client
+		err    error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		c, chans, reqs, err := ssh.NewClientConn(conn, address, config)
+		if err != nil {
+			resultCh <- result{err: err}
+			return
+		}
+		resultCh <- result{client: ssh.NewClient(c, chans, reqs)}
+	}()
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil {
+			conn.Close()
+		}
+		return res.client, res.err
+	case <-time.After(sshTimeOut):
+		conn.Close()
+		return nil, fmt.Errorf("ssh handshake with %s via jump host: %w", address, ErrTimeout)
+	}
+}
@@ -25,9 +25,11 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -48,6 +50,12 @@ var wipeDiskShellScript string
 //go:embed check-disk.sh
 var checkDiskShellScript string
 
+//go:embed burn-in.sh
+var burnInShellScript string
+
+//go:embed write-disk-image.sh
+var writeDiskImageShellScript string
+
 var downloadFromOciShellScript = `#!/bin/bash
 
 # Copyright 2023 The Kubernetes Authors.
@@ -170,6 +178,10 @@ var (
 	// ErrCheckDiskBrokenDisk means that a disk seams broken.
 	ErrCheckDiskBrokenDisk = errors.New("CheckDisk failed")
 	errSSHDialFailed       = errors.New("failed to dial ssh")
+	// ErrJumpHostConnectionFailed means that the connection to the jump host failed. In this case, nothing is known
+	// about the server behind the jump host, so the error is not classified as timeout, connection refused etc.
+	ErrJumpHostConnectionFailed = errors.New("failed to connect to ssh jump host")
+	errJumpHostKeyNotVerified   = errors.New("host key of ssh jump host is not verified")
 )
 
 // Input defines an SSH input.
@@ -177,6 +189,36 @@ type Input struct {
 	IP         string
 	PrivateKey string
 	Port       int
+
+	// VerifyHostKey gets called with the SHA256 fingerprint of the host key of the server. If it returns an
+	// error, the connection gets aborted. If it is nil, every host key is accepted.
+	VerifyHostKey func(fingerprint string) error
+
+	// JumpHost is a bastion host through which the connection to the server is made. If it is nil, the
+	// server is connected directly.
+	JumpHost *JumpHost
+}
+
+// JumpHost defines a bastion host through which the connection to the server is made.
+type JumpHost struct {
+	// Address is the address of the jump host in the form host:port.
+	Address    string
+	User       string
+	PrivateKey string
+
+	// VerifyHostKey works like Input.VerifyHostKey, but for the host key of the jump host. It is required, as
+	// the host key of the jump host is not pinned on first use.
+	VerifyHostKey func(fingerprint string) error
+}
+
+// HostKeyMismatchError means that the host key of the server does not match the pinned host key.
+type HostKeyMismatchError struct {
+	Pinned string
+	Actual string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("ssh: host key mismatch: pinned %s, got %s", e.Pinned, e.Actual)
 }
 
 // Output defines the SSH output.
@@ -198,6 +240,101 @@ const (
 	InstallImageStateFinished InstallImageState = "finished"
 )
 
+// BurnInState defines the states of the burn-in process.
+type BurnInState string
+
+const (
+	// BurnInStateNotStartedYet means the burn-in has not started yet.
+	BurnInStateNotStartedYet BurnInState = "not-started-yet"
+	// BurnInStateRunning means the burn-in is still running.
+	BurnInStateRunning BurnInState = "running"
+	// BurnInStateFinished means the burn-in has finished.
+	BurnInStateFinished BurnInState = "finished"
+)
+
+// BurnInOptions defines which checks the burn-in runs. Checks with a zero value are skipped.
+type BurnInOptions struct {
+	SMART           bool
+	MemtesterMB     int
+	MinDiskReadMBps int
+	MinNICSpeedMbps int
+}
+
+// args returns the arguments of burn-in.sh.
+func (o BurnInOptions) args() string {
+	var args []string
+	if o.SMART {
+		args = append(args, "--smart")
+	}
+	if o.MemtesterMB > 0 {
+		args = append(args, fmt.Sprintf("--memtester-mb %d", o.MemtesterMB))
+	}
+	if o.MinDiskReadMBps > 0 {
+		args = append(args, fmt.Sprintf("--min-disk-read-mbps %d", o.MinDiskReadMBps))
+	}
+	if o.MinNICSpeedMbps > 0 {
+		args = append(args, fmt.Sprintf("--min-nic-speed-mbps %d", o.MinNICSpeedMbps))
+	}
+	return strings.Join(args, " ")
+}
+
+// DiskImageState defines the states of writing a disk image.
+type DiskImageState string
+
+const (
+	// DiskImageStateNotStartedYet means writing the disk image has not started yet.
+	DiskImageStateNotStartedYet DiskImageState = "not-started-yet"
+	// DiskImageStateRunning means the disk image is still being written.
+	DiskImageStateRunning DiskImageState = "running"
+	// DiskImageStateFinished means writing the disk image has finished.
+	DiskImageStateFinished DiskImageState = "finished"
+)
+
+// DiskImageOptions defines the disk image that gets written onto the disk of the first WWN.
+type DiskImageOptions struct {
+	URL         string
+	Format      string
+	Compression string
+	Checksum    string
+	SkipGrowth  bool
+	WWNs        []string
+
+	// MetaData and UserData are written to a partition with the label CIDATA, so that cloud-init finds them.
+	MetaData string
+	UserData []byte
+
+	// PostInstallScript runs in the rescue system after the disk image has been written.
+	PostInstallScript string
+}
+
+// args returns the arguments of write-disk-image.sh.
+func (o DiskImageOptions) args() string {
+	args := []string{
+		"--url", shellQuote(o.URL),
+		"--format", shellQuote(o.Format),
+		"--checksum", shellQuote(o.Checksum),
+		"--seed-dir", "/root/disk-image-seed",
+	}
+	if o.Compression != "" {
+		args = append(args, "--compression", shellQuote(o.Compression))
+	}
+	if o.SkipGrowth {
+		args = append(args, "--no-grow")
+	}
+	if o.PostInstallScript != "" {
+		args = append(args, "--post-install", "/root/post-install.sh")
+	}
+	for _, wwn := range o.WWNs {
+		args = append(args, shellQuote(wwn))
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes the string for the shell, so that it is passed as a single argument.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func (o Output) String() string {
 	s := make([]string, 0, 3)
 	stdout := strings.TrimSpace(o.StdOut)
@@ -255,6 +392,8 @@ type Client interface {
 	DownloadImage(path, url string) Output
 	CreatePostInstallScript(data string) Output
 	ExecuteInstallImage(hasPostInstallScript bool) Output
+	// ExecutePostInstallScript runs the post install script in the running operating system.
+	ExecutePostInstallScript() Output
 	Reboot() Output
 	CloudInitStatus() Output
 	CheckCloudInitLogsForSigTerm() Output
@@ -271,6 +410,25 @@ type Client interface {
 	// CheckDisk checks the given disks via smartctl.
 	// ErrCheckDiskBrokenDisk gets returned, if a disk is broken.
 	CheckDisk(ctx context.Context, sliceOfWwns []string) (info string, err error)
+
+	// GetRaidStatus returns the content of /proc/mdstat.
+	GetRaidStatus() Output
+
+	// StartBurnIn starts the burn-in checks in the background.
+	StartBurnIn(opts BurnInOptions) Output
+	GetBurnInState() (BurnInState, error)
+	// GetResultOfBurnIn returns the output and the exit status of the burn-in. Every check prints one line
+	// "RESULT<tab>check<tab>target<tab>pass|fail<tab>message".
+	GetResultOfBurnIn() (output string, exitStatus int, err error)
+
+	// StartWriteDiskImage starts writing the disk image in the background.
+	StartWriteDiskImage(opts DiskImageOptions) Output
+	GetWriteDiskImageState() (DiskImageState, error)
+	// GetResultOfWriteDiskImage returns the output and the exit status of writing the disk image.
+	GetResultOfWriteDiskImage() (output string, exitStatus int, err error)
+	// ResetWriteDiskImage removes the output and the exit status of writing the disk image, so that it can be
+	// started again.
+	ResetWriteDiskImage() Output
 }
 
 // Factory is the interface for creating new Client objects.
@@ -278,11 +436,14 @@ type Factory interface {
 	NewClient(Input) Client
 }
 
-type sshFactory struct{}
+type sshFactory struct {
+	pool *connPool
+}
 
-// NewFactory creates a new factory for SSH clients.
+// NewFactory creates a new factory for SSH clients. The clients of one factory share their connections, so
+// that a connection to a server is reused by consecutive commands.
 func NewFactory() Factory {
-	return &sshFactory{}
+	return &sshFactory{pool: newConnPool()}
 }
 
 var _ = Factory(&sshFactory{})
@@ -293,6 +454,9 @@ func (f *sshFactory) NewClient(in Input) Client {
 		privateSSHKey: in.PrivateKey,
 		ip:            in.IP,
 		port:          in.Port,
+		verifyHostKey: in.VerifyHostKey,
+		jumpHost:      in.JumpHost,
+		pool:          f.pool,
 	}
 }
 
@@ -300,6 +464,9 @@ type sshClient struct {
 	ip            string
 	privateSSHKey string
 	port          int
+	verifyHostKey func(fingerprint string) error
+	jumpHost      *JumpHost
+	pool          *connPool
 }
 
 var _ = Client(&sshClient{})
@@ -429,6 +596,11 @@ EOF_VIA_SSH`, data))
 	return c.runSSH(`chmod +x /root/post-install.sh`)
 }
 
+// ExecutePostInstallScript implements the ExecutePostInstallScript method of the SSHClient interface.
+func (c *sshClient) ExecutePostInstallScript() Output {
+	return c.runSSH(`/root/post-install.sh`)
+}
+
 // GetInstallImageState returns the running installimage processes.
 func (c *sshClient) GetInstallImageState() (InstallImageState, error) {
 	out := c.runSSH(`ps aux| grep installimage | grep -v grep; true`)
@@ -642,6 +814,129 @@ chmod a+rx /root/check-disk.sh
 	return "", fmt.Errorf("CheckDisk for %+v failed: %s. %s: %w", sliceOfWwns, out.StdOut, out.StdErr, out.Err)
 }
 
+// GetRaidStatus implements the GetRaidStatus method of the SSHClient interface.
+func (c *sshClient) GetRaidStatus() Output {
+	return c.runSSH(`cat /proc/mdstat`)
+}
+
+// StartBurnIn implements the StartBurnIn method of the SSHClient interface.
+func (c *sshClient) StartBurnIn(opts BurnInOptions) Output {
+	out := c.runSSH(fmt.Sprintf(`cat >/root/burn-in.sh <<'EOF_VIA_SSH'
+%s
+EOF_VIA_SSH
+chmod a+rx /root/burn-in.sh
+rm -f /root/burn-in.log /root/burn-in.done
+`, burnInShellScript))
+	if out.Err != nil || out.StdErr != "" {
+		return out
+	}
+
+	return c.runSSH(fmt.Sprintf(`nohup sh -c '/root/burn-in.sh %s >/root/burn-in.log 2>&1; echo $? >/root/burn-in.done' >/dev/null 2>&1 </dev/null &`,
+		opts.args()))
+}
+
+// GetBurnInState returns the state of the burn-in.
+func (c *sshClient) GetBurnInState() (BurnInState, error) {
+	out := c.runSSH(`if [ -e /root/burn-in.done ]; then echo finished; elif [ -e /root/burn-in.log ]; then echo running; fi`)
+	if out.Err != nil {
+		return "", fmt.Errorf("failed to get state of burn-in: %w", out.Err)
+	}
+	switch strings.TrimSpace(out.StdOut) {
+	case "finished":
+		return BurnInStateFinished, nil
+	case "running":
+		return BurnInStateRunning, nil
+	}
+	return BurnInStateNotStartedYet, nil
+}
+
+// GetResultOfBurnIn returns the logs and the exit status of the burn-in.
+// Before calling this method be sure that the burn-in is already terminated.
+func (c *sshClient) GetResultOfBurnIn() (output string, exitStatus int, err error) {
+	out := c.runSSH(`cat /root/burn-in.done`)
+	if out.Err != nil {
+		return "", 0, fmt.Errorf("failed to get burn-in.done: %w", out.Err)
+	}
+	exitStatus, err = strconv.Atoi(strings.TrimSpace(out.StdOut))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse exit status of burn-in.sh %q: %w", out.StdOut, err)
+	}
+
+	out = c.runSSH(`cat /root/burn-in.log`)
+	if out.Err != nil {
+		return "", 0, fmt.Errorf("failed to get burn-in.log: %w", out.Err)
+	}
+	return out.StdOut, exitStatus, nil
+}
+
+// StartWriteDiskImage implements the StartWriteDiskImage method of the SSHClient interface.
+func (c *sshClient) StartWriteDiskImage(opts DiskImageOptions) Output {
+	postInstallScript := ""
+	if opts.PostInstallScript != "" {
+		postInstallScript = fmt.Sprintf(`echo %s | base64 -d >/root/post-install.sh
+chmod a+rx /root/post-install.sh
+`, base64.StdEncoding.EncodeToString([]byte(opts.PostInstallScript)))
+	}
+
+	out := c.runSSH(fmt.Sprintf(`cat >/root/write-disk-image.sh <<'EOF_VIA_SSH'
+%s
+EOF_VIA_SSH
+chmod a+rx /root/write-disk-image.sh
+rm -rf /root/write-disk-image.log /root/write-disk-image.done /root/disk-image-seed
+mkdir -p /root/disk-image-seed
+echo %s | base64 -d >/root/disk-image-seed/meta-data
+echo %s | base64 -d >/root/disk-image-seed/user-data
+%s`, writeDiskImageShellScript,
+		base64.StdEncoding.EncodeToString([]byte(opts.MetaData)),
+		base64.StdEncoding.EncodeToString(opts.UserData),
+		postInstallScript))
+	if out.Err != nil || out.StdErr != "" {
+		return out
+	}
+
+	return c.runSSH(fmt.Sprintf(`nohup sh -c '/root/write-disk-image.sh %s >/root/write-disk-image.log 2>&1; echo $? >/root/write-disk-image.done' >/dev/null 2>&1 </dev/null &`,
+		strings.ReplaceAll(opts.args(), "'", `'\''`)))
+}
+
+// GetWriteDiskImageState returns the state of writing the disk image.
+func (c *sshClient) GetWriteDiskImageState() (DiskImageState, error) {
+	out := c.runSSH(`if [ -e /root/write-disk-image.done ]; then echo finished; elif [ -e /root/write-disk-image.log ]; then echo running; fi`)
+	if out.Err != nil {
+		return "", fmt.Errorf("failed to get state of writing disk image: %w", out.Err)
+	}
+	switch strings.TrimSpace(out.StdOut) {
+	case "finished":
+		return DiskImageStateFinished, nil
+	case "running":
+		return DiskImageStateRunning, nil
+	}
+	return DiskImageStateNotStartedYet, nil
+}
+
+// GetResultOfWriteDiskImage returns the logs and the exit status of writing the disk image.
+// Before calling this method be sure that writing the disk image is already terminated.
+func (c *sshClient) GetResultOfWriteDiskImage() (output string, exitStatus int, err error) {
+	out := c.runSSH(`cat /root/write-disk-image.done`)
+	if out.Err != nil {
+		return "", 0, fmt.Errorf("failed to get write-disk-image.done: %w", out.Err)
+	}
+	exitStatus, err = strconv.Atoi(strings.TrimSpace(out.StdOut))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse exit status of write-disk-image.sh %q: %w", out.StdOut, err)
+	}
+
+	out = c.runSSH(`cat /root/write-disk-image.log`)
+	if out.Err != nil {
+		return "", 0, fmt.Errorf("failed to get write-disk-image.log: %w", out.Err)
+	}
+	return out.StdOut, exitStatus, nil
+}
+
+// ResetWriteDiskImage implements the ResetWriteDiskImage method of the SSHClient interface.
+func (c *sshClient) ResetWriteDiskImage() Output {
+	return c.runSSH(`rm -f /root/write-disk-image.log /root/write-disk-image.done`)
+}
+
 func (c *sshClient) UntarTGZ() Output {
 	// read tgz from container image.
 	fileName := "/installimage.tgz"
@@ -657,12 +952,12 @@ func (c *sshClient) UntarTGZ() Output {
 
 // IsConnectionRefusedError checks whether the ssh error is a connection refused error.
 func IsConnectionRefusedError(err error) bool {
-	return strings.Contains(err.Error(), ErrConnectionRefused.Error())
+	return !errors.Is(err, ErrJumpHostConnectionFailed) && strings.Contains(err.Error(), ErrConnectionRefused.Error())
 }
 
 // IsAuthenticationFailedError checks whether the ssh error is an authentication failed error.
 func IsAuthenticationFailedError(err error) bool {
-	return strings.Contains(err.Error(), ErrAuthenticationFailed.Error())
+	return !errors.Is(err, ErrJumpHostConnectionFailed) && strings.Contains(err.Error(), ErrAuthenticationFailed.Error())
 }
 
 // IsCommandExitedWithoutExitSignalError checks whether the ssh error is an unplanned exit error.
@@ -672,38 +967,32 @@ func IsCommandExitedWithoutExitSignalError(err error) bool {
 
 // IsTimeoutError checks whether the ssh error is an unplanned exit error.
 func IsTimeoutError(err error) bool {
-	return strings.Contains(err.Error(), ErrTimeout.Error())
+	return !errors.Is(err, ErrJumpHostConnectionFailed) && strings.Contains(err.Error(), ErrTimeout.Error())
 }
 
-func (c *sshClient) runSSH(command string) Output {
-	// Create the Signer for this private key.
-	signer, err := ssh.ParsePrivateKey([]byte(c.privateSSHKey))
-	if err != nil {
-		return Output{Err: fmt.Errorf("unable to parse private key: %w", err)}
-	}
-
-	config := &ssh.ClientConfig{
-		User: "root",
-		Auth: []ssh.AuthMethod{
-			// Use the PublicKeys method for remote authentication.
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //#nosec
-		Timeout:         sshTimeOut,
-	}
-
-	// Connect to the remote server and perform the SSH handshake.
+// IsHostKeyMismatchError checks whether the ssh error is caused by a host key that does not match the pinned one.
+func IsHostKeyMismatchError(err error) bool {
+	var mismatchErr *HostKeyMismatchError
+	return errors.As(err, &mismatchErr)
+}
 
-	client, err := ssh.Dial("tcp", fmt.Sprintf("%s:%v", c.ip, c.port), config)
+func (c *sshClient) runSSH(command string) Output {
+	client, release, err := c.pool.get(endpoint{
+		address:       net.JoinHostPort(c.ip, strconv.Itoa(c.port)),
+		user:          "root",
+		privateKey:    c.privateSSHKey,
+		verifyHostKey: c.verifyHostKey,
+	}, c.jumpHost)
 	if err != nil {
-		return Output{Err: fmt.Errorf("failed to dial ssh. Error message: %s. DialErr: %w", err.Error(), errSSHDialFailed)}
+		return Output{Err: err}
 	}
-	defer client.Close()
 
 	sess, err := client.NewSession()
 	if err != nil {
+		release(true)
 		return Output{Err: fmt.Errorf("unable to create new ssh session: %w", err)}
 	}
+	defer release(false)
 	defer sess.Close()
 
 	var stdoutBuffer bytes.Buffer
@@ -18,11 +18,19 @@ limitations under the License.
 package sshclient
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	_ "embed"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 )
 
 func Test_removeUselessLinesFromCloudInitOutput(t *testing.T) {
@@ -102,3 +110,282 @@ func TestOutput_String(t *testing.T) {
 		Err:    fmt.Errorf("some err"),
 	}, "mystdout. Stderr: mystderr. Err: some err")
 }
+
+// testSSHServer is a minimal SSH server that answers every command with the command itself. It also forwards
+// TCP connections, so that it can be used as jump host.
+type testSSHServer struct {
+	listener    net.Listener
+	hostKey     ssh.Signer
+	connections atomic.Int32
+}
+
+func newTestSSHServer(t *testing.T) *testSSHServer {
+	t.Helper()
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &testSSHServer{listener: listener, hostKey: hostSigner}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostSigner)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.connections.Add(1)
+			go server.serve(conn, config)
+		}
+	}()
+	return server
+}
+
+func (s *testSSHServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() == "direct-tcpip" {
+			go forward(newChannel)
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer channel.Close()
+			for req := range channelRequests {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				_ = req.Reply(true, nil)
+				// the payload is the length-prefixed command
+				_, _ = channel.Write(req.Payload[4:])
+				_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+				return
+			}
+		}()
+	}
+}
+
+func forward(newChannel ssh.NewChannel) {
+	var target struct {
+		Host     string
+		Port     uint32
+		OrigHost string
+		OrigPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		_, _ = io.Copy(channel, conn)
+		channel.Close()
+	}()
+	_, _ = io.Copy(conn, channel)
+	conn.Close()
+}
+
+func (s *testSSHServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func testPrivateKey(t *testing.T) string {
+	t.Helper()
+	_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	clientPEM, err := ssh.MarshalPrivateKey(clientKey, "")
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(clientPEM))
+}
+
+func TestDiskImageOptions_args(t *testing.T) {
+	opts := DiskImageOptions{
+		URL:               "https://example.com/image.raw.xz?user='me'",
+		Format:            "raw",
+		Compression:       "xz",
+		Checksum:          "sha256:abc",
+		SkipGrowth:        true,
+		WWNs:              []string{"0x500a07511bb48b25", "0x500a07511bb48b26"},
+		PostInstallScript: "echo hello",
+	}
+	require.Equal(t,
+		`--url 'https://example.com/image.raw.xz?user='\''me'\''' --format 'raw' --checksum 'sha256:abc' --seed-dir /root/disk-image-seed `+
+			`--compression 'xz' --no-grow --post-install /root/post-install.sh '0x500a07511bb48b25' '0x500a07511bb48b26'`,
+		opts.args())
+}
+
+func TestRunSSH_ReusesConnection(t *testing.T) {
+	server := newTestSSHServer(t)
+	factory := NewFactory()
+	in := Input{IP: "127.0.0.1", Port: server.port(), PrivateKey: testPrivateKey(t)}
+
+	c := factory.NewClient(in).(*sshClient)
+	require.Equal(t, "hostname", c.runSSH("hostname").StdOut)
+	require.Equal(t, "uptime", c.runSSH("uptime").StdOut)
+
+	// a new client for the same input uses the same connection
+	require.Equal(t, "hostname", factory.NewClient(in).(*sshClient).runSSH("hostname").StdOut)
+	require.Equal(t, int32(1), server.connections.Load())
+
+	// a broken connection gets replaced
+	for _, conn := range c.pool.conns {
+		conn.client.Close()
+	}
+	require.Equal(t, "hostname", c.runSSH("hostname").StdOut)
+	require.Equal(t, int32(2), server.connections.Load())
+}
+
+func TestRunSSH_ConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	out := NewFactory().NewClient(Input{IP: "127.0.0.1", Port: port, PrivateKey: testPrivateKey(t)}).(*sshClient).runSSH("hostname")
+	require.Error(t, out.Err)
+	require.True(t, IsConnectionRefusedError(out.Err))
+}
+
+func TestRunSSH_VerifyHostKey(t *testing.T) {
+	server := newTestSSHServer(t)
+
+	var gotFingerprint string
+	in := Input{
+		IP:         "127.0.0.1",
+		Port:       server.port(),
+		PrivateKey: testPrivateKey(t),
+		VerifyHostKey: func(fingerprint string) error {
+			gotFingerprint = fingerprint
+			return nil
+		},
+	}
+	factory := NewFactory()
+	require.NoError(t, factory.NewClient(in).(*sshClient).runSSH("hostname").Err)
+	require.Equal(t, ssh.FingerprintSHA256(server.hostKey.PublicKey()), gotFingerprint)
+
+	// the host key of a pooled connection gets verified again
+	in.VerifyHostKey = func(fingerprint string) error {
+		return &HostKeyMismatchError{Pinned: "SHA256:pinned", Actual: fingerprint}
+	}
+	out := factory.NewClient(in).(*sshClient).runSSH("hostname")
+	require.Error(t, out.Err)
+	require.True(t, IsHostKeyMismatchError(out.Err))
+
+	// a new connection gets refused as well
+	out = NewFactory().NewClient(in).(*sshClient).runSSH("hostname")
+	require.Error(t, out.Err)
+	require.True(t, IsHostKeyMismatchError(out.Err))
+	require.False(t, IsHostKeyMismatchError(fmt.Errorf("some err")))
+}
+
+func TestRunSSH_JumpHost(t *testing.T) {
+	jumpHost := newTestSSHServer(t)
+	server := newTestSSHServer(t)
+	factory := NewFactory()
+
+	var jumpHostFingerprint string
+	in := Input{
+		IP:         "127.0.0.1",
+		Port:       server.port(),
+		PrivateKey: testPrivateKey(t),
+		JumpHost: &JumpHost{
+			Address:    jumpHost.listener.Addr().String(),
+			User:       "jump",
+			PrivateKey: testPrivateKey(t),
+			VerifyHostKey: func(fingerprint string) error {
+				jumpHostFingerprint = fingerprint
+				return nil
+			},
+		},
+	}
+	require.Equal(t, "hostname", factory.NewClient(in).(*sshClient).runSSH("hostname").StdOut)
+	require.Equal(t, ssh.FingerprintSHA256(jumpHost.hostKey.PublicKey()), jumpHostFingerprint)
+	require.Equal(t, int32(1), jumpHost.connections.Load())
+	require.Equal(t, int32(1), server.connections.Load())
+
+	// the connection to the jump host is shared by the connections to all servers
+	otherServer := newTestSSHServer(t)
+	otherIn := in
+	otherIn.Port = otherServer.port()
+	require.Equal(t, "uptime", factory.NewClient(otherIn).(*sshClient).runSSH("uptime").StdOut)
+	require.Equal(t, int32(1), jumpHost.connections.Load())
+	require.Equal(t, int32(1), otherServer.connections.Load())
+
+	// a server that refuses the connection behind the jump host is classified like a direct connection
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	refusedIn := in
+	refusedIn.Port = listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	out := factory.NewClient(refusedIn).(*sshClient).runSSH("hostname")
+	require.Error(t, out.Err)
+	require.True(t, IsConnectionRefusedError(out.Err))
+}
+
+func TestRunSSH_JumpHostUnreachable(t *testing.T) {
+	server := newTestSSHServer(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	jumpHostAddress := listener.Addr().String()
+	listener.Close()
+
+	out := NewFactory().NewClient(Input{
+		IP:         "127.0.0.1",
+		Port:       server.port(),
+		PrivateKey: testPrivateKey(t),
+		JumpHost: &JumpHost{
+			Address:       jumpHostAddress,
+			User:          "root",
+			PrivateKey:    testPrivateKey(t),
+			VerifyHostKey: func(string) error { return nil },
+		},
+	}).(*sshClient).runSSH("hostname")
+	require.Error(t, out.Err)
+	require.ErrorIs(t, out.Err, ErrJumpHostConnectionFailed)
+
+	// errors of the jump host must not be mistaken for errors of the server, e.g. a server that refuses
+	// connections while it reboots.
+	require.False(t, IsConnectionRefusedError(out.Err))
+	require.Equal(t, int32(0), server.connections.Load())
+}
+
+func TestRunSSH_JumpHostWithoutHostKeyVerification(t *testing.T) {
+	jumpHost := newTestSSHServer(t)
+	server := newTestSSHServer(t)
+
+	out := NewFactory().NewClient(Input{
+		IP:         "127.0.0.1",
+		Port:       server.port(),
+		PrivateKey: testPrivateKey(t),
+		JumpHost:   &JumpHost{Address: jumpHost.listener.Addr().String(), User: "root", PrivateKey: testPrivateKey(t)},
+	}).(*sshClient).runSSH("hostname")
+	require.ErrorIs(t, out.Err, ErrJumpHostConnectionFailed)
+	require.ErrorIs(t, out.Err, errJumpHostKeyNotVerified)
+	require.Equal(t, int32(0), jumpHost.connections.Load())
+	require.Equal(t, int32(0), server.connections.Load())
+}
@@ -0,0 +1,319 @@
+#!/bin/bash
+
+# Copyright 2024 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# This script gets copied from the controller into the rescue system
+# of the bare-metal machine. It writes a raw or qcow2 disk image onto
+# the root device instead of running installimage.
+
+trap 'echo "ERROR: A command has failed. Exiting the script. Line was ($0:$LINENO): $(sed -n "${LINENO}p" "$0")"; exit 3' ERR
+set -Eeuo pipefail
+
+function usage() {
+    echo "$0 --url URL --format raw|qcow2 --checksum ALGORITHM:DIGEST [--compression gz|xz|bz2|zst]"
+    echo "    [--no-grow] [--seed-dir DIR] [--post-install SCRIPT] wwn1 [wwn2 ...]"
+    echo "    Write the disk image onto the disk of the first WWN. The signatures of all given disks get wiped before."
+    echo "    The last partition of the image and its filesystem get grown to the size of the disk, unless --no-grow is given."
+    echo "    The files of the seed directory are written to a partition with the label CIDATA at the end of the disk,"
+    echo "    so that cloud-init finds them (NoCloud data source)."
+    echo "    The post install script runs afterwards. The environment variable ROOT_DEVICE contains the disk."
+    echo "    ATTENTION! THIS DELETES ALL DATA ON THE GIVEN DISKS!"
+    echo "    Exit 0: The image was written."
+    echo "    Exit 1: The checksum of the image does not match."
+    echo "    Exit 3: Some other error (like invalid arguments or a failed download)"
+}
+
+url=""
+format=""
+compression=""
+checksum=""
+grow=true
+seed_dir=""
+post_install=""
+wwns=()
+
+while [ $# -gt 0 ]; do
+    case "$1" in
+    --url)
+        url="$2"
+        shift
+        ;;
+    --format)
+        format="$2"
+        shift
+        ;;
+    --compression)
+        compression="$2"
+        shift
+        ;;
+    --checksum)
+        checksum="$2"
+        shift
+        ;;
+    --no-grow)
+        grow=false
+        ;;
+    --seed-dir)
+        seed_dir="$2"
+        shift
+        ;;
+    --post-install)
+        post_install="$2"
+        shift
+        ;;
+    --*)
+        echo "unknown argument $1"
+        usage
+        exit 3
+        ;;
+    *)
+        wwns+=("$1")
+        ;;
+    esac
+    shift
+done
+
+if [ -z "$url" ] || [ -z "$format" ] || [ -z "$checksum" ] || [ ${#wwns[@]} -eq 0 ]; then
+    usage
+    exit 3
+fi
+
+algorithm="${checksum%%:*}"
+expected_digest="${checksum#*:}"
+case "$algorithm" in
+sha256 | sha512) ;;
+*)
+    echo "unsupported checksum algorithm $algorithm"
+    exit 3
+    ;;
+esac
+
+function ensure_command() {
+    if ! command -v "$1" >/dev/null 2>&1; then
+        echo "INFO: Installing $2"
+        DEBIAN_FRONTEND=noninteractive apt-get update -q
+        DEBIAN_FRONTEND=noninteractive apt-get install -y -q "$2"
+    fi
+}
+
+decompress=(cat)
+case "$compression" in
+"") ;;
+gz)
+    decompress=(gzip -dc)
+    ;;
+xz)
+    decompress=(xz -dc)
+    ;;
+bz2)
+    decompress=(bzip2 -dc)
+    ;;
+zst)
+    ensure_command zstd zstd
+    decompress=(zstd -dc)
+    ;;
+*)
+    echo "unsupported compression $compression"
+    exit 3
+    ;;
+esac
+
+devices=()
+for wwn in "${wwns[@]}"; do
+    device=$(lsblk -dn -oNAME,WWN,TYPE | awk -v wwn="$wwn" '$2 == wwn && $3 == "disk" {print $1; exit}')
+    if [ -z "$device" ]; then
+        echo "Failed to find device for WWN $wwn"
+        lsblk -oNAME,WWN | grep -vi loop || true
+        exit 3
+    fi
+    devices+=("/dev/$device")
+done
+root_device="${devices[0]}"
+
+# Old signatures must not survive on the other disks, otherwise the server might boot an old operating system.
+for device in "${devices[@]}"; do
+    echo "INFO: Calling wipefs for $device"
+    wipefs -af "$device"
+done
+
+curl_opts=(-fsSL --retry 3 --connect-timeout 30 --speed-limit 1024 --speed-time 120)
+
+# The rescue system runs from memory, so a qcow2 image, which has to be downloaded before it can be converted,
+# is stored on the second disk if there is one.
+download_dir=/root
+download_device=""
+
+function remove_download() {
+    rm -f "$download_dir/disk-image.qcow2"
+    if [ -n "$download_device" ] && mountpoint -q "$download_dir"; then
+        umount "$download_dir"
+        wipefs -af "$download_device"
+    fi
+}
+trap remove_download EXIT
+
+function verify_checksum() {
+    local digest
+    digest=$(cut -d' ' -f1 /root/disk-image.digest)
+    if [ "$digest" != "$expected_digest" ]; then
+        echo "ERROR: Checksum mismatch. Expected $algorithm:$expected_digest, got $algorithm:$digest"
+        # a partially valid image must not boot
+        wipefs -af "$root_device"
+        exit 1
+    fi
+    echo "INFO: Checksum $algorithm:$digest is valid"
+}
+
+case "$format" in
+raw)
+    echo "INFO: Streaming image to $root_device"
+    rm -f /root/disk-image.fifo
+    mkfifo /root/disk-image.fifo
+    "${algorithm}sum" </root/disk-image.fifo >/root/disk-image.digest &
+    digest_pid=$!
+    curl "${curl_opts[@]}" "$url" | tee /root/disk-image.fifo | "${decompress[@]}" |
+        dd of="$root_device" bs=4M iflag=fullblock conv=fsync status=none
+    wait "$digest_pid"
+    rm -f /root/disk-image.fifo
+    verify_checksum
+    ;;
+qcow2)
+    ensure_command qemu-img qemu-utils
+    if [ ${#devices[@]} -gt 1 ]; then
+        download_device="${devices[1]}"
+        download_dir=/mnt/disk-image-download
+        echo "INFO: Creating temporary filesystem for the download on $download_device"
+        mkfs.ext4 -q -F "$download_device"
+        mkdir -p "$download_dir"
+        mount "$download_device" "$download_dir"
+    fi
+    size=$(curl "${curl_opts[@]}" -I "$url" 2>/dev/null | tr -d '\r' | awk 'tolower($1) == "content-length:" {print $2}' | tail -n1) || true
+    available=$(df --output=avail -B1 "$download_dir" | tail -n1)
+    if [ -n "$size" ] && [ "$size" -gt "$available" ]; then
+        echo "ERROR: The image has $size bytes, but only $available bytes are available in $download_dir"
+        exit 3
+    fi
+    echo "INFO: Downloading image to $download_dir"
+    curl "${curl_opts[@]}" -o "$download_dir/disk-image.qcow2" "$url"
+    "${algorithm}sum" "$download_dir/disk-image.qcow2" >/root/disk-image.digest
+    verify_checksum
+    echo "INFO: Converting image to $root_device"
+    qemu-img convert -n -f qcow2 -O raw "$download_dir/disk-image.qcow2" "$root_device"
+    remove_download
+    sync
+    ;;
+*)
+    echo "unsupported format $format"
+    exit 3
+    ;;
+esac
+
+function reread_partitions() {
+    blockdev --rereadpt "$root_device" || partx -u "$root_device"
+    udevadm settle || true
+}
+
+# partition_device prints the device of the partition with the given number.
+function partition_device() {
+    local partition
+    for partition in /sys/class/block/"$(basename "$root_device")"/*/partition; do
+        [ -e "$partition" ] || continue
+        if [ "$(cat "$partition")" = "$1" ]; then
+            echo "/dev/$(basename "$(dirname "$partition")")"
+            return 0
+        fi
+    done
+    echo "Failed to find partition $1 of $root_device" >&2
+    return 1
+}
+
+reread_partitions
+
+# the partition that starts last gets grown
+last_partition=$(partx -g -o NR,START "$root_device" | sort -n -k2 | tail -n1 | awk '{print $1}')
+if [ -z "$last_partition" ]; then
+    echo "ERROR: The image does not contain a partition table"
+    exit 3
+fi
+
+if [ "$(blkid -o value -s PTTYPE "$root_device")" = "gpt" ]; then
+    # the backup GPT of the image has to be moved to the end of the disk
+    sfdisk --relocate gpt-bak-std "$root_device"
+fi
+
+# The seed partition is placed at the end of the disk, so that the image can be grown up to it.
+sector_size=$(blockdev --getss "$root_device")
+disk_sectors=$(($(blockdev --getsize64 "$root_device") / sector_size))
+seed_sectors=$((64 * 1024 * 1024 / sector_size))
+alignment=$((1024 * 1024 / sector_size))
+# leave room for the backup GPT
+seed_start=$(((disk_sectors - 34 - seed_sectors) / alignment * alignment))
+if [ -n "$seed_dir" ]; then
+    echo "INFO: Creating seed partition at sector $seed_start"
+    echo "start=$seed_start, size=$seed_sectors, type=L" | sfdisk --append --no-reread "$root_device"
+fi
+
+if [ "$grow" = true ]; then
+    echo "INFO: Growing partition $last_partition of $root_device"
+    echo ", +" | sfdisk --no-reread -N "$last_partition" "$root_device"
+fi
+
+reread_partitions
+
+if [ "$grow" = true ]; then
+    grow_device=$(partition_device "$last_partition")
+    fstype=$(blkid -o value -s TYPE "$grow_device" || true)
+    mkdir -p /mnt/disk-image-grow
+    case "$fstype" in
+    ext2 | ext3 | ext4)
+        # exit status 1 means that errors have been corrected
+        e2fsck -fy "$grow_device" || [ $? -le 1 ]
+        resize2fs "$grow_device"
+        ;;
+    xfs)
+        mount "$grow_device" /mnt/disk-image-grow
+        xfs_growfs /mnt/disk-image-grow
+        umount /mnt/disk-image-grow
+        ;;
+    btrfs)
+        mount "$grow_device" /mnt/disk-image-grow
+        btrfs filesystem resize max /mnt/disk-image-grow
+        umount /mnt/disk-image-grow
+        ;;
+    *)
+        echo "INFO: Filesystem '$fstype' of $grow_device does not get grown"
+        ;;
+    esac
+fi
+
+if [ -n "$seed_dir" ]; then
+    ensure_command mkfs.vfat dosfstools
+    seed_partition=$(partx -g -o NR,START "$root_device" | awk -v start="$seed_start" '$2 == start {print $1}')
+    seed_device=$(partition_device "$seed_partition")
+    echo "INFO: Writing seed to $seed_device"
+    mkfs.vfat -n CIDATA "$seed_device"
+    mkdir -p /mnt/disk-image-seed
+    mount "$seed_device" /mnt/disk-image-seed
+    cp "$seed_dir"/* /mnt/disk-image-seed/
+    umount /mnt/disk-image-seed
+fi
+
+if [ -n "$post_install" ]; then
+    echo "INFO: Running post install script"
+    ROOT_DEVICE="$root_device" "$post_install"
+fi
+
+sync
+echo "INFO: Disk image was written to $root_device"
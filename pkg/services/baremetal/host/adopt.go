@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+// previous: None
+// next: Provisioned
+func (s *Service) actionAdopting(_ context.Context) actionResult {
+	host := s.scope.HetznerBareMetalHost
+	markProvisionPending(host, infrav1.StateAdopting)
+
+	_, actResult := s.updateRobotServerDetails()
+	if _, isComplete := actResult.(actionComplete); !isComplete {
+		return actResult
+	}
+
+	if err := s.ensureRebootTypes(); err != nil {
+		return actionError{err: err}
+	}
+
+	hostKeyVerifier := s.osHostKeyVerifier()
+	sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
+		PrivateKey:    sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, host.Spec.Status.SSHSpec.SecretRef).PrivateKey,
+		Port:          host.Spec.Status.SSHSpec.PortAfterCloudInit,
+		IP:            host.Spec.Status.GetIPAddress(),
+		VerifyHostKey: hostKeyVerifier.verify,
+		JumpHost:      s.sshJumpHost(),
+	})
+
+	out := sshClient.GetHostName()
+	hostName := trimLineBreak(out.StdOut)
+	if out.Err != nil || out.StdErr != "" || hostName == "" {
+		reason := "empty hostname"
+		if out.Err != nil {
+			reason = out.Err.Error()
+		} else if out.StdErr != "" {
+			reason = out.StdErr
+		}
+		msg := fmt.Sprintf("failed to reach operating system via ssh on port %d: %s",
+			host.Spec.Status.SSHSpec.PortAfterCloudInit, reason)
+		conditions.MarkFalse(
+			host,
+			infrav1.ProvisionSucceededCondition,
+			infrav1.SSHToOSFailedReason,
+			clusterv1.ConditionSeverityWarning,
+			"%s",
+			msg,
+		)
+		record.Warn(host, infrav1.SSHToOSFailedReason, msg)
+
+		// The server is never rebooted, as it runs the workload that is supposed to be adopted.
+		return actionContinue{delay: 30 * time.Second}
+	}
+
+	// from now on we know that we talk to the operating system
+	hostKeyVerifier.trust()
+
+	if host.Spec.Status.HardwareDetails == nil {
+		hardwareDetails, err := getHardwareDetails(sshClient)
+		if err != nil {
+			return actionError{err: fmt.Errorf("failed to get hardware details: %w", err)}
+		}
+		host.Spec.Status.HardwareDetails = &hardwareDetails
+	}
+
+	host.Spec.Status.AdoptedHostname = hostName
+	record.Eventf(host, "ServerAdopted", "adopted externally provisioned server with hostname %s", hostName)
+	conditions.MarkTrue(host, infrav1.ProvisionSucceededCondition)
+	host.ClearError()
+	return actionComplete{} // next: Provisioned
+}
+
+// resetExternallyProvisioned makes sure that an adopted server gets an image installed the next time it is provisioned.
+func (s *Service) resetExternallyProvisioned() {
+	host := s.scope.HetznerBareMetalHost
+	if !host.Spec.ExternallyProvisioned && host.Spec.Status.AdoptedHostname == "" {
+		return
+	}
+	host.Spec.ExternallyProvisioned = false
+	host.Spec.Status.AdoptedHostname = ""
+	record.Event(host, "ExternallyProvisionedReset", "host is not externally provisioned anymore")
+}
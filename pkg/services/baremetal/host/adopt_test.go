@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"github.com/syself/hrobot-go/models"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	bmmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
+	sshmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/ssh"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("actionAdopting", func() {
+	var (
+		host      *infrav1.HetznerBareMetalHost
+		robotMock *robotmock.Client
+	)
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost(
+			"test-host",
+			"default",
+			helpers.WithSSHSpecInclPorts(23, 24),
+			helpers.WithConsumerRef(),
+		)
+		host.Spec.ExternallyProvisioned = true
+		host.Spec.Status.ProvisioningState = infrav1.StateAdopting
+
+		robotMock = &robotmock.Client{}
+		robotMock.On("GetBMServer", mock.Anything).Return(&models.Server{ServerIP: "1.2.3.4"}, nil)
+		robotMock.On("GetReboot", mock.Anything).Return(&models.Reset{Type: []string{"sw", "hw"}}, nil)
+	})
+
+	It("adopts the running operating system without rebooting the server", func() {
+		sshMock := registeringSSHMock(`NAME="sda" TYPE="disk" HCTL="0:0:0:0" MODEL="Micron_1100_MTFDDAK512TBN" VENDOR="ATA" SERIAL="18081BB48B25" SIZE="512110190592" WWN="0x500a07511bb48b25" ROTA="0"`)
+		sshMock.On("GetHostName").Unset()
+		sshMock.On("GetHostName").Return(sshclient.Output{StdOut: "node-1\n"})
+
+		service := newTestService(host, robotMock, bmmock.NewSSHFactory(nil, nil, sshMock),
+			helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), nil)
+
+		Expect(service.actionAdopting(context.Background())).To(BeAssignableToTypeOf(actionComplete{}))
+		Expect(host.Spec.Status.IPv4).To(Equal("1.2.3.4"))
+		Expect(host.Spec.Status.RebootTypes).To(HaveLen(2))
+		Expect(host.Spec.Status.HardwareDetails).ToNot(BeNil())
+		Expect(host.Spec.Status.HardwareDetails.Storage).To(HaveLen(1))
+		Expect(host.Spec.Status.AdoptedHostname).To(Equal("node-1"))
+		Expect(service.scope.Hostname()).To(Equal("node-1"))
+		Expect(conditions.IsTrue(host, infrav1.ProvisionSucceededCondition)).To(BeTrue())
+		sshMock.AssertNotCalled(GinkgoT(), "Reboot")
+	})
+
+	It("retries without rebooting if the operating system is not reachable", func() {
+		sshMock := &sshmock.Client{}
+		sshMock.On("GetHostName").Return(sshclient.Output{Err: sshclient.ErrAuthenticationFailed})
+
+		service := newTestService(host, robotMock, bmmock.NewSSHFactory(nil, nil, sshMock),
+			helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), nil)
+
+		Expect(service.actionAdopting(context.Background())).To(BeAssignableToTypeOf(actionContinue{}))
+		Expect(conditions.GetReason(host, infrav1.ProvisionSucceededCondition)).To(Equal(infrav1.SSHToOSFailedReason))
+		Expect(host.Spec.Status.AdoptedHostname).To(BeEmpty())
+		sshMock.AssertNotCalled(GinkgoT(), "Reboot")
+		robotMock.AssertNotCalled(GinkgoT(), "RebootBMServer", mock.Anything, mock.Anything)
+	})
+})
+
+var _ = Describe("handleAdopting", func() {
+	It("releases the host without deprovisioning if the adoption gets cancelled", func() {
+		host := helpers.BareMetalHost("test-host", "default", helpers.WithSSHSpecInclPorts(23, 24))
+		host.Spec.ExternallyProvisioned = true
+		host.Spec.Status.ProvisioningState = infrav1.StateAdopting
+
+		hsm := newTestHostStateMachine(host, newTestService(host, nil, nil, nil, nil))
+		hsm.nextState = infrav1.StateAdopting
+
+		Expect(hsm.handleAdopting(context.Background())).To(BeAssignableToTypeOf(actionComplete{}))
+		Expect(hsm.nextState).To(Equal(infrav1.StateNone))
+		Expect(host.Spec.ExternallyProvisioned).To(BeTrue())
+	})
+})
+
+var _ = Describe("resetExternallyProvisioned", func() {
+	It("makes sure that the next consumer installs an image", func() {
+		host := helpers.BareMetalHost("test-host", "default")
+		host.Spec.ExternallyProvisioned = true
+		host.Spec.Status.AdoptedHostname = "node-1"
+
+		newTestService(host, nil, nil, nil, nil).resetExternallyProvisioned()
+
+		Expect(host.Spec.ExternallyProvisioned).To(BeFalse())
+		Expect(host.Spec.Status.AdoptedHostname).To(BeEmpty())
+	})
+})
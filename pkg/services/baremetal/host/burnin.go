@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+// burnInTimeout is the maximum duration of the burn-in. After that, the burn-in is considered as failed.
+const burnInTimeout = 2 * time.Hour
+
+// needsBurnIn returns true if the host has a burn-in configured that has not passed yet.
+func needsBurnIn(host *infrav1.HetznerBareMetalHost) bool {
+	return host.Spec.BurnIn != nil && (host.Spec.Status.BurnIn == nil || !host.Spec.Status.BurnIn.Passed)
+}
+
+// previous: Registering
+// next: ImageInstalling
+func (s *Service) actionBurnIn(_ context.Context) actionResult {
+	host := s.scope.HetznerBareMetalHost
+	markProvisionPending(host, infrav1.StateBurnIn)
+
+	creds := sshclient.CredentialsFromSecret(s.scope.RescueSSHSecret, s.scope.HetznerCluster.Spec.SSHKeys.RobotRescueSecretRef)
+	sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
+		PrivateKey:    creds.PrivateKey,
+		Port:          rescuePort,
+		IP:            host.Spec.Status.GetIPAddress(),
+		VerifyHostKey: s.rescueHostKeyVerifier().verify,
+		JumpHost:      s.sshJumpHost(),
+	})
+
+	state, err := sshClient.GetBurnInState()
+	if err != nil {
+		return actionError{err: fmt.Errorf("failed to get state of burn-in: %w", err)}
+	}
+
+	switch state {
+	case sshclient.BurnInStateNotStartedYet:
+		out := sshClient.StartBurnIn(sshclient.BurnInOptions{
+			SMART:           host.Spec.BurnIn.SMART,
+			MemtesterMB:     host.Spec.BurnIn.MemtesterMB,
+			MinDiskReadMBps: host.Spec.BurnIn.MinDiskReadMBps,
+			MinNICSpeedMbps: host.Spec.BurnIn.MinNICSpeedMbps,
+		})
+		if out.Err != nil || out.StdErr != "" {
+			return actionError{err: fmt.Errorf("failed to start burn-in: %s", out.String())}
+		}
+		now := metav1.Now()
+		host.Spec.Status.BurnIn = &infrav1.BurnInStatus{StartedAt: &now}
+		conditions.MarkFalse(
+			host,
+			infrav1.BurnInPassedCondition,
+			infrav1.BurnInRunningReason,
+			clusterv1.ConditionSeverityInfo,
+			"burn-in started",
+		)
+		record.Event(host, "BurnInStarted", "Started burn-in of hardware")
+		return actionContinue{delay: 30 * time.Second}
+
+	case sshclient.BurnInStateRunning:
+		if host.Spec.Status.BurnIn != nil && host.Spec.Status.BurnIn.StartedAt != nil &&
+			hasTimedOut(host.Spec.Status.BurnIn.StartedAt, burnInTimeout) {
+			return s.failBurnIn(fmt.Sprintf("burn-in did not finish within %s", burnInTimeout))
+		}
+		return actionContinue{delay: 30 * time.Second}
+	}
+
+	output, exitStatus, err := sshClient.GetResultOfBurnIn()
+	if err != nil {
+		return actionError{err: fmt.Errorf("failed to get result of burn-in: %w", err)}
+	}
+
+	results := parseBurnInResults(output)
+	if host.Spec.Status.BurnIn == nil {
+		host.Spec.Status.BurnIn = &infrav1.BurnInStatus{}
+	}
+	now := metav1.Now()
+	host.Spec.Status.BurnIn.CompletedAt = &now
+	host.Spec.Status.BurnIn.Results = results
+
+	var failed []string
+	for _, check := range enabledBurnInChecks(host.Spec.BurnIn) {
+		if !slices.ContainsFunc(results, func(result infrav1.BurnInResult) bool { return result.Check == check }) {
+			failed = append(failed, fmt.Sprintf("%s: no result", check))
+		}
+	}
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, fmt.Sprintf("%s %s: %s", result.Check, result.Target, result.Message))
+		}
+	}
+	if exitStatus != 0 && len(failed) == 0 {
+		failed = append(failed, fmt.Sprintf("burn-in.sh exited with status %d: %s", exitStatus, strings.TrimSpace(output)))
+	}
+	if len(failed) > 0 {
+		return s.failBurnIn(fmt.Sprintf("burn-in failed: %s", strings.Join(failed, "; ")))
+	}
+
+	host.Spec.Status.BurnIn.Passed = true
+	conditions.MarkTrue(host, infrav1.BurnInPassedCondition)
+	record.Eventf(host, "BurnInPassed", "All %d checks of the burn-in have passed", len(results))
+	return actionComplete{}
+}
+
+// failBurnIn marks the burn-in as failed. The host gets a permanent error, so that it is not
+// chosen by any HetznerBareMetalMachine until the permanent error annotation gets removed.
+func (s *Service) failBurnIn(msg string) actionResult {
+	conditions.MarkFalse(
+		s.scope.HetznerBareMetalHost,
+		infrav1.BurnInPassedCondition,
+		infrav1.BurnInFailedReason,
+		clusterv1.ConditionSeverityError,
+		"%s",
+		msg,
+	)
+	record.Warn(s.scope.HetznerBareMetalHost, infrav1.BurnInFailedReason, msg)
+	return s.recordActionFailure(infrav1.PermanentError, msg)
+}
+
+// enabledBurnInChecks returns the names of the checks of burn-in.sh that are enabled in the spec. Each of them
+// has to report at least one result.
+func enabledBurnInChecks(burnIn *infrav1.BurnInSpec) []string {
+	var checks []string
+	if burnIn.SMART {
+		checks = append(checks, "smart")
+	}
+	if burnIn.MemtesterMB > 0 {
+		checks = append(checks, "memtester")
+	}
+	if burnIn.MinDiskReadMBps > 0 {
+		checks = append(checks, "fio")
+	}
+	if burnIn.MinNICSpeedMbps > 0 {
+		checks = append(checks, "nic-speed")
+	}
+	return checks
+}
+
+// parseBurnInResults parses the lines "RESULT<tab>check<tab>target<tab>pass|fail<tab>message" of burn-in.sh.
+// All other lines are ignored.
+func parseBurnInResults(output string) []infrav1.BurnInResult {
+	var results []infrav1.BurnInResult
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimRight(line, "\r"), "\t", 5)
+		if len(fields) < 4 || fields[0] != "RESULT" {
+			continue
+		}
+		result := infrav1.BurnInResult{
+			Check:  fields[1],
+			Target: fields[2],
+			Passed: fields[3] == "pass",
+		}
+		if len(fields) == 5 {
+			result.Message = strings.TrimSpace(fields[4])
+		}
+		results = append(results, result)
+	}
+	return results
+}
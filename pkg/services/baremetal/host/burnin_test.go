@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	bmmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks"
+	sshmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/ssh"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("parseBurnInResults", func() {
+	It("parses the result lines and ignores other output", func() {
+		output := "INFO: memtester not installed yet. Installing memtester\n" +
+			"RESULT\tsmart\t0x5002538e09a1b2c3\tpass\tSMART health status passed\n" +
+			"RESULT\tfio\teui.002538b411b2cee8\tfail\tsequential read 80 MB/s < 500 MB/s\n" +
+			"RESULT\tnic-speed\teth0\tpass\n"
+
+		Expect(parseBurnInResults(output)).To(Equal([]infrav1.BurnInResult{
+			{Check: "smart", Target: "0x5002538e09a1b2c3", Passed: true, Message: "SMART health status passed"},
+			{Check: "fio", Target: "eui.002538b411b2cee8", Passed: false, Message: "sequential read 80 MB/s < 500 MB/s"},
+			{Check: "nic-speed", Target: "eth0", Passed: true},
+		}))
+	})
+})
+
+var _ = Describe("actionBurnIn", func() {
+	var (
+		host    *infrav1.HetznerBareMetalHost
+		sshMock *sshmock.Client
+		service *Service
+	)
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost("test-host", "default", helpers.WithIPv4(), helpers.WithConsumerRef())
+		host.Spec.BurnIn = &infrav1.BurnInSpec{SMART: true, MemtesterMB: 1024}
+		sshMock = &sshmock.Client{}
+		service = newTestService(host, nil, bmmock.NewSSHFactory(sshMock, sshMock, sshMock), nil, helpers.GetDefaultSSHSecret(rescueSSHKeyName, "default"))
+	})
+
+	It("starts the burn-in", func() {
+		sshMock.On("GetBurnInState").Return(sshclient.BurnInStateNotStartedYet, nil)
+		sshMock.On("StartBurnIn", sshclient.BurnInOptions{SMART: true, MemtesterMB: 1024}).Return(sshclient.Output{})
+
+		Expect(service.actionBurnIn(context.Background())).To(BeAssignableToTypeOf(actionContinue{}))
+		Expect(host.Spec.Status.BurnIn).ToNot(BeNil())
+		Expect(host.Spec.Status.BurnIn.StartedAt).ToNot(BeNil())
+		Expect(conditions.GetReason(host, infrav1.BurnInPassedCondition)).To(Equal(infrav1.BurnInRunningReason))
+	})
+
+	It("completes if all checks have passed", func() {
+		sshMock.On("GetBurnInState").Return(sshclient.BurnInStateFinished, nil)
+		sshMock.On("GetResultOfBurnIn").Return("RESULT\tsmart\t0x5002538e09a1b2c3\tpass\tok\nRESULT\tmemtester\t1024M\tpass\tok\n", 0, nil)
+
+		Expect(service.actionBurnIn(context.Background())).To(BeAssignableToTypeOf(actionComplete{}))
+		Expect(host.Spec.Status.BurnIn.Passed).To(BeTrue())
+		Expect(host.Spec.Status.BurnIn.Results).To(HaveLen(2))
+		Expect(conditions.IsTrue(host, infrav1.BurnInPassedCondition)).To(BeTrue())
+		Expect(needsBurnIn(host)).To(BeFalse())
+	})
+
+	It("sets a permanent error if a check has failed", func() {
+		sshMock.On("GetBurnInState").Return(sshclient.BurnInStateFinished, nil)
+		sshMock.On("GetResultOfBurnIn").Return("RESULT\tsmart\t0x5002538e09a1b2c3\tfail\tFAILED!\nRESULT\tmemtester\t1024M\tpass\tok\n", 1, nil)
+
+		Expect(service.actionBurnIn(context.Background())).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.Status.BurnIn.Passed).To(BeFalse())
+		Expect(host.Spec.Status.ErrorType).To(Equal(infrav1.PermanentError))
+		Expect(host.Annotations).To(HaveKey(infrav1.PermanentErrorAnnotation))
+		Expect(conditions.GetReason(host, infrav1.BurnInPassedCondition)).To(Equal(infrav1.BurnInFailedReason))
+		Expect(needsBurnIn(host)).To(BeTrue())
+		sshMock.AssertNotCalled(GinkgoT(), "StartBurnIn", mock.Anything)
+	})
+
+	It("fails if an enabled check did not report a result", func() {
+		sshMock.On("GetBurnInState").Return(sshclient.BurnInStateFinished, nil)
+		sshMock.On("GetResultOfBurnIn").Return("RESULT\tsmart\t0x5002538e09a1b2c3\tpass\tok\n", 0, nil)
+
+		Expect(service.actionBurnIn(context.Background())).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.Status.BurnIn.Passed).To(BeFalse())
+		Expect(host.Spec.Status.ErrorMessage).To(ContainSubstring("memtester: no result"))
+	})
+
+	It("fails if burn-in.sh exited with an error", func() {
+		sshMock.On("GetBurnInState").Return(sshclient.BurnInStateFinished, nil)
+		sshMock.On("GetResultOfBurnIn").Return("RESULT\tsmart\t0x5002538e09a1b2c3\tpass\tok\nRESULT\tmemtester\t1024M\tpass\tok\nline 42: syntax error\n", 3, nil)
+
+		Expect(service.actionBurnIn(context.Background())).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.Status.BurnIn.Passed).To(BeFalse())
+		Expect(host.Spec.Status.ErrorMessage).To(ContainSubstring("exited with status 3"))
+	})
+})
+
+var _ = Describe("handleBurnIn", func() {
+	It("goes to image installing after the burn-in has passed", func() {
+		host := helpers.BareMetalHost("test-host", "default", helpers.WithIPv4(), helpers.WithConsumerRef())
+		host.Spec.BurnIn = &infrav1.BurnInSpec{SMART: true}
+		host.Spec.Status.InstallImage = &infrav1.InstallImage{}
+		host.Spec.Status.ProvisioningState = infrav1.StateBurnIn
+		sshMock := &sshmock.Client{}
+		sshMock.On("GetBurnInState").Return(sshclient.BurnInStateFinished, nil)
+		sshMock.On("GetResultOfBurnIn").Return("RESULT\tsmart\t0x5002538e09a1b2c3\tpass\tok\n", 0, nil)
+		service := newTestService(host, nil, bmmock.NewSSHFactory(sshMock, sshMock, sshMock), nil, helpers.GetDefaultSSHSecret(rescueSSHKeyName, "default"))
+		hsm := newTestHostStateMachine(host, service)
+
+		Expect(hsm.handleBurnIn(context.Background())).To(BeAssignableToTypeOf(actionComplete{}))
+		Expect(hsm.nextState).To(Equal(infrav1.StateImageInstalling))
+	})
+})
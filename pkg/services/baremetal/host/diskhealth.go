@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+// defaultDiskHealthCheckInterval is used if the interval of the disk health check is not set.
+const defaultDiskHealthCheckInterval = time.Hour
+
+var (
+	mdstatArrayRegex  = regexp.MustCompile(`^(md\S+) : `)
+	mdstatStatusRegex = regexp.MustCompile(`\[\d+/\d+\] \[([U_]+)\]`)
+)
+
+// diskHealthCheckDue returns true if the disk health check is enabled and the interval has passed since the last check.
+func diskHealthCheckDue(host *infrav1.HetznerBareMetalHost) bool {
+	if host.Spec.DiskHealthCheck == nil {
+		return false
+	}
+	if host.Spec.Status.LastDiskHealthCheck == nil {
+		return true
+	}
+	interval := host.Spec.DiskHealthCheck.Interval.Duration
+	if interval == 0 {
+		interval = defaultDiskHealthCheckInterval
+	}
+	return hasTimedOut(host.Spec.Status.LastDiskHealthCheck, interval)
+}
+
+// checkDiskHealth checks the SMART status of the root devices and whether a software RAID is degraded. The
+// result is reported in the DiskHealthy condition. If a disk is unhealthy, the CAPI Machine gets marked for
+// remediation if this is enabled.
+func (s *Service) checkDiskHealth(ctx context.Context, sshClient sshclient.Client) error {
+	host := s.scope.HetznerBareMetalHost
+	now := metav1.Now()
+	host.Spec.Status.LastDiskHealthCheck = &now
+
+	var problems []string
+	var reason string
+
+	if host.Spec.RootDeviceHints != nil {
+		if _, err := sshClient.CheckDisk(ctx, host.Spec.RootDeviceHints.ListOfWWN()); err != nil {
+			if !errors.Is(err, sshclient.ErrCheckDiskBrokenDisk) {
+				conditions.MarkUnknown(host, infrav1.DiskHealthyCondition, infrav1.DiskHealthCheckFailedReason, "%s", err.Error())
+				return nil
+			}
+			problems = append(problems, err.Error())
+			reason = infrav1.DiskUnhealthyReason
+		}
+	}
+
+	out := sshClient.GetRaidStatus()
+	if out.Err != nil {
+		conditions.MarkUnknown(host, infrav1.DiskHealthyCondition, infrav1.DiskHealthCheckFailedReason,
+			"failed to get status of software RAID: %s", out.String())
+		return nil
+	}
+	if degraded := degradedRaidArrays(out.StdOut); len(degraded) > 0 {
+		problems = append(problems, fmt.Sprintf("software RAID degraded: %s", strings.Join(degraded, ", ")))
+		if reason == "" {
+			reason = infrav1.RaidDegradedReason
+		}
+	}
+
+	if len(problems) == 0 {
+		conditions.MarkTrue(host, infrav1.DiskHealthyCondition)
+		return nil
+	}
+
+	msg := strings.Join(problems, "; ")
+	if !conditions.IsFalse(host, infrav1.DiskHealthyCondition) {
+		record.Warnf(host, reason, "Disks of host are unhealthy: %s", msg)
+	}
+	conditions.MarkFalse(host, infrav1.DiskHealthyCondition, reason, clusterv1.ConditionSeverityError, "%s", msg)
+
+	if host.Spec.DiskHealthCheck.RemediateMachine {
+		return s.remediateMachine(ctx, msg)
+	}
+	return nil
+}
+
+// remediateMachine sets the remediate-machine annotation on the CAPI Machine of the host, so that a
+// MachineHealthCheck replaces it.
+func (s *Service) remediateMachine(ctx context.Context, msg string) error {
+	if s.scope.HetznerBareMetalMachine == nil {
+		return nil
+	}
+
+	machine, err := util.GetOwnerMachine(ctx, s.scope.Client, s.scope.HetznerBareMetalMachine.ObjectMeta)
+	if err != nil {
+		return fmt.Errorf("failed to get capi machine: %w", err)
+	}
+	if machine == nil || annotations.HasRemediateMachine(machine) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(machine, s.scope.Client)
+	if err != nil {
+		return fmt.Errorf("failed to init patch helper: %s %s/%s %w", machine.Kind, machine.Namespace, machine.Name, err)
+	}
+
+	annotations.AddAnnotations(machine, map[string]string{clusterv1.RemediateMachineAnnotation: ""})
+
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return fmt.Errorf("failed to patch: %s %s/%s %w", machine.Kind, machine.Namespace, machine.Name, err)
+	}
+	record.Warnf(s.scope.HetznerBareMetalHost, "MachineMarkedForRemediation",
+		"Marked machine %s for remediation: %s", machine.Name, msg)
+	return nil
+}
+
+// degradedRaidArrays returns the names of the arrays in /proc/mdstat that miss at least one member.
+func degradedRaidArrays(mdstat string) []string {
+	var degraded []string
+	var array string
+	for _, line := range strings.Split(mdstat, "\n") {
+		if match := mdstatArrayRegex.FindStringSubmatch(line); match != nil {
+			array = match[1]
+			continue
+		}
+		if array == "" {
+			continue
+		}
+		if match := mdstatStatusRegex.FindStringSubmatch(line); match != nil {
+			if strings.Contains(match[1], "_") {
+				degraded = append(degraded, array)
+			}
+			array = ""
+		}
+	}
+	return degraded
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/ssh"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+const (
+	mdstatHealthy = `Personalities : [raid1]
+md2 : active raid1 nvme0n1p3[0] nvme1n1p3[1]
+      475708736 blocks super 1.2 [2/2] [UU]
+      bitmap: 3/4 pages [12KB], 65536KB chunk
+
+md0 : active raid1 nvme1n1p1[1] nvme0n1p1[0]
+      33521664 blocks super 1.2 [2/2] [UU]
+
+unused devices: <none>
+`
+	mdstatDegraded = `Personalities : [raid1]
+md2 : active raid1 nvme0n1p3[0] nvme1n1p3[1](F)
+      475708736 blocks super 1.2 [2/1] [U_]
+      bitmap: 3/4 pages [12KB], 65536KB chunk
+
+md0 : active raid1 nvme1n1p1[1] nvme0n1p1[0]
+      33521664 blocks super 1.2 [2/2] [UU]
+
+unused devices: <none>
+`
+)
+
+var _ = Describe("degradedRaidArrays", func() {
+	DescribeTable("degradedRaidArrays",
+		func(mdstat string, expected []string) {
+			Expect(degradedRaidArrays(mdstat)).To(Equal(expected))
+		},
+		Entry("healthy", mdstatHealthy, nil),
+		Entry("degraded", mdstatDegraded, []string{"md2"}),
+		Entry("no software RAID", "Personalities :\nunused devices: <none>\n", nil),
+	)
+})
+
+var _ = Describe("diskHealthCheckDue", func() {
+	It("is only due if enabled and the interval has passed", func() {
+		host := helpers.BareMetalHost("test-host", "default")
+		Expect(diskHealthCheckDue(host)).To(BeFalse())
+
+		host.Spec.DiskHealthCheck = &infrav1.DiskHealthCheckSpec{Interval: metav1.Duration{Duration: 10 * time.Minute}}
+		Expect(diskHealthCheckDue(host)).To(BeTrue())
+
+		lastCheck := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+		host.Spec.Status.LastDiskHealthCheck = &lastCheck
+		Expect(diskHealthCheckDue(host)).To(BeFalse())
+
+		lastCheck = metav1.NewTime(time.Now().Add(-15 * time.Minute))
+		Expect(diskHealthCheckDue(host)).To(BeTrue())
+	})
+})
+
+var _ = Describe("checkDiskHealth", func() {
+	var (
+		host    *infrav1.HetznerBareMetalHost
+		sshMock *sshmock.Client
+		service *Service
+	)
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost("test-host", "default",
+			helpers.WithRootDeviceHintRaid(),
+			helpers.WithConsumerRef(),
+		)
+		host.Spec.DiskHealthCheck = &infrav1.DiskHealthCheckSpec{}
+		sshMock = &sshmock.Client{}
+		service = newTestService(host, nil, nil, nil, nil)
+	})
+
+	It("marks the disks as healthy", func() {
+		sshMock.On("CheckDisk", mock.Anything, host.Spec.RootDeviceHints.ListOfWWN()).Return("PASSED", nil)
+		sshMock.On("GetRaidStatus").Return(sshclient.Output{StdOut: mdstatHealthy})
+
+		Expect(service.checkDiskHealth(context.Background(), sshMock)).To(Succeed())
+		Expect(conditions.IsTrue(host, infrav1.DiskHealthyCondition)).To(BeTrue())
+		Expect(host.Spec.Status.LastDiskHealthCheck).ToNot(BeNil())
+	})
+
+	It("reports a degraded software RAID", func() {
+		sshMock.On("CheckDisk", mock.Anything, mock.Anything).Return("PASSED", nil)
+		sshMock.On("GetRaidStatus").Return(sshclient.Output{StdOut: mdstatDegraded})
+
+		Expect(service.checkDiskHealth(context.Background(), sshMock)).To(Succeed())
+		Expect(conditions.IsFalse(host, infrav1.DiskHealthyCondition)).To(BeTrue())
+		Expect(conditions.GetReason(host, infrav1.DiskHealthyCondition)).To(Equal(infrav1.RaidDegradedReason))
+	})
+
+	It("reports a broken disk", func() {
+		sshMock.On("CheckDisk", mock.Anything, mock.Anything).Return("", fmt.Errorf("FAILED: %w", sshclient.ErrCheckDiskBrokenDisk))
+		sshMock.On("GetRaidStatus").Return(sshclient.Output{StdOut: mdstatDegraded})
+
+		Expect(service.checkDiskHealth(context.Background(), sshMock)).To(Succeed())
+		Expect(conditions.GetReason(host, infrav1.DiskHealthyCondition)).To(Equal(infrav1.DiskUnhealthyReason))
+	})
+
+	It("sets the condition to unknown if the check fails", func() {
+		sshMock.On("CheckDisk", mock.Anything, mock.Anything).Return("", errTimeout)
+
+		Expect(service.checkDiskHealth(context.Background(), sshMock)).To(Succeed())
+		Expect(conditions.IsUnknown(host, infrav1.DiskHealthyCondition)).To(BeTrue())
+		Expect(conditions.GetReason(host, infrav1.DiskHealthyCondition)).To(Equal(infrav1.DiskHealthCheckFailedReason))
+		sshMock.AssertNotCalled(GinkgoT(), "GetRaidStatus")
+	})
+
+	It("marks the machine for remediation", func() {
+		host.Spec.DiskHealthCheck.RemediateMachine = true
+		machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"}}
+		service.scope.HetznerBareMetalMachine = &infrav1.HetznerBareMetalMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bm-machine",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: clusterv1.GroupVersion.String(),
+					Kind:       "Machine",
+					Name:       machine.Name,
+				}},
+			},
+		}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clusterv1.AddToScheme(scheme))
+		service.scope.Client = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(machine).Build()
+
+		sshMock.On("CheckDisk", mock.Anything, mock.Anything).Return("PASSED", nil)
+		sshMock.On("GetRaidStatus").Return(sshclient.Output{StdOut: mdstatDegraded})
+
+		Expect(service.checkDiskHealth(context.Background(), sshMock)).To(Succeed())
+
+		var updatedMachine clusterv1.Machine
+		Expect(service.scope.Client.Get(context.Background(), client.ObjectKeyFromObject(machine), &updatedMachine)).To(Succeed())
+		Expect(updatedMachine.Annotations).To(HaveKey(clusterv1.RemediateMachineAnnotation))
+	})
+})
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+// diskImageChecksumMismatchExitStatus is the exit status of write-disk-image.sh if the checksum does not match.
+const diskImageChecksumMismatchExitStatus = 1
+
+// actionImageInstallingDiskImage writes the disk image onto the root device instead of running installimage.
+// The image is written in the background, like installimage. Afterwards, the server reboots into the image, and
+// cloud-init reads the bootstrap data from the seed partition that got created next to the image.
+func (s *Service) actionImageInstallingDiskImage(ctx context.Context, sshClient sshclient.Client) actionResult {
+	state, err := sshClient.GetWriteDiskImageState()
+	if err != nil {
+		return actionError{err: fmt.Errorf("failed to get state of writing disk image: %w", err)}
+	}
+
+	switch state {
+	case sshclient.DiskImageStateRunning:
+		s.scope.Logger.Info("disk image is still being written. Checking again in some seconds.")
+		return actionContinue{delay: 10 * time.Second}
+	case sshclient.DiskImageStateFinished:
+		return s.actionImageInstallingDiskImageFinished(ctx, sshClient)
+	}
+	return s.startWriteDiskImage(ctx, sshClient)
+}
+
+func (s *Service) startWriteDiskImage(ctx context.Context, sshClient sshclient.Client) actionResult {
+	host := s.scope.HetznerBareMetalHost
+	diskImage := host.Spec.Status.InstallImage.DiskImage
+	if diskImage == nil || diskImage.URL == "" {
+		return s.failDiskImageSpec("no disk image specified for install method " + string(infrav1.InstallMethodDiskImage))
+	}
+
+	// The vSwitch is configured by the post install script of installimage, which only knows the network
+	// configuration written by installimage.
+	if s.scope.HetznerCluster.Spec.HCloudNetwork.Enabled && s.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch != nil {
+		return s.failDiskImageSpec("install method " + string(infrav1.InstallMethodDiskImage) + " does not support the vSwitch")
+	}
+
+	if actionRes := s.imageInstallingPreflightChecks(ctx, sshClient); actionRes != nil {
+		return actionRes
+	}
+
+	userData, err := s.scope.GetRawBootstrapData(ctx)
+	if err != nil {
+		return actionError{err: fmt.Errorf("failed to get user data: %w", err)}
+	}
+
+	creds := sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, host.Spec.Status.SSHSpec.SecretRef)
+
+	out := sshClient.StartWriteDiskImage(sshclient.DiskImageOptions{
+		URL:               diskImage.URL,
+		Format:            string(diskImage.GetFormat()),
+		Compression:       diskImage.GetCompression(),
+		Checksum:          diskImage.Checksum,
+		SkipGrowth:        diskImage.SkipPartitionGrowth,
+		WWNs:              host.Spec.RootDeviceHints.ListOfWWN(),
+		MetaData:          diskImageMetaData(s.scope.Hostname(), creds.PublicKey),
+		UserData:          userData,
+		PostInstallScript: host.Spec.Status.InstallImage.PostInstallScript,
+	})
+	if out.Err != nil || out.StdErr != "" {
+		return actionError{err: fmt.Errorf("failed to start writing disk image: %s", out.String())}
+	}
+	record.Event(host, "WriteDiskImageStarted", diskImage.String())
+
+	s.osHostKeyVerifier().reset()
+	s.startProvisioningAttempt()
+	return actionContinue{delay: 10 * time.Second}
+}
+
+func (s *Service) actionImageInstallingDiskImageFinished(ctx context.Context, sshClient sshclient.Client) actionResult {
+	host := s.scope.HetznerBareMetalHost
+
+	output, exitStatus, err := sshClient.GetResultOfWriteDiskImage()
+	if err != nil {
+		return actionError{err: fmt.Errorf("failed to get result of writing disk image: %w", err)}
+	}
+	s.storeProvisioningLog(ctx, provisioningLogDiskImage, output)
+
+	if exitStatus != 0 {
+		return s.failWriteDiskImage(sshClient, output, exitStatus)
+	}
+
+	record.Event(host, "WriteDiskImageOutput", redactCredentials(output))
+
+	// Update name in robot API
+	if _, err := s.scope.RobotClient.SetBMServerName(host.Spec.ServerID, s.scope.Hostname()); err != nil {
+		record.Warn(host, "SetBMServerNameFailed", err.Error())
+		s.handleRobotRateLimitExceeded(err, "SetBMServerName")
+		return actionError{err: fmt.Errorf("failed to update name of host in robot API: %w", err)}
+	}
+
+	if err := handleSSHError(sshClient.Reboot()); err != nil {
+		err = fmt.Errorf("failed to reboot server (after writing disk image): %w", err)
+		record.Warn(host, "RebootFailed", err.Error())
+		return actionError{err: err}
+	}
+	createSSHRebootEvent(host, "disk image and cloud-init data got written")
+
+	host.ClearError()
+	return actionComplete{} // next: ensure-provisioned
+}
+
+// failWriteDiskImage records the failure of writing the disk image. The result gets removed from the rescue
+// system, so that the disk image is written again after the backoff.
+func (s *Service) failWriteDiskImage(sshClient sshclient.Client, output string, exitStatus int) actionResult {
+	host := s.scope.HetznerBareMetalHost
+
+	if err := handleSSHError(sshClient.ResetWriteDiskImage()); err != nil {
+		return actionError{err: fmt.Errorf("failed to reset writing disk image: %w", err)}
+	}
+
+	msg := fmt.Sprintf("writing disk image failed with exit status %d", exitStatus)
+	if exitStatus == diskImageChecksumMismatchExitStatus {
+		msg = fmt.Sprintf("checksum of disk image %s does not match", host.Spec.Status.InstallImage.DiskImage)
+		conditions.MarkFalse(
+			host,
+			infrav1.ProvisionSucceededCondition,
+			infrav1.DiskImageChecksumMismatchReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			msg,
+		)
+		record.Warn(host, infrav1.DiskImageChecksumMismatchReason, redactCredentials(output))
+	} else {
+		record.Warn(host, "WriteDiskImageFailed", redactCredentials(output))
+	}
+	return s.recordActionFailure(infrav1.ProvisioningError, msg)
+}
+
+// failDiskImageSpec marks the disk image of the host as invalid.
+func (s *Service) failDiskImageSpec(msg string) actionResult {
+	conditions.MarkFalse(
+		s.scope.HetznerBareMetalHost,
+		infrav1.ProvisionSucceededCondition,
+		infrav1.ImageSpecInvalidReason,
+		clusterv1.ConditionSeverityError,
+		"%s",
+		msg,
+	)
+	return s.recordActionFailure(infrav1.ProvisioningError, msg)
+}
+
+// diskImageMetaData returns the meta data of the NoCloud data source of cloud-init. The public key of the
+// OS SSH secret is added, so that cloud-init authorizes it.
+func diskImageMetaData(hostName, publicKey string) string {
+	metaData := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", hostName, hostName)
+	if publicKey = strings.TrimSpace(publicKey); publicKey != "" {
+		metaData += fmt.Sprintf("public-keys:\n  - %s\n", publicKey)
+	}
+	return metaData
+}
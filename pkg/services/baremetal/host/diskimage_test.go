@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
+	sshmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/ssh"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("actionImageInstallingDiskImage", func() {
+	var (
+		host      *infrav1.HetznerBareMetalHost
+		robotMock *robotmock.Client
+		sshMock   *sshmock.Client
+		service   *Service
+	)
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost(
+			"test-host",
+			"default",
+			helpers.WithSSHSpecInclPorts(23, 24),
+			helpers.WithRootDeviceHintWWN(),
+			helpers.WithConsumerRef(),
+		)
+		host.Spec.Status.ProvisioningState = infrav1.StateImageInstalling
+		host.Spec.Status.InstallImage = &infrav1.InstallImage{
+			Method: infrav1.InstallMethodDiskImage,
+			DiskImage: &infrav1.DiskImage{
+				URL:      "https://example.com/golden-image.raw.xz",
+				Checksum: "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+			},
+		}
+		host.Spec.Status.UserData = &corev1.SecretReference{Name: "bootstrap-data", Namespace: "default"}
+
+		robotMock = &robotmock.Client{}
+		sshMock = &sshmock.Client{}
+		service = newTestService(host, robotMock, nil, helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), nil)
+		service.scope.SecretManager = secretutil.NewSecretManager(log, service.scope.Client, service.scope.Client)
+		Expect(service.scope.Client.Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-data", Namespace: "default"},
+			Data:       map[string][]byte{"value": []byte("#cloud-config\n")},
+		})).To(Succeed())
+	})
+
+	It("starts writing the disk image onto the root device", func() {
+		sshMock.On("GetWriteDiskImageState").Return(sshclient.DiskImageStateNotStartedYet, nil)
+		sshMock.On("CheckDisk", mock.Anything, host.Spec.RootDeviceHints.ListOfWWN()).Return("PASSED", nil)
+		sshMock.On("DetectLinuxOnAnotherDisk", mock.Anything).Return(sshclient.Output{})
+		sshMock.On("StartWriteDiskImage", mock.MatchedBy(func(opts sshclient.DiskImageOptions) bool {
+			return opts.URL == "https://example.com/golden-image.raw.xz" &&
+				opts.Format == "raw" &&
+				opts.Compression == "xz" &&
+				!opts.SkipGrowth &&
+				len(opts.WWNs) == 1 && opts.WWNs[0] == helpers.DefaultWWN &&
+				strings.Contains(opts.MetaData, "local-hostname: "+service.scope.Hostname()) &&
+				strings.Contains(opts.MetaData, "- my-public-key") &&
+				string(opts.UserData) == "#cloud-config\n"
+		})).Return(sshclient.Output{})
+
+		Expect(service.actionImageInstallingDiskImage(context.Background(), sshMock)).To(BeAssignableToTypeOf(actionContinue{}))
+		sshMock.AssertCalled(GinkgoT(), "StartWriteDiskImage", mock.Anything)
+	})
+
+	It("does not start if the vSwitch is enabled", func() {
+		service.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch = &infrav1.HCloudNetworkVSwitchSpec{VLANID: 4000}
+		sshMock.On("GetWriteDiskImageState").Return(sshclient.DiskImageStateNotStartedYet, nil)
+
+		Expect(service.actionImageInstallingDiskImage(context.Background(), sshMock)).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(conditions.GetReason(host, infrav1.ProvisionSucceededCondition)).To(Equal(infrav1.ImageSpecInvalidReason))
+		sshMock.AssertNotCalled(GinkgoT(), "StartWriteDiskImage", mock.Anything)
+	})
+
+	It("waits as long as the disk image is being written", func() {
+		sshMock.On("GetWriteDiskImageState").Return(sshclient.DiskImageStateRunning, nil)
+
+		Expect(service.actionImageInstallingDiskImage(context.Background(), sshMock)).To(BeAssignableToTypeOf(actionContinue{}))
+	})
+
+	It("reboots into the disk image once it has been written", func() {
+		sshMock.On("GetWriteDiskImageState").Return(sshclient.DiskImageStateFinished, nil)
+		sshMock.On("GetResultOfWriteDiskImage").Return("INFO: Disk image was written to /dev/sda", 0, nil)
+		sshMock.On("Reboot").Return(sshclient.Output{})
+		robotMock.On("SetBMServerName", mock.Anything, mock.Anything).Return(nil, nil)
+
+		Expect(service.actionImageInstallingDiskImage(context.Background(), sshMock)).To(BeAssignableToTypeOf(actionComplete{}))
+		sshMock.AssertCalled(GinkgoT(), "Reboot")
+	})
+
+	It("records a failure and resets the result if the checksum does not match", func() {
+		sshMock.On("GetWriteDiskImageState").Return(sshclient.DiskImageStateFinished, nil)
+		sshMock.On("GetResultOfWriteDiskImage").Return("ERROR: Checksum mismatch", diskImageChecksumMismatchExitStatus, nil)
+		sshMock.On("ResetWriteDiskImage").Return(sshclient.Output{})
+
+		Expect(service.actionImageInstallingDiskImage(context.Background(), sshMock)).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(conditions.GetReason(host, infrav1.ProvisionSucceededCondition)).To(Equal(infrav1.DiskImageChecksumMismatchReason))
+		Expect(host.Spec.Status.ErrorType).To(Equal(infrav1.ProvisioningError))
+		sshMock.AssertCalled(GinkgoT(), "ResetWriteDiskImage")
+		sshMock.AssertNotCalled(GinkgoT(), "Reboot")
+	})
+
+	It("records a failure and resets the result if writing the disk image failed", func() {
+		sshMock.On("GetWriteDiskImageState").Return(sshclient.DiskImageStateFinished, nil)
+		sshMock.On("GetResultOfWriteDiskImage").Return("ERROR: Download failed", 3, nil)
+		sshMock.On("ResetWriteDiskImage").Return(sshclient.Output{})
+
+		Expect(service.actionImageInstallingDiskImage(context.Background(), sshMock)).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.Status.ErrorMessage).To(Equal("writing disk image failed with exit status 3"))
+		sshMock.AssertCalled(GinkgoT(), "ResetWriteDiskImage")
+		sshMock.AssertNotCalled(GinkgoT(), "Reboot")
+	})
+
+	It("gives an error if the result cannot be reset", func() {
+		sshMock.On("GetWriteDiskImageState").Return(sshclient.DiskImageStateFinished, nil)
+		sshMock.On("GetResultOfWriteDiskImage").Return("ERROR: Download failed", 3, nil)
+		sshMock.On("ResetWriteDiskImage").Return(sshclient.Output{Err: sshclient.ErrConnectionRefused})
+
+		Expect(service.actionImageInstallingDiskImage(context.Background(), sshMock)).To(BeAssignableToTypeOf(actionError{}))
+		Expect(host.Spec.Status.ErrorType).To(BeEmpty())
+	})
+})
+
+var _ = Describe("diskImageMetaData", func() {
+	It("contains the hostname and the public key", func() {
+		Expect(diskImageMetaData("bm-node-1", "ssh-ed25519 AAAA\n")).To(Equal(
+			"instance-id: bm-node-1\nlocal-hostname: bm-node-1\npublic-keys:\n  - ssh-ed25519 AAAA\n"))
+	})
+
+	It("does not contain public keys if there is none", func() {
+		Expect(diskImageMetaData("bm-node-1", "")).To(Equal("instance-id: bm-node-1\nlocal-hostname: bm-node-1\n"))
+	})
+})
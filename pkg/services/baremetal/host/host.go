@@ -46,6 +46,7 @@ import (
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/userdata"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
 )
 
@@ -69,6 +70,9 @@ const (
 	// PostInstallScriptFinished is a marker in the output of installimage. If it is not present,
 	// then install-image failed.
 	PostInstallScriptFinished = "POST_INSTALL_SCRIPT_FINISHED"
+
+	// userDataCompressionThreshold is the size of user data above which it is compressed in the post install script.
+	userDataCompressionThreshold = userdata.MaxHCloudSize
 )
 
 var (
@@ -172,29 +176,11 @@ func SaveHostAndReturn(ctx context.Context, cl client.Client, host *infrav1.Hetz
 func (s *Service) actionPreparing(_ context.Context) actionResult {
 	markProvisionPending(s.scope.HetznerBareMetalHost, infrav1.StatePreparing)
 
-	server, err := s.scope.RobotClient.GetBMServer(s.scope.HetznerBareMetalHost.Spec.ServerID)
-	if err != nil {
-		s.handleRobotRateLimitExceeded(err, "GetBMServer")
-		if models.IsError(err, models.ErrorCodeServerNotFound) {
-			msg := "bare metal host not found"
-			conditions.MarkFalse(
-				s.scope.HetznerBareMetalHost,
-				infrav1.ProvisionSucceededCondition,
-				infrav1.ServerNotFoundReason,
-				clusterv1.ConditionSeverityError,
-				"%s",
-				msg,
-			)
-			record.Warnf(s.scope.HetznerBareMetalHost, infrav1.ServerNotFoundReason, msg)
-			s.scope.HetznerBareMetalHost.SetError(infrav1.PermanentError, msg)
-			return actionStop{}
-		}
-		return actionError{err: fmt.Errorf("failed to get bare metal server: %w", err)}
+	server, actResult := s.updateRobotServerDetails()
+	if _, isComplete := actResult.(actionComplete); !isComplete {
+		return actResult
 	}
 
-	s.scope.HetznerBareMetalHost.Spec.Status.IPv4 = server.ServerIP
-	s.scope.HetznerBareMetalHost.Spec.Status.IPv6 = server.ServerIPv6Net + "1"
-
 	sshKey, actResult := s.ensureSSHKey(s.scope.HetznerCluster.Spec.SSHKeys.RobotRescueSecretRef, s.scope.RescueSSHSecret)
 	if _, isComplete := actResult.(actionComplete); !isComplete {
 		return actResult
@@ -202,19 +188,8 @@ func (s *Service) actionPreparing(_ context.Context) actionResult {
 
 	s.scope.HetznerBareMetalHost.Spec.Status.SSHStatus.RescueKey = &sshKey
 
-	// Populate reboot methods in status
-	if len(s.scope.HetznerBareMetalHost.Spec.Status.RebootTypes) == 0 {
-		reboot, err := s.scope.RobotClient.GetReboot(s.scope.HetznerBareMetalHost.Spec.ServerID)
-		if err != nil {
-			s.handleRobotRateLimitExceeded(err, "GetReboot")
-			return actionError{err: fmt.Errorf("failed to get reboot: %w", err)}
-		}
-
-		rebootTypes, err := rebootTypesFromStringList(reboot.Type)
-		if err != nil {
-			return actionError{err: fmt.Errorf("failed to unmarshal: %w", err)}
-		}
-		s.scope.HetznerBareMetalHost.Spec.Status.RebootTypes = rebootTypes
+	if err := s.ensureRebootTypes(); err != nil {
+		return actionError{err: err}
 	}
 
 	// if there is no rescue system, we cannot provision the server
@@ -238,9 +213,11 @@ func (s *Service) actionPreparing(_ context.Context) actionResult {
 	}
 
 	sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
-		PrivateKey: sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
-		Port:       s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
-		IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		PrivateKey:    sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
+		Port:          s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
+		IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		VerifyHostKey: s.osHostKeyVerifier().verify,
+		JumpHost:      s.sshJumpHost(),
 	})
 
 	// Check hostname with sshClient
@@ -273,7 +250,60 @@ func (s *Service) actionPreparing(_ context.Context) actionResult {
 	return actionComplete{} // next: Registering
 }
 
+// updateRobotServerDetails gets the server from the robot API and updates the IPs and labels of the host.
+func (s *Service) updateRobotServerDetails() (*models.Server, actionResult) {
+	server, err := s.scope.RobotClient.GetBMServer(s.scope.HetznerBareMetalHost.Spec.ServerID)
+	if err != nil {
+		s.handleRobotRateLimitExceeded(err, "GetBMServer")
+		if models.IsError(err, models.ErrorCodeServerNotFound) {
+			msg := "bare metal host not found"
+			conditions.MarkFalse(
+				s.scope.HetznerBareMetalHost,
+				infrav1.ProvisionSucceededCondition,
+				infrav1.ServerNotFoundReason,
+				clusterv1.ConditionSeverityError,
+				"%s",
+				msg,
+			)
+			record.Warnf(s.scope.HetznerBareMetalHost, infrav1.ServerNotFoundReason, msg)
+			s.scope.HetznerBareMetalHost.SetError(infrav1.PermanentError, msg)
+			return nil, actionStop{}
+		}
+		return nil, actionError{err: fmt.Errorf("failed to get bare metal server: %w", err)}
+	}
+
+	s.scope.HetznerBareMetalHost.Spec.Status.IPv4 = server.ServerIP
+	s.scope.HetznerBareMetalHost.Spec.Status.IPv6 = server.ServerIPv6Net + "1"
+	SetRobotServerLabels(s.scope.HetznerBareMetalHost, server)
+
+	return server, actionComplete{}
+}
+
+// ensureRebootTypes populates the reboot types of the robot API in the status of the host.
+func (s *Service) ensureRebootTypes() error {
+	if len(s.scope.HetznerBareMetalHost.Spec.Status.RebootTypes) > 0 {
+		return nil
+	}
+
+	reboot, err := s.scope.RobotClient.GetReboot(s.scope.HetznerBareMetalHost.Spec.ServerID)
+	if err != nil {
+		s.handleRobotRateLimitExceeded(err, "GetReboot")
+		return fmt.Errorf("failed to get reboot: %w", err)
+	}
+
+	rebootTypes, err := rebootTypesFromStringList(reboot.Type)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal: %w", err)
+	}
+	s.scope.HetznerBareMetalHost.Spec.Status.RebootTypes = rebootTypes
+	return nil
+}
+
 func (s *Service) enforceRescueMode() error {
+	if err := s.cancelRobotLinuxInstall(); err != nil {
+		return err
+	}
+
 	// delete old rescue activations if exist, as the ssh key might have changed in between
 	if _, err := s.scope.RobotClient.DeleteBootRescue(s.scope.HetznerBareMetalHost.Spec.ServerID); err != nil {
 		s.handleRobotRateLimitExceeded(err, "DeleteBootRescue")
@@ -287,6 +317,8 @@ func (s *Service) enforceRescueMode() error {
 		s.handleRobotRateLimitExceeded(err, "SetBootRescue")
 		return fmt.Errorf("failed to set boot rescue: %w", err)
 	}
+	// the rescue system gets booted again, so the host key of the rescue system has to be pinned again
+	s.scope.HetznerBareMetalHost.Spec.Status.SSHStatus.RescueHostKeyFingerprint = ""
 	return nil
 }
 
@@ -575,6 +607,8 @@ func (s *Service) ensureRescueMode() error {
 			return fmt.Errorf("failed to set boot rescue: %w", err)
 		}
 	}
+	// the server gets rebooted into the rescue system, so the host key of the rescue system has to be pinned again
+	s.scope.HetznerBareMetalHost.Spec.Status.SSHStatus.RescueHostKeyFingerprint = ""
 	return nil
 }
 
@@ -584,10 +618,13 @@ func (s *Service) actionRegistering(_ context.Context) actionResult {
 	markProvisionPending(s.scope.HetznerBareMetalHost, infrav1.StateRegistering)
 
 	creds := sshclient.CredentialsFromSecret(s.scope.RescueSSHSecret, s.scope.HetznerCluster.Spec.SSHKeys.RobotRescueSecretRef)
+	hostKeyVerifier := s.rescueHostKeyVerifier()
 	in := sshclient.Input{
-		PrivateKey: creds.PrivateKey,
-		Port:       rescuePort,
-		IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		PrivateKey:    creds.PrivateKey,
+		Port:          rescuePort,
+		IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		VerifyHostKey: hostKeyVerifier.verify,
+		JumpHost:      s.sshJumpHost(),
 	}
 	sshClient := s.scope.SSHClientFactory.NewClient(in)
 
@@ -601,6 +638,9 @@ func (s *Service) actionRegistering(_ context.Context) actionResult {
 		}
 
 		isSSHTimeoutError, isSSHConnectionRefusedError, err := s.analyzeSSHOutputRegistering(out)
+		if sshclient.IsHostKeyMismatchError(err) {
+			return s.failHostKeyMismatch(err)
+		}
 		if err != nil {
 			// This can happen if the bare-metal server was taken by another mgt-cluster.
 			// Check in https://robot.hetzner.com/server for the "History" of the server.
@@ -623,6 +663,9 @@ func (s *Service) actionRegistering(_ context.Context) actionResult {
 		return actionContinue{delay: 10 * time.Second}
 	}
 
+	// from now on we know that we talk to the rescue system
+	hostKeyVerifier.trust()
+
 	output := sshClient.GetHardwareDetailsDebug()
 	if output.Err != nil {
 		return actionError{err: fmt.Errorf("failed to obtain hardware for debugging: %w", output.Err)}
@@ -641,6 +684,26 @@ func (s *Service) actionRegistering(_ context.Context) actionResult {
 		s.scope.HetznerBareMetalHost.Spec.Status.HardwareDetails = &hardwareDetails
 	}
 
+	if s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.UsesRobotLinux() {
+		// The installer of the robot API chooses the disks itself, so no root device hints are needed.
+		s.scope.HetznerBareMetalHost.ClearError()
+		return actionComplete{} // next: ImageInstalling
+	}
+
+	if s.scope.HetznerBareMetalHost.Spec.RootDeviceHints == nil && s.scope.HetznerBareMetalHost.Spec.RootDeviceHintsPolicy != nil {
+		if err := s.selectRootDeviceHints(); err != nil {
+			conditions.MarkFalse(
+				s.scope.HetznerBareMetalHost,
+				infrav1.RootDeviceHintsValidatedCondition,
+				infrav1.ValidationFailedReason,
+				clusterv1.ConditionSeverityError,
+				"%s",
+				err.Error(),
+			)
+			return s.recordActionFailure(infrav1.RegistrationError, err.Error())
+		}
+	}
+
 	if s.scope.HetznerBareMetalHost.Spec.RootDeviceHints == nil {
 		conditions.MarkFalse(
 			s.scope.HetznerBareMetalHost,
@@ -795,6 +858,8 @@ func (s *Service) analyzeSSHErrorRegistering(sshErr error) (isSSHTimeoutError, i
 		reterr = fmt.Errorf("wrong ssh key: %w", sshErr)
 	case sshclient.IsConnectionRefusedError(sshErr):
 		isConnectionRefused = true
+	case sshclient.IsHostKeyMismatchError(sshErr):
+		reterr = sshErr
 
 	default:
 		reterr = fmt.Errorf("unhandled ssh error while getting hostname: %w", sshErr)
@@ -1070,11 +1135,17 @@ func validateStdOut(stdOut string) (string, error) {
 func (s *Service) actionImageInstalling(ctx context.Context) actionResult {
 	markProvisionPending(s.scope.HetznerBareMetalHost, infrav1.StateImageInstalling)
 
+	if s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.UsesRobotLinux() {
+		return s.actionImageInstallingRobotLinux(ctx)
+	}
+
 	creds := sshclient.CredentialsFromSecret(s.scope.RescueSSHSecret, s.scope.HetznerCluster.Spec.SSHKeys.RobotRescueSecretRef)
 	in := sshclient.Input{
-		PrivateKey: creds.PrivateKey,
-		Port:       rescuePort,
-		IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		PrivateKey:    creds.PrivateKey,
+		Port:          rescuePort,
+		IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		VerifyHostKey: s.rescueHostKeyVerifier().verify,
+		JumpHost:      s.sshJumpHost(),
 	}
 	sshClient := s.scope.SSHClientFactory.NewClient(in)
 
@@ -1085,6 +1156,11 @@ func (s *Service) actionImageInstalling(ctx context.Context) actionResult {
 	}
 
 	s.scope.HetznerBareMetalHost.Spec.Status.SSHStatus.OSKey = &sshKey
+
+	if s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.UsesDiskImage() {
+		return s.actionImageInstallingDiskImage(ctx, sshClient)
+	}
+
 	state, err := sshClient.GetInstallImageState()
 	if err != nil {
 		return actionError{err: fmt.Errorf("failed to get state of installimage processes: %w", err)}
@@ -1106,6 +1182,67 @@ func (s *Service) actionImageInstalling(ctx context.Context) actionResult {
 }
 
 func (s *Service) actionImageInstallingStartBackgroundProcess(ctx context.Context, sshClient sshclient.Client) actionResult {
+	// The private IP in the vSwitch is needed for the network configuration of the installed system.
+	vSwitchScript, actionRes := s.vSwitchNetworkConfig()
+	if actionRes != nil {
+		return actionRes
+	}
+
+	if actionRes := s.imageInstallingPreflightChecks(ctx, sshClient); actionRes != nil {
+		return actionRes
+	}
+
+	autoSetupInput, actionRes := s.createAutoSetupInput(sshClient)
+	if actionRes != nil {
+		return actionRes
+	}
+
+	autoSetup := buildAutoSetup(s.scope.HetznerBareMetalHost.Spec.Status.InstallImage, autoSetupInput)
+
+	out := sshClient.CreateAutoSetup(autoSetup)
+	if out.Err != nil {
+		return actionError{err: fmt.Errorf("failed to create autosetup: %q %q %w", out.StdOut, out.StdErr, out.Err)}
+	}
+
+	if out.StdErr != "" {
+		return actionError{err: fmt.Errorf("failed to create autosetup: %q %q %w. Content: %s", out.StdOut, out.StdErr, out.Err, autoSetup)}
+	}
+
+	postInstallScript, err := s.buildPostInstallScript(ctx, vSwitchScript)
+	if err != nil {
+		return actionError{err: err}
+	}
+
+	if err := handleSSHError(sshClient.CreatePostInstallScript(postInstallScript)); err != nil {
+		return actionError{err: fmt.Errorf("failed to create post install script %s: %w", postInstallScript, err)}
+	}
+
+	record.Event(s.scope.HetznerBareMetalHost, "InstallingMachineImageStarted",
+		s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.Image.String())
+
+	out = sshClient.UntarTGZ()
+	if out.Err != nil {
+		record.Warnf(s.scope.HetznerBareMetalHost, "UntarInstallimageTgzFailed", "err: %s, stderr: %s", out.Err.Error(), out.StdErr)
+		return actionError{err: fmt.Errorf("UntarInstallimageTgzFailed: %w", out.Err)}
+	}
+	record.Event(s.scope.HetznerBareMetalHost, "ExecuteInstallImageStarted",
+		s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.Image.String())
+
+	// Execute install image
+	out = sshClient.ExecuteInstallImage(postInstallScript != "")
+	if out.Err != nil {
+		record.Warnf(s.scope.HetznerBareMetalHost, "ExecuteInstallImageFailed", out.String())
+		return actionError{err: fmt.Errorf("failed to execute installimage: %w", out.Err)}
+	}
+
+	s.osHostKeyVerifier().reset()
+	s.startProvisioningAttempt()
+	return actionContinue{delay: 10 * time.Second}
+}
+
+// imageInstallingPreflightChecks checks the disks before the image gets installed. It returns nil if the
+// installation can start.
+func (s *Service) imageInstallingPreflightChecks(ctx context.Context, sshClient sshclient.Client) actionResult {
 	// CheckDisk before accessing the disk
 	info, err := sshClient.CheckDisk(ctx, s.scope.HetznerBareMetalHost.Spec.RootDeviceHints.ListOfWWN())
 	if err != nil {
@@ -1223,24 +1360,12 @@ func (s *Service) actionImageInstallingStartBackgroundProcess(ctx context.Contex
 	record.Eventf(s.scope.HetznerBareMetalHost, "NoLinuxOnAnotherDisk", "OK, no Linux on another disk:\n%s\n\n%s", out.StdOut, out.StdErr)
 
 	record.Event(s.scope.HetznerBareMetalHost, "InstallImagePreflightCheckSuccessful", "Rescue system reachable, disks look good.")
+	return nil
+}
 
-	autoSetupInput, actionRes := s.createAutoSetupInput(sshClient)
-	if actionRes != nil {
-		return actionRes
-	}
-
-	autoSetup := buildAutoSetup(s.scope.HetznerBareMetalHost.Spec.Status.InstallImage, autoSetupInput)
-
-	out = sshClient.CreateAutoSetup(autoSetup)
-	if out.Err != nil {
-		return actionError{err: fmt.Errorf("failed to create autosetup: %q %q %w", out.StdOut, out.StdErr, out.Err)}
-	}
-
-	if out.StdErr != "" {
-		return actionError{err: fmt.Errorf("failed to create autosetup: %q %q %w. Content: %s", out.StdOut, out.StdErr, out.Err, autoSetup)}
-	}
-
-	// create post install script
+// buildPostInstallScript returns the post install script of the InstallImage, extended by the installation of
+// the cloud-init data and the network configuration of the vSwitch.
+func (s *Service) buildPostInstallScript(ctx context.Context, vSwitchScript string) (string, error) {
 	postInstallScript := s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.PostInstallScript
 
 	if !strings.HasPrefix(postInstallScript, "#!/bin/bash") {
@@ -1249,7 +1374,18 @@ func (s *Service) actionImageInstallingStartBackgroundProcess(ctx context.Contex
 
 	cloudInitData, err := s.scope.GetRawBootstrapData(ctx)
 	if err != nil {
-		return actionError{err: fmt.Errorf("failed to get user data: %w", err)}
+		return "", fmt.Errorf("failed to get user data: %w", err)
+	}
+
+	// Large user data is compressed to keep the post install script small. cloud-init decompresses it transparently.
+	writeUserDataCommand := "cat"
+	if len(cloudInitData) > userDataCompressionThreshold && userdata.IsCompressible(cloudInitData) {
+		compressed, err := userdata.Compress(cloudInitData)
+		if err != nil {
+			return "", fmt.Errorf("failed to compress user data: %w", err)
+		}
+		cloudInitData = compressed
+		writeUserDataCommand = "base64 -d"
 	}
 
 	postInstallScript = fmt.Sprintf(`%s
@@ -1265,46 +1401,29 @@ cat << 'EOF_POST_INSTALL_SCRIPT' > /var/lib/cloud/seed/nocloud-net/meta-data
 local-hostname: %s
 EOF_POST_INSTALL_SCRIPT
 
-cat << 'EOF_POST_INSTALL_SCRIPT' > /var/lib/cloud/seed/nocloud-net/user-data
+%s << 'EOF_POST_INSTALL_SCRIPT' > /var/lib/cloud/seed/nocloud-net/user-data
 %s
 EOF_POST_INSTALL_SCRIPT
-
+%s
 echo %q
 # end of install cloud-init data
-`, postInstallScript, s.scope.Hostname(), cloudInitData, PostInstallScriptFinished)
-
-	if err := handleSSHError(sshClient.CreatePostInstallScript(postInstallScript)); err != nil {
-		return actionError{err: fmt.Errorf("failed to create post install script %s: %w", postInstallScript, err)}
-	}
-
-	record.Event(s.scope.HetznerBareMetalHost, "InstallingMachineImageStarted",
-		s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.Image.String())
-
-	out = sshClient.UntarTGZ()
-	if out.Err != nil {
-		record.Warnf(s.scope.HetznerBareMetalHost, "UntarInstallimageTgzFailed", "err: %s, stderr: %s", out.Err.Error(), out.StdErr)
-		return actionError{err: fmt.Errorf("UntarInstallimageTgzFailed: %w", out.Err)}
-	}
-	record.Event(s.scope.HetznerBareMetalHost, "ExecuteInstallImageStarted",
-		s.scope.HetznerBareMetalHost.Spec.Status.InstallImage.Image.String())
+`, postInstallScript, s.scope.Hostname(), writeUserDataCommand, cloudInitData, vSwitchScript, PostInstallScriptFinished)
 
-	// Execute install image
-	out = sshClient.ExecuteInstallImage(postInstallScript != "")
-	if out.Err != nil {
-		record.Warnf(s.scope.HetznerBareMetalHost, "ExecuteInstallImageFailed", out.String())
-		return actionError{err: fmt.Errorf("failed to execute installimage: %w", out.Err)}
-	}
-	return actionContinue{delay: 10 * time.Second}
+	return postInstallScript, nil
 }
 
-func (s *Service) actionImageInstallingFinished(_ context.Context, sshClient sshclient.Client) actionResult {
+func (s *Service) actionImageInstallingFinished(ctx context.Context, sshClient sshclient.Client) actionResult {
 	output, err := sshClient.GetResultOfInstallImage()
 	if err != nil {
 		return actionError{
 			err: fmt.Errorf("GetResultOfInstallImage failed: %w", err),
 		}
 	}
-	if !strings.Contains(output, PostInstallScriptFinished) {
+	s.storeProvisioningLog(ctx, provisioningLogInstallImage, output)
+	finished := strings.Contains(output, PostInstallScriptFinished)
+	// the output is shown in events and errors, so it must not contain credentials of the user data.
+	output = redactCredentials(output)
+	if !finished {
 		record.Warn(s.scope.HetznerBareMetalHost, "InstallImageNotSuccessful", output)
 		return actionError{err: fmt.Errorf("did not find marker %q in stdout. Installimage was not successful: %s",
 			PostInstallScriptFinished, output)}
@@ -1420,6 +1539,8 @@ func analyzeSSHOutputInstallImage(out sshclient.Output, sshClient sshclient.Clie
 			return false, false, handleAuthenticationFailed(sshClient, port)
 		case sshclient.IsConnectionRefusedError(out.Err):
 			return false, verifyConnectionRefused(sshClient, port), nil
+		case sshclient.IsHostKeyMismatchError(out.Err):
+			return false, false, out.Err
 		}
 
 		return false, false, fmt.Errorf("unhandled ssh error while getting hostname: %w", out.Err)
@@ -1469,12 +1590,15 @@ func verifyConnectionRefused(sshClient sshclient.Client, port int) bool {
 	return true
 }
 
-func (s *Service) actionEnsureProvisioned(_ context.Context) (ar actionResult) {
+func (s *Service) actionEnsureProvisioned(ctx context.Context) (ar actionResult) {
 	markProvisionPending(s.scope.HetznerBareMetalHost, infrav1.StateEnsureProvisioned)
+	hostKeyVerifier := s.osHostKeyVerifier()
 	sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
-		PrivateKey: sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
-		Port:       s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
-		IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		PrivateKey:    sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
+		Port:          s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
+		IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		VerifyHostKey: hostKeyVerifier.verify,
+		JumpHost:      s.sshJumpHost(),
 	})
 
 	// Check hostname with sshClient
@@ -1490,6 +1614,9 @@ func (s *Service) actionEnsureProvisioned(_ context.Context) (ar actionResult) {
 		}
 
 		isTimeout, isSSHConnectionRefusedError, err := analyzeSSHOutputProvisioned(out)
+		if sshclient.IsHostKeyMismatchError(err) {
+			return s.failHostKeyMismatch(err)
+		}
 		if err != nil {
 			if errors.Is(err, errUnexpectedHostName) {
 				// One possible reason: The machine gets used by a second wl-cluster
@@ -1518,6 +1645,7 @@ func (s *Service) actionEnsureProvisioned(_ context.Context) (ar actionResult) {
 
 	// from now on we know that the machine is reachable and
 	// is no longer in the rescue system.
+	hostKeyVerifier.trust()
 
 	createEventWithCloudInitOutput := func(ar actionResult) actionResult {
 		// Create an Event which contains the cloud-init-output.
@@ -1538,15 +1666,16 @@ func (s *Service) actionEnsureProvisioned(_ context.Context) (ar actionResult) {
 		if out.Err != nil || out.StdErr != "" {
 			record.Warnf(s.scope.HetznerBareMetalHost, "GetCloudInitOutputFailed",
 				"GetCloudInitOutput failed to get /var/log/cloud-init-output.log: stdout %q, stderr %q, err %q",
-				out.StdOut, out.StdErr, out.Err.Error())
+				redactCredentials(out.StdOut), redactCredentials(out.StdErr), out.Err.Error())
 			if err != nil {
 				return actionError{err: fmt.Errorf("failed to get cloud init output: %w, while handling: %w", out.Err, err)}
 			}
 			return actionError{err: fmt.Errorf("failed to get cloud init output: %w", err)}
 		}
+		s.storeProvisioningLog(ctx, provisioningLogCloudInit, out.StdOut)
 		f(s.scope.HetznerBareMetalHost, "CloudInitOutput", "cloud init output%s:\n%s",
 			errMsg,
-			out.StdOut)
+			redactCredentials(out.StdOut))
 		return ar
 	}
 
@@ -1582,9 +1711,11 @@ func (s *Service) handleConnectionRefused() actionResult {
 		return nil
 	}
 	oldSSHClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
-		PrivateKey: sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
-		Port:       s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterInstallImage,
-		IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		PrivateKey:    sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
+		Port:          s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterInstallImage,
+		IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		VerifyHostKey: s.osHostKeyVerifier().verify,
+		JumpHost:      s.sshJumpHost(),
 	})
 	actResult, _, err := s.checkCloudInitStatus(oldSSHClient)
 	// If this ssh client also gives an error, then we go back to analyzing the error of the first ssh call
@@ -1648,9 +1779,11 @@ func (s *Service) checkCloudInitStatus(sshClient sshclient.Client) (actionResult
 func (s *Service) handleCloudInitNotStarted() actionResult {
 	// Check whether cloud init really was successfully. Sigterm causes problems there.
 	oldSSHClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
-		PrivateKey: sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
-		Port:       s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterInstallImage,
-		IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		PrivateKey:    sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
+		Port:          s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterInstallImage,
+		IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		VerifyHostKey: s.osHostKeyVerifier().verify,
+		JumpHost:      s.sshJumpHost(),
 	})
 	out := oldSSHClient.CheckCloudInitLogsForSigTerm()
 	if err := handleSSHError(out); err != nil {
@@ -1692,6 +1825,8 @@ func analyzeSSHOutputProvisioned(out sshclient.Output) (isTimeout, isConnectionR
 		case sshclient.IsConnectionRefusedError(out.Err):
 			// We strongly assume that the ssh reboot that has been done before has been triggered. Hence we do nothing specific here.
 			isConnectionRefused = true
+		case sshclient.IsHostKeyMismatchError(out.Err):
+			reterr = out.Err
 		default:
 			reterr = fmt.Errorf("unhandled ssh error while getting hostname: %w", out.Err)
 		}
@@ -1718,17 +1853,20 @@ func analyzeSSHOutputProvisioned(out sshclient.Output) (isTimeout, isConnectionR
 
 // previous: EnsureProvisioned
 // next: Stays in Provisioned (final state)
-func (s *Service) actionProvisioned(_ context.Context) actionResult {
+func (s *Service) actionProvisioned(ctx context.Context) actionResult {
 	// set host to provisioned
 	conditions.MarkTrue(s.scope.HetznerBareMetalHost, infrav1.ProvisionSucceededCondition)
 
 	rebootDesired := s.scope.HetznerBareMetalHost.HasRebootAnnotation()
 	isRebooted := s.scope.HetznerBareMetalHost.Spec.Status.Rebooted
 	creds := sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef)
+	hostKeyVerifier := s.osHostKeyVerifier()
 	in := sshclient.Input{
-		PrivateKey: creds.PrivateKey,
-		Port:       s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
-		IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		PrivateKey:    creds.PrivateKey,
+		Port:          s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
+		IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+		VerifyHostKey: hostKeyVerifier.verify,
+		JumpHost:      s.sshJumpHost(),
 	}
 	sshClient := s.scope.SSHClientFactory.NewClient(in)
 
@@ -1742,6 +1880,7 @@ func (s *Service) actionProvisioned(_ context.Context) actionResult {
 
 			if trimLineBreak(out.StdOut) == wantHostName {
 				// Reboot has been successful
+				hostKeyVerifier.trust()
 				s.scope.HetznerBareMetalHost.Spec.Status.Rebooted = false
 				s.scope.HetznerBareMetalHost.ClearRebootAnnotations()
 
@@ -1750,6 +1889,9 @@ func (s *Service) actionProvisioned(_ context.Context) actionResult {
 			}
 			// Reboot has been ongoing
 			isTimeout, isSSHConnectionRefusedError, err := analyzeSSHOutputProvisioned(out)
+			if sshclient.IsHostKeyMismatchError(err) {
+				return s.failHostKeyMismatch(err)
+			}
 			if err != nil {
 				if errors.Is(err, errUnexpectedHostName) {
 					// One possible reason: The machine gets used by a second wl-cluster
@@ -1778,9 +1920,32 @@ func (s *Service) actionProvisioned(_ context.Context) actionResult {
 		return actionContinue{delay: 10 * time.Second}
 	}
 
+	if diskHealthCheckDue(s.scope.HetznerBareMetalHost) {
+		if err := s.checkDiskHealth(ctx, sshClient); err != nil {
+			return actionError{err: fmt.Errorf("failed to check disk health: %w", err)}
+		}
+	}
+
 	return actionComplete{} // Stays in Provisioned (final state)
 }
 
+// startReprovisioning removes the reprovision request and pending reboots of a provisioned host, so that the
+// image can be installed again.
+func (s *Service) startReprovisioning() {
+	host := s.scope.HetznerBareMetalHost
+	delete(host.Annotations, infrav1.ReprovisionAnnotation)
+	host.ClearRebootAnnotations()
+	host.Spec.Status.Rebooted = false
+	host.ClearError()
+	s.resetExternallyProvisioned()
+
+	image := ""
+	if host.Spec.Status.InstallImage != nil {
+		image = host.Spec.Status.InstallImage.Image.String()
+	}
+	record.Eventf(host, "ReprovisioningStarted", "Reprovisioning host with image %s", image)
+}
+
 // next: None
 func (s *Service) actionDeprovisioning(_ context.Context) actionResult {
 	// Update name in robot API
@@ -1792,12 +1957,18 @@ func (s *Service) actionDeprovisioning(_ context.Context) actionResult {
 		return actionError{err: fmt.Errorf("failed to update name of host in robot API: %w", err)}
 	}
 
+	if err := s.cancelRobotLinuxInstall(); err != nil {
+		return actionError{err: err}
+	}
+
 	// If has been provisioned completely, stop all running pods
 	if s.scope.OSSSHSecret != nil {
 		sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
-			PrivateKey: sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
-			Port:       s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
-			IP:         s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+			PrivateKey:    sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.SecretRef).PrivateKey,
+			Port:          s.scope.HetznerBareMetalHost.Spec.Status.SSHSpec.PortAfterCloudInit,
+			IP:            s.scope.HetznerBareMetalHost.Spec.Status.GetIPAddress(),
+			VerifyHostKey: s.osHostKeyVerifier().verify,
+			JumpHost:      s.sshJumpHost(),
 		})
 		out := sshClient.ResetKubeadm()
 		s.scope.V(1).Info("Output of ResetKubeadm", "stdout", out.StdOut, "stderr", out.StdErr, "err", out.Err)
@@ -1810,6 +1981,9 @@ func (s *Service) actionDeprovisioning(_ context.Context) actionResult {
 		s.scope.Info("OS SSH Secret is empty - cannot reset kubeadm")
 	}
 
+	// The operating system of an adopted server is not kept, so the next consumer installs an image.
+	s.resetExternallyProvisioned()
+
 	// Only keep permanent errors on the host object after deprovisioning.
 	// Permanent errors are those ones that do not get solved with de- or re-provisioning.
 	if s.scope.HetznerBareMetalHost.Spec.Status.ErrorType != infrav1.PermanentError {
@@ -79,6 +79,7 @@ func newTestService(
 ) *Service {
 	scheme := runtime.NewScheme()
 	utilruntime.Must(infrav1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
 	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(host).Build()
 	return &Service{
 		&scope.BareMetalHostScope{
@@ -782,6 +782,13 @@ var _ = Describe("analyzeSSHOutputInstallImage", func() {
 			expectedIsConnectionRefused: true,
 			expectedErrMessage:          "",
 		}),
+		Entry("host key mismatch", testCaseAnalyzeSSHOutputInstallImageOutErr{
+			err:                         &sshclient.HostKeyMismatchError{Pinned: "SHA256:pinned", Actual: "SHA256:other"},
+			rescueActive:                true,
+			expectedIsTimeout:           false,
+			expectedIsConnectionRefused: false,
+			expectedErrMessage:          "host key mismatch",
+		}),
 	)
 
 	type testCaseAnalyzeSSHOutputInstallImageStdErr struct {
@@ -936,6 +943,14 @@ var _ = Describe("analyzeSSHOutputInstallImage", func() {
 			expectedIsConnectionRefused: false,
 			expectedErrMessage:          "",
 		}),
+		Entry("host key mismatch", testCaseAnalyzeSSHOutputInstallImageOutErr{
+			err:                         &sshclient.HostKeyMismatchError{Pinned: "SHA256:pinned", Actual: "SHA256:other"},
+			errFromGetHostNameNil:       true,
+			port:                        22,
+			expectedIsTimeout:           false,
+			expectedIsConnectionRefused: false,
+			expectedErrMessage:          "host key mismatch",
+		}),
 	)
 
 	type testCaseAnalyzeSSHOutputInstallImageStdErr struct {
@@ -1109,6 +1124,12 @@ var _ = Describe("analyzeSSHOutputProvisioned", func() {
 			expectedIsConnectionRefused: true,
 			expectedErrMessage:          nil,
 		}),
+		Entry("host key mismatch", testCaseAnalyzeSSHOutputProvisioned{
+			out:                         sshclient.Output{Err: &sshclient.HostKeyMismatchError{Pinned: "SHA256:pinned", Actual: "SHA256:other"}},
+			expectedIsTimeout:           false,
+			expectedIsConnectionRefused: false,
+			expectedErrMessage:          ptr.To("host key mismatch"),
+		}),
 	)
 })
 
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+// hostKeyVerifier implements trust on first use for the SSH host key of either the rescue system or the
+// installed operating system. As long as no host key is pinned, every host key is accepted. The host key
+// gets pinned with trust after the caller has made sure that it talks to the expected system.
+type hostKeyVerifier struct {
+	host     *infrav1.HetznerBareMetalHost
+	system   string
+	pinned   *string
+	observed string
+}
+
+func (s *Service) rescueHostKeyVerifier() *hostKeyVerifier {
+	host := s.scope.HetznerBareMetalHost
+	return &hostKeyVerifier{host: host, system: "rescue system", pinned: &host.Spec.Status.SSHStatus.RescueHostKeyFingerprint}
+}
+
+func (s *Service) osHostKeyVerifier() *hostKeyVerifier {
+	host := s.scope.HetznerBareMetalHost
+	return &hostKeyVerifier{host: host, system: "operating system", pinned: &host.Spec.Status.SSHStatus.OSHostKeyFingerprint}
+}
+
+// verify is used as sshclient.Input.VerifyHostKey.
+func (v *hostKeyVerifier) verify(fingerprint string) error {
+	v.observed = fingerprint
+	if *v.pinned == "" {
+		return nil
+	}
+	if fingerprint != *v.pinned {
+		err := &sshclient.HostKeyMismatchError{Pinned: *v.pinned, Actual: fingerprint}
+		conditions.MarkFalse(
+			v.host,
+			infrav1.SSHHostKeyVerifiedCondition,
+			infrav1.SSHHostKeyMismatchReason,
+			clusterv1.ConditionSeverityError,
+			"host key of %s does not match: %s",
+			v.system,
+			err.Error(),
+		)
+		record.Warnf(v.host, infrav1.SSHHostKeyMismatchReason, "Refused to connect to %s: %s", v.system, err.Error())
+		return err
+	}
+	conditions.MarkTrue(v.host, infrav1.SSHHostKeyVerifiedCondition)
+	return nil
+}
+
+// trust pins the observed host key if no host key has been pinned yet.
+func (v *hostKeyVerifier) trust() {
+	if *v.pinned != "" || v.observed == "" {
+		return
+	}
+	*v.pinned = v.observed
+	conditions.MarkTrue(v.host, infrav1.SSHHostKeyVerifiedCondition)
+	record.Eventf(v.host, "SSHHostKeyPinned", "Pinned host key %s of %s", v.observed, v.system)
+}
+
+// reset removes the pinned host key. It is called when the system gets installed again, as the new system has
+// new host keys, which get pinned on the next connection.
+func (v *hostKeyVerifier) reset() {
+	*v.pinned = ""
+}
+
+// failHostKeyMismatch stops the state machine if a server presented a host key that does not match the pinned
+// one. The server might be impersonated, so the controller does not connect again until the fingerprint got
+// removed from the status and the permanent error annotation got removed from the host.
+func (s *Service) failHostKeyMismatch(err error) actionResult {
+	return s.recordActionFailure(infrav1.PermanentError, "refused to connect: "+err.Error())
+}
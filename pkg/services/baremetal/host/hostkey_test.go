@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("hostKeyVerifier", func() {
+	var (
+		host    *infrav1.HetznerBareMetalHost
+		service *Service
+	)
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost("test-host", "default")
+		service = newTestService(host, nil, nil, nil, nil)
+	})
+
+	It("pins the host key only after it has been trusted", func() {
+		verifier := service.rescueHostKeyVerifier()
+		Expect(verifier.verify("SHA256:rescue")).To(Succeed())
+		Expect(host.Spec.Status.SSHStatus.RescueHostKeyFingerprint).To(BeEmpty())
+
+		verifier.trust()
+		Expect(host.Spec.Status.SSHStatus.RescueHostKeyFingerprint).To(Equal("SHA256:rescue"))
+		Expect(host.Spec.Status.SSHStatus.OSHostKeyFingerprint).To(BeEmpty())
+		Expect(conditions.IsTrue(host, infrav1.SSHHostKeyVerifiedCondition)).To(BeTrue())
+	})
+
+	It("accepts the pinned host key", func() {
+		host.Spec.Status.SSHStatus.OSHostKeyFingerprint = "SHA256:os"
+
+		Expect(service.osHostKeyVerifier().verify("SHA256:os")).To(Succeed())
+		Expect(conditions.IsTrue(host, infrav1.SSHHostKeyVerifiedCondition)).To(BeTrue())
+	})
+
+	It("refuses a host key that does not match the pinned one", func() {
+		host.Spec.Status.SSHStatus.OSHostKeyFingerprint = "SHA256:os"
+		verifier := service.osHostKeyVerifier()
+
+		err := verifier.verify("SHA256:other")
+		Expect(sshclient.IsHostKeyMismatchError(err)).To(BeTrue())
+		Expect(conditions.IsFalse(host, infrav1.SSHHostKeyVerifiedCondition)).To(BeTrue())
+		Expect(conditions.GetReason(host, infrav1.SSHHostKeyVerifiedCondition)).To(Equal(infrav1.SSHHostKeyMismatchReason))
+
+		// trusting does not overwrite the pinned host key
+		verifier.trust()
+		Expect(host.Spec.Status.SSHStatus.OSHostKeyFingerprint).To(Equal("SHA256:os"))
+	})
+
+	It("removes the pinned host key on reset", func() {
+		host.Spec.Status.SSHStatus.RescueHostKeyFingerprint = "SHA256:rescue"
+		host.Spec.Status.SSHStatus.OSHostKeyFingerprint = "SHA256:os"
+
+		service.osHostKeyVerifier().reset()
+		Expect(host.Spec.Status.SSHStatus.OSHostKeyFingerprint).To(BeEmpty())
+		Expect(host.Spec.Status.SSHStatus.RescueHostKeyFingerprint).To(Equal("SHA256:rescue"))
+	})
+
+	It("stops with a permanent error on a host key mismatch", func() {
+		err := &sshclient.HostKeyMismatchError{Pinned: "SHA256:os", Actual: "SHA256:other"}
+
+		Expect(service.failHostKeyMismatch(err)).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.Status.ErrorType).To(Equal(infrav1.PermanentError))
+		Expect(host.Spec.Status.ErrorMessage).To(ContainSubstring("host key mismatch"))
+		Expect(host.Annotations).To(HaveKey(infrav1.PermanentErrorAnnotation))
+	})
+})
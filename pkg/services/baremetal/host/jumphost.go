@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"net"
+
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+const defaultSSHPort = "22"
+
+// sshJumpHost returns the jump host through which the host is connected, or nil if the host is connected directly.
+// The secret of the jump host is fetched by the controller and stored in the scope.
+func (s *Service) sshJumpHost() *sshclient.JumpHost {
+	spec := s.scope.HetznerBareMetalHost.GetSSHJumpHost(s.scope.HetznerCluster)
+	if spec == nil {
+		return nil
+	}
+
+	address := spec.Address
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, defaultSSHPort)
+	}
+
+	user := spec.User
+	if user == "" {
+		user = "root"
+	}
+
+	jumpHost := &sshclient.JumpHost{
+		Address: address,
+		User:    user,
+	}
+	if s.scope.SSHJumpHostSecret != nil {
+		jumpHost.PrivateKey = sshclient.CredentialsFromSecret(s.scope.SSHJumpHostSecret, spec.SecretRef).PrivateKey
+	}
+	jumpHost.VerifyHostKey = s.jumpHostKeyVerifier(spec.HostKeyFingerprint).verify
+	return jumpHost
+}
+
+// jumpHostKeyVerifier verifies the host key of the jump host against the fingerprint of the spec. The fingerprint
+// is required, so that every host key of the jump host gets verified. It is never overwritten by trust.
+func (s *Service) jumpHostKeyVerifier(fingerprint string) *hostKeyVerifier {
+	return &hostKeyVerifier{host: s.scope.HetznerBareMetalHost, system: "jump host", pinned: &fingerprint}
+}
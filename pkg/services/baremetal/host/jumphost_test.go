@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("sshJumpHost", func() {
+	var (
+		host    *infrav1.HetznerBareMetalHost
+		service *Service
+	)
+
+	secretRef := infrav1.SSHSecretRef{
+		Name: "jump-host-ssh",
+		Key:  infrav1.SSHSecretKeyRef{Name: "sshkey-name", PublicKey: "ssh-publickey", PrivateKey: "ssh-privatekey"},
+	}
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost("test-host", "default")
+		service = newTestService(host, nil, nil, nil, nil)
+		service.scope.SSHJumpHostSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "jump-host-ssh", Namespace: "default"},
+			Data:       map[string][]byte{"ssh-privatekey": []byte("jump-host-private-key")},
+		}
+	})
+
+	It("connects directly if no jump host is configured", func() {
+		Expect(service.sshJumpHost()).To(BeNil())
+	})
+
+	It("uses the jump host of the cluster with default port and user", func() {
+		service.scope.HetznerCluster.Spec.SSHJumpHost = &infrav1.SSHJumpHost{Address: "bastion.example.com", SecretRef: secretRef, HostKeyFingerprint: "SHA256:bastion"}
+
+		jumpHost := service.sshJumpHost()
+		Expect(jumpHost).ToNot(BeNil())
+		Expect(jumpHost.Address).To(Equal("bastion.example.com:22"))
+		Expect(jumpHost.User).To(Equal("root"))
+		Expect(jumpHost.PrivateKey).To(Equal("jump-host-private-key"))
+		Expect(jumpHost.VerifyHostKey).ToNot(BeNil())
+	})
+
+	It("prefers the jump host of the host over the one of the cluster", func() {
+		service.scope.HetznerCluster.Spec.SSHJumpHost = &infrav1.SSHJumpHost{Address: "bastion.example.com", SecretRef: secretRef, HostKeyFingerprint: "SHA256:bastion"}
+		host.Spec.SSHJumpHost = &infrav1.SSHJumpHost{Address: "[2001:db8::1]:2222", User: "jump", SecretRef: secretRef, HostKeyFingerprint: "SHA256:other-bastion"}
+
+		jumpHost := service.sshJumpHost()
+		Expect(jumpHost.Address).To(Equal("[2001:db8::1]:2222"))
+		Expect(jumpHost.User).To(Equal("jump"))
+	})
+
+	It("verifies the host key of the jump host", func() {
+		host.Spec.SSHJumpHost = &infrav1.SSHJumpHost{Address: "2001:db8::1", SecretRef: secretRef, HostKeyFingerprint: "SHA256:bastion"}
+
+		jumpHost := service.sshJumpHost()
+		Expect(jumpHost.Address).To(Equal("[2001:db8::1]:22"))
+		Expect(jumpHost.VerifyHostKey("SHA256:bastion")).To(Succeed())
+		Expect(conditions.IsTrue(host, infrav1.SSHHostKeyVerifiedCondition)).To(BeTrue())
+
+		err := jumpHost.VerifyHostKey("SHA256:other")
+		Expect(sshclient.IsHostKeyMismatchError(err)).To(BeTrue())
+		Expect(conditions.IsFalse(host, infrav1.SSHHostKeyVerifiedCondition)).To(BeTrue())
+		Expect(conditions.GetReason(host, infrav1.SSHHostKeyVerifiedCondition)).To(Equal(infrav1.SSHHostKeyMismatchReason))
+		Expect(conditions.GetMessage(host, infrav1.SSHHostKeyVerifiedCondition)).To(ContainSubstring("jump host"))
+
+		// the fingerprint of the spec is not overwritten
+		Expect(host.Spec.SSHJumpHost.HostKeyFingerprint).To(Equal("SHA256:bastion"))
+	})
+})
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/syself/hrobot-go/models"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// SetHardwareLabels sets the well-known hardware labels of the host from its hardware details, so that hosts
+// can be selected by their hardware with the HostSelector of HetznerBareMetalMachines. It returns true if the
+// labels have changed.
+func SetHardwareLabels(host *infrav1.HetznerBareMetalHost) bool {
+	details := host.Spec.Status.HardwareDetails
+	if details == nil {
+		return false
+	}
+
+	var nvmeCount, ssdCount, hddCount, nicSpeed int
+	for _, disk := range details.Storage {
+		if disk.IsNVMe() {
+			nvmeCount++
+		}
+		if disk.Rota {
+			hddCount++
+		} else {
+			ssdCount++
+		}
+	}
+	for _, nic := range details.NIC {
+		nicSpeed = max(nicSpeed, nic.SpeedMbps)
+	}
+
+	return setLabels(host, map[string]string{
+		infrav1.HardwareRAMGBLabel:      strconv.Itoa(details.RAMGB),
+		infrav1.HardwareCPUArchLabel:    details.CPU.Arch,
+		infrav1.HardwareCPUCoresLabel:   strconv.Itoa(details.CPU.Cores),
+		infrav1.HardwareCPUThreadsLabel: strconv.Itoa(details.CPU.Threads),
+		infrav1.HardwareDiskCountLabel:  strconv.Itoa(len(details.Storage)),
+		infrav1.HardwareNVMeCountLabel:  strconv.Itoa(nvmeCount),
+		infrav1.HardwareSSDCountLabel:   strconv.Itoa(ssdCount),
+		infrav1.HardwareHDDCountLabel:   strconv.Itoa(hddCount),
+		infrav1.HardwareNICSpeedLabel:   strconv.Itoa(nicSpeed),
+	})
+}
+
+// SetRobotServerLabels sets the labels for the datacenter and the product of the server in Hetzner Robot.
+// It returns true if the labels have changed.
+func SetRobotServerLabels(host *infrav1.HetznerBareMetalHost, server *models.Server) bool {
+	return setLabels(host, map[string]string{
+		infrav1.HardwareDatacenterLabel: server.Dc,
+		infrav1.HardwareProductLabel:    server.Product,
+	})
+}
+
+// setLabels sets the labels on the host. Values are converted to valid label values. Labels whose values are
+// empty afterwards are removed, so that host selectors do not match outdated values.
+func setLabels(host *infrav1.HetznerBareMetalHost, labels map[string]string) (changed bool) {
+	for key, value := range labels {
+		value = labelValue(value)
+		if value == "" {
+			if _, found := host.Labels[key]; found {
+				delete(host.Labels, key)
+				changed = true
+			}
+			continue
+		}
+		if host.Labels[key] == value {
+			continue
+		}
+		if host.Labels == nil {
+			host.Labels = make(map[string]string, len(labels))
+		}
+		host.Labels[key] = value
+		changed = true
+	}
+	return changed
+}
+
+// labelValue replaces the characters that are not allowed in label values, e.g. "Server Auction" becomes
+// "Server-Auction". An empty string is returned if the value cannot be converted.
+func labelValue(value string) string {
+	value = invalidLabelValueChars.ReplaceAllString(value, "-")
+	if len(value) > validation.LabelValueMaxLength {
+		value = value[:validation.LabelValueMaxLength]
+	}
+	value = strings.Trim(value, "-_.")
+	if len(validation.IsValidLabelValue(value)) > 0 {
+		return ""
+	}
+	return value
+}
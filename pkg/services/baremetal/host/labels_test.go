@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syself/hrobot-go/models"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("SetHardwareLabels", func() {
+	It("does not set labels without hardware details", func() {
+		host := helpers.BareMetalHost("test-host", "default")
+		Expect(SetHardwareLabels(host)).To(BeFalse())
+		Expect(host.Labels).To(BeEmpty())
+	})
+
+	It("sets the labels from the hardware details", func() {
+		host := helpers.BareMetalHost("test-host", "default")
+		host.Labels = map[string]string{"pool": "workers"}
+		host.Spec.Status.HardwareDetails = &infrav1.HardwareDetails{
+			RAMGB: 64,
+			CPU:   infrav1.CPU{Arch: "x86_64", Cores: 8, Threads: 16},
+			NIC: []infrav1.NIC{
+				{Name: "eth0", SpeedMbps: 1000},
+				{Name: "eth1", SpeedMbps: 10000},
+			},
+			Storage: []infrav1.Storage{
+				{WWN: "eui.0025388801b4dff2"},
+				{WWN: "0x5002538e09a1b2c3", HCTL: "0:0:0:0"},
+				{WWN: "0x5000039b38d0b6a1", HCTL: "1:0:0:0", Rota: true},
+			},
+		}
+
+		Expect(SetHardwareLabels(host)).To(BeTrue())
+		Expect(host.Labels).To(Equal(map[string]string{
+			"pool":                          "workers",
+			infrav1.HardwareRAMGBLabel:      "64",
+			infrav1.HardwareCPUArchLabel:    "x86_64",
+			infrav1.HardwareCPUCoresLabel:   "8",
+			infrav1.HardwareCPUThreadsLabel: "16",
+			infrav1.HardwareDiskCountLabel:  "3",
+			infrav1.HardwareNVMeCountLabel:  "1",
+			infrav1.HardwareSSDCountLabel:   "2",
+			infrav1.HardwareHDDCountLabel:   "1",
+			infrav1.HardwareNICSpeedLabel:   "10000",
+		}))
+
+		Expect(SetHardwareLabels(host)).To(BeFalse())
+	})
+
+	It("removes labels whose value became empty", func() {
+		host := helpers.BareMetalHost("test-host", "default")
+		host.Labels = map[string]string{infrav1.HardwareCPUArchLabel: "x86_64"}
+		host.Spec.Status.HardwareDetails = &infrav1.HardwareDetails{RAMGB: 64}
+
+		Expect(SetHardwareLabels(host)).To(BeTrue())
+		Expect(host.Labels).ToNot(HaveKey(infrav1.HardwareCPUArchLabel))
+		Expect(host.Labels).To(HaveKeyWithValue(infrav1.HardwareRAMGBLabel, "64"))
+	})
+})
+
+var _ = Describe("SetRobotServerLabels", func() {
+	It("converts the values into valid label values", func() {
+		host := helpers.BareMetalHost("test-host", "default")
+		Expect(SetRobotServerLabels(host, &models.Server{Dc: "FSN1-DC14", Product: "Server Auction (EX42)"})).To(BeTrue())
+		Expect(host.Labels).To(Equal(map[string]string{
+			infrav1.HardwareDatacenterLabel: "FSN1-DC14",
+			infrav1.HardwareProductLabel:    "Server-Auction-EX42",
+		}))
+
+		Expect(SetRobotServerLabels(host, &models.Server{Product: "EX44"})).To(BeTrue())
+		Expect(host.Labels).To(Equal(map[string]string{
+			infrav1.HardwareProductLabel: "EX44",
+		}))
+	})
+})
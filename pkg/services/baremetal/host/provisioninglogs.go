@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	secretutil "github.com/syself/cluster-api-provider-hetzner/pkg/secrets"
+)
+
+const (
+	defaultProvisioningLogsHistoryLimit = 3
+
+	// maxProvisioningLogBytes limits the size of a single log, so that the logs of all attempts fit into one secret.
+	maxProvisioningLogBytes = 48 * 1024
+
+	provisioningLogInstallImage = "installimage.log"
+	provisioningLogCloudInit    = "cloud-init-output.log"
+	provisioningLogPostInstall  = "post-install.log"
+	provisioningLogDiskImage    = "write-disk-image.log"
+)
+
+// provisioningLogRedactions remove credentials that the user data or the post install script might contain.
+var provisioningLogRedactions = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`), "[REDACTED PRIVATE KEY]"},
+	{regexp.MustCompile(`(--(?:token|certificate-key)[ =])\S+`), "${1}[REDACTED]"},
+	// bootstrap tokens of kubeadm
+	{regexp.MustCompile(`\b[a-z0-9]{6}\.[a-z0-9]{16}\b`), "[REDACTED TOKEN]"},
+	{regexp.MustCompile(`(?i)((?:password|passwd|secret|token|certificate-key|api[_-]?key)["']?\s*[:=]\s*["']?)[^\s"',]+`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`(?i)(authorization:\s*(?:bearer|basic)\s+)\S+`), "${1}[REDACTED]"},
+}
+
+// redactCredentials removes credentials from the output of the provisioning. It is used for the stored logs as
+// well as for events and log messages that contain the output.
+func redactCredentials(content string) string {
+	for _, redaction := range provisioningLogRedactions {
+		content = redaction.re.ReplaceAllString(content, redaction.replacement)
+	}
+	return content
+}
+
+// redactProvisioningLog removes credentials from the log and truncates it to maxProvisioningLogBytes. The end
+// of the log is kept, as it usually contains the error. A marker at the beginning shows how much got truncated.
+func redactProvisioningLog(content string) (log string, truncated bool) {
+	content = redactCredentials(content)
+	if len(content) <= maxProvisioningLogBytes {
+		return content, false
+	}
+	return fmt.Sprintf("[... truncated the first %d bytes, only the last %d bytes of the log are stored ...]\n%s",
+		len(content)-maxProvisioningLogBytes, maxProvisioningLogBytes, content[len(content)-maxProvisioningLogBytes:]), true
+}
+
+func provisioningLogsHistoryLimit(host *infrav1.HetznerBareMetalHost) int {
+	if host.Spec.ProvisioningLogsHistoryLimit == nil {
+		return defaultProvisioningLogsHistoryLimit
+	}
+	return *host.Spec.ProvisioningLogsHistoryLimit
+}
+
+// startProvisioningAttempt starts a new provisioning attempt, so that the logs of the previous attempt are kept.
+func (s *Service) startProvisioningAttempt() {
+	host := s.scope.HetznerBareMetalHost
+	if provisioningLogsHistoryLimit(host) == 0 {
+		return
+	}
+	if host.Spec.Status.ProvisioningLogs == nil {
+		host.Spec.Status.ProvisioningLogs = &infrav1.ProvisioningLogsStatus{SecretName: provisioningLogsSecretName(host)}
+	}
+	host.Spec.Status.ProvisioningLogs.LastAttempt++
+}
+
+// storeProvisioningLog stores the redacted log of the current provisioning attempt in the secret of the host and
+// removes the logs of attempts that exceed the history limit. The logs are only for debugging, so errors do not
+// stop the provisioning.
+func (s *Service) storeProvisioningLog(ctx context.Context, name, content string) {
+	host := s.scope.HetznerBareMetalHost
+	limit := provisioningLogsHistoryLimit(host)
+	if limit == 0 {
+		return
+	}
+	if host.Spec.Status.ProvisioningLogs == nil {
+		s.startProvisioningAttempt()
+	}
+	status := host.Spec.Status.ProvisioningLogs
+	log, truncated := redactProvisioningLog(content)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      status.SecretName,
+			Namespace: host.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, s.scope.Client, secret, func() error {
+		// the label makes sure that the secret is in the cache of the controller.
+		metav1.SetMetaDataLabel(&secret.ObjectMeta, secretutil.LabelEnvironmentName, secretutil.LabelEnvironmentValue)
+		if err := controllerutil.SetOwnerReference(host, secret, s.scope.Client.Scheme()); err != nil {
+			return fmt.Errorf("failed to set owner reference: %w", err)
+		}
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		for key := range secret.Data {
+			if attempt, ok := provisioningLogAttempt(key); ok && attempt <= status.LastAttempt-limit {
+				delete(secret.Data, key)
+			}
+		}
+		secret.Data[provisioningLogKey(status.LastAttempt, name)] = []byte(log)
+		return nil
+	})
+	if err != nil {
+		record.Warnf(host, "StoreProvisioningLogFailed", "Failed to store %s in secret %s: %s", name, status.SecretName, err.Error())
+		return
+	}
+	if truncated {
+		record.Eventf(host, "ProvisioningLogTruncated", "Stored only the last %d bytes of %s in secret %s",
+			maxProvisioningLogBytes, name, status.SecretName)
+	}
+}
+
+func provisioningLogsSecretName(host *infrav1.HetznerBareMetalHost) string {
+	return host.Name + "-provisioning-logs"
+}
+
+func provisioningLogKey(attempt int, name string) string {
+	return fmt.Sprintf("%d-%s", attempt, name)
+}
+
+// provisioningLogAttempt returns the attempt of a key of the secret with the provisioning logs.
+func provisioningLogAttempt(key string) (int, bool) {
+	prefix, _, found := strings.Cut(key, "-")
+	if !found {
+		return 0, false
+	}
+	attempt, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0, false
+	}
+	return attempt, true
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/syself/hrobot-go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+)
+
+const (
+	// robotLinuxSSHPort is the port of the SSH server of a distribution installed by the Robot API.
+	robotLinuxSSHPort = 22
+
+	// robotLinuxInstallTimeout is the maximum duration of the Linux installation of the Robot API.
+	robotLinuxInstallTimeout = 45 * time.Minute
+
+	defaultRobotLinuxLang = "en"
+)
+
+// actionImageInstallingRobotLinux installs the operating system with the Linux installation of the Robot API.
+// The server reboots from the rescue system into the installer of Hetzner. Afterwards, the cloud-init data is
+// written to the installed system, and the server gets rebooted again, so that cloud-init runs.
+func (s *Service) actionImageInstallingRobotLinux(ctx context.Context) actionResult {
+	if s.scope.HetznerBareMetalHost.Spec.Status.RobotLinuxInstallStarted == nil {
+		return s.startRobotLinuxInstall()
+	}
+	return s.finishRobotLinuxInstall(ctx)
+}
+
+func (s *Service) startRobotLinuxInstall() actionResult {
+	host := s.scope.HetznerBareMetalHost
+	robotLinux := host.Spec.Status.InstallImage.RobotLinux
+	if robotLinux == nil || robotLinux.Dist == "" {
+		msg := "no distribution specified for install method " + string(infrav1.InstallMethodRobotLinux)
+		conditions.MarkFalse(
+			host,
+			infrav1.ProvisionSucceededCondition,
+			infrav1.ImageSpecInvalidReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			msg,
+		)
+		return s.recordActionFailure(infrav1.ProvisioningError, msg)
+	}
+	lang := robotLinux.Lang
+	if lang == "" {
+		lang = defaultRobotLinuxLang
+	}
+
+	// The installed system gets the OS SSH key as authorized key.
+	sshKey, actResult := s.ensureSSHKey(host.Spec.Status.SSHSpec.SecretRef, s.scope.OSSSHSecret)
+	if _, isComplete := actResult.(actionComplete); !isComplete {
+		return actResult
+	}
+	host.Spec.Status.SSHStatus.OSKey = &sshKey
+
+	// Only one boot configuration can be active, so the rescue system has to be deactivated first.
+	if _, err := s.scope.RobotClient.DeleteBootRescue(host.Spec.ServerID); err != nil {
+		s.handleRobotRateLimitExceeded(err, "DeleteBootRescue")
+		return actionError{err: fmt.Errorf("failed to delete boot rescue: %w", err)}
+	}
+	if _, err := s.scope.RobotClient.SetBootLinux(host.Spec.ServerID, robotLinux.Dist, lang, sshKey.Fingerprint); err != nil {
+		s.handleRobotRateLimitExceeded(err, "SetBootLinux")
+		return actionError{err: fmt.Errorf("failed to set boot linux: %w", err)}
+	}
+
+	creds := sshclient.CredentialsFromSecret(s.scope.RescueSSHSecret, s.scope.HetznerCluster.Spec.SSHKeys.RobotRescueSecretRef)
+	sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
+		PrivateKey:    creds.PrivateKey,
+		Port:          rescuePort,
+		IP:            host.Spec.Status.GetIPAddress(),
+		VerifyHostKey: s.rescueHostKeyVerifier().verify,
+		JumpHost:      s.sshJumpHost(),
+	})
+	if err := handleSSHError(sshClient.Reboot()); err != nil {
+		return actionError{err: fmt.Errorf("failed to reboot into linux installation of robot API: %w", err)}
+	}
+
+	record.Eventf(host, "RobotLinuxInstallStarted", "Installing %s with the linux installation of the robot API", robotLinux.Dist)
+	createSSHRebootEvent(host, "Rebooting into the linux installation of the robot API")
+
+	s.osHostKeyVerifier().reset()
+	now := metav1.Now()
+	host.Spec.Status.RobotLinuxInstallStarted = &now
+	s.startProvisioningAttempt()
+	return actionContinue{delay: time.Minute}
+}
+
+func (s *Service) finishRobotLinuxInstall(ctx context.Context) actionResult {
+	host := s.scope.HetznerBareMetalHost
+
+	if hasTimedOut(host.Spec.Status.RobotLinuxInstallStarted, robotLinuxInstallTimeout) {
+		// The installation gets cancelled, so that it starts again from the rescue system.
+		if err := s.cancelRobotLinuxInstall(); err != nil {
+			return actionError{err: err}
+		}
+		msg := fmt.Sprintf("linux installation of robot API did not finish within %s", robotLinuxInstallTimeout)
+		record.Warn(host, "RobotLinuxInstallTimedOut", msg)
+		return s.recordActionFailure(infrav1.ProvisioningError, msg)
+	}
+
+	// The boot configuration stays active until the installer has been booted.
+	linux, err := s.scope.RobotClient.GetBootLinux(host.Spec.ServerID)
+	if err != nil {
+		s.handleRobotRateLimitExceeded(err, "GetBootLinux")
+		return actionError{err: fmt.Errorf("failed to get boot linux: %w", err)}
+	}
+	if linux.Active {
+		return actionContinue{delay: 30 * time.Second}
+	}
+
+	hostKeyVerifier := s.osHostKeyVerifier()
+	sshClient := s.scope.SSHClientFactory.NewClient(sshclient.Input{
+		PrivateKey:    sshclient.CredentialsFromSecret(s.scope.OSSSHSecret, host.Spec.Status.SSHSpec.SecretRef).PrivateKey,
+		Port:          robotLinuxSSHPort,
+		IP:            host.Spec.Status.GetIPAddress(),
+		VerifyHostKey: hostKeyVerifier.verify,
+		JumpHost:      s.sshJumpHost(),
+	})
+
+	// The installer runs in a system like the rescue system. The installation is done as soon as the
+	// installed system is reachable.
+	out := sshClient.GetHostName()
+	if hostName := trimLineBreak(out.StdOut); out.Err != nil || hostName == "" || hostName == rescue {
+		s.scope.Logger.Info("Installed system is not reachable yet. Will retry some seconds later.",
+			"hostname", hostName, "stderr", out.StdErr, "err", out.Err)
+		return actionContinue{delay: 30 * time.Second}
+	}
+
+	// from now on we know that we talk to the installed system
+	hostKeyVerifier.trust()
+
+	vSwitchScript, actionRes := s.vSwitchNetworkConfig()
+	if actionRes != nil {
+		return actionRes
+	}
+
+	postInstallScript, err := s.buildPostInstallScript(ctx, vSwitchScript)
+	if err != nil {
+		return actionError{err: err}
+	}
+	postInstallScript += robotLinuxCloudInitScript(s.scope.Hostname())
+
+	if err := handleSSHError(sshClient.CreatePostInstallScript(postInstallScript)); err != nil {
+		return actionError{err: fmt.Errorf("failed to create post install script: %w", err)}
+	}
+
+	out = sshClient.ExecutePostInstallScript()
+	s.storeProvisioningLog(ctx, provisioningLogPostInstall, out.StdOut+out.StdErr)
+	if out.Err != nil {
+		record.Warn(host, "ExecutePostInstallScriptFailed", redactCredentials(out.String()))
+		return actionError{err: fmt.Errorf("failed to execute post install script: %w", out.Err)}
+	}
+
+	// Update name in robot API
+	if _, err := s.scope.RobotClient.SetBMServerName(host.Spec.ServerID, s.scope.Hostname()); err != nil {
+		record.Warn(host, "SetBMServerNameFailed", err.Error())
+		s.handleRobotRateLimitExceeded(err, "SetBMServerName")
+		return actionError{err: fmt.Errorf("failed to update name of host in robot API: %w", err)}
+	}
+
+	if err := handleSSHError(sshClient.Reboot()); err != nil {
+		err = fmt.Errorf("failed to reboot server (after linux installation): %w", err)
+		record.Warn(host, "RebootFailed", err.Error())
+		return actionError{err: err}
+	}
+	createSSHRebootEvent(host, "linux distribution of robot API and cloud-init data got installed")
+
+	host.Spec.Status.RobotLinuxInstallStarted = nil
+	host.ClearError()
+	return actionComplete{} // next: ensure-provisioned
+}
+
+// cancelRobotLinuxInstall deactivates the Linux installation of the Robot API if it has been started, so that
+// the server can boot into the rescue system again.
+func (s *Service) cancelRobotLinuxInstall() error {
+	host := s.scope.HetznerBareMetalHost
+	if host.Spec.Status.RobotLinuxInstallStarted == nil {
+		return nil
+	}
+	if _, err := s.scope.RobotClient.DeleteBootLinux(host.Spec.ServerID); err != nil && !models.IsError(err, models.ErrorCodeNotFound) {
+		s.handleRobotRateLimitExceeded(err, "DeleteBootLinux")
+		return fmt.Errorf("failed to delete boot linux: %w", err)
+	}
+	host.Spec.Status.RobotLinuxInstallStarted = nil
+	return nil
+}
+
+// robotLinuxCloudInitScript makes sure that cloud-init runs with the data of the post install script on the next
+// boot. The distributions of the Robot API might not include cloud-init.
+func robotLinuxCloudInitScript(hostName string) string {
+	return fmt.Sprintf(`
+# prepare cloud-init in the system installed by the robot API
+
+if ! command -v cloud-init >/dev/null 2>&1; then
+	if command -v apt-get >/dev/null 2>&1; then
+		DEBIAN_FRONTEND=noninteractive apt-get update -q
+		DEBIAN_FRONTEND=noninteractive apt-get install -y -q cloud-init
+	elif command -v dnf >/dev/null 2>&1; then
+		dnf install -y cloud-init
+	else
+		echo "ERROR: cloud-init is not installed and no supported package manager was found"
+		exit 4
+	fi
+fi
+
+hostnamectl set-hostname %s
+cloud-init clean --logs
+`, hostName)
+}
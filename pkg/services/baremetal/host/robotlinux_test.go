@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"github.com/syself/hrobot-go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	bmmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
+	sshmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/ssh"
+	sshclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/ssh"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("actionImageInstallingRobotLinux", func() {
+	var (
+		host      *infrav1.HetznerBareMetalHost
+		robotMock *robotmock.Client
+	)
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost(
+			"test-host",
+			"default",
+			helpers.WithSSHSpecInclPorts(23, 24),
+			helpers.WithConsumerRef(),
+		)
+		host.Spec.Status.ProvisioningState = infrav1.StateImageInstalling
+		host.Spec.Status.InstallImage = &infrav1.InstallImage{
+			Method:     infrav1.InstallMethodRobotLinux,
+			RobotLinux: &infrav1.RobotLinuxInstall{Dist: "Ubuntu 24.04 LTS base"},
+		}
+
+		robotMock = &robotmock.Client{}
+	})
+
+	It("activates the linux installation and reboots from the rescue system", func() {
+		robotMock.On("ListSSHKeys").Return([]models.Key{{Name: "my-name", Fingerprint: "os-fingerprint"}}, nil)
+		robotMock.On("DeleteBootRescue", mock.Anything).Return(&models.Rescue{}, nil)
+		robotMock.On("SetBootLinux", mock.Anything, "Ubuntu 24.04 LTS base", "en", "os-fingerprint").Return(&models.Linux{Active: true}, nil)
+
+		rescueSSHMock := &sshmock.Client{}
+		rescueSSHMock.On("Reboot").Return(sshclient.Output{})
+
+		host.Spec.Status.SSHStatus.OSHostKeyFingerprint = "SHA256:old"
+
+		service := newTestService(host, robotMock, bmmock.NewSSHFactory(rescueSSHMock, nil, nil),
+			helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), helpers.GetDefaultSSHSecret("rescue-ssh-secret", "default"))
+
+		Expect(service.actionImageInstallingRobotLinux(context.Background())).To(BeAssignableToTypeOf(actionContinue{}))
+		Expect(host.Spec.Status.RobotLinuxInstallStarted).ToNot(BeNil())
+		Expect(host.Spec.Status.SSHStatus.OSKey).ToNot(BeNil())
+		Expect(host.Spec.Status.SSHStatus.OSHostKeyFingerprint).To(BeEmpty())
+		robotMock.AssertCalled(GinkgoT(), "DeleteBootRescue", mock.Anything)
+		rescueSSHMock.AssertCalled(GinkgoT(), "Reboot")
+	})
+
+	It("fails if no distribution is specified", func() {
+		host.Spec.Status.InstallImage.RobotLinux = nil
+
+		service := newTestService(host, robotMock, bmmock.NewSSHFactory(nil, nil, nil), nil, nil)
+
+		Expect(service.actionImageInstallingRobotLinux(context.Background())).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.Status.ErrorType).To(Equal(infrav1.ProvisioningError))
+		robotMock.AssertNotCalled(GinkgoT(), "SetBootLinux", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("waits as long as the installer has not been booted", func() {
+		host.Spec.Status.RobotLinuxInstallStarted = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		robotMock.On("GetBootLinux", mock.Anything).Return(&models.Linux{Active: true}, nil)
+
+		osSSHMock := &sshmock.Client{}
+		service := newTestService(host, robotMock, bmmock.NewSSHFactory(nil, osSSHMock, nil),
+			helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), nil)
+
+		Expect(service.actionImageInstallingRobotLinux(context.Background())).To(BeAssignableToTypeOf(actionContinue{}))
+		osSSHMock.AssertNotCalled(GinkgoT(), "GetHostName")
+	})
+
+	It("waits as long as the installed system is not reachable", func() {
+		host.Spec.Status.RobotLinuxInstallStarted = &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+		robotMock.On("GetBootLinux", mock.Anything).Return(&models.Linux{Active: false}, nil)
+
+		osSSHMock := &sshmock.Client{}
+		osSSHMock.On("GetHostName").Return(sshclient.Output{Err: sshclient.ErrConnectionRefused})
+
+		service := newTestService(host, robotMock, bmmock.NewSSHFactory(nil, osSSHMock, nil),
+			helpers.GetDefaultSSHSecret(osSSHKeyName, "default"), nil)
+
+		Expect(service.actionImageInstallingRobotLinux(context.Background())).To(BeAssignableToTypeOf(actionContinue{}))
+		Expect(host.Spec.Status.SSHStatus.OSHostKeyFingerprint).To(BeEmpty())
+		osSSHMock.AssertNotCalled(GinkgoT(), "CreatePostInstallScript", mock.Anything)
+	})
+
+	It("cancels the installation and records a failure if the installation times out", func() {
+		host.Spec.Status.RobotLinuxInstallStarted = &metav1.Time{Time: time.Now().Add(-robotLinuxInstallTimeout - time.Minute)}
+		robotMock.On("DeleteBootLinux", mock.Anything).Return(&models.Linux{}, nil)
+
+		service := newTestService(host, robotMock, bmmock.NewSSHFactory(nil, nil, nil), nil, nil)
+
+		Expect(service.actionImageInstallingRobotLinux(context.Background())).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.Status.RobotLinuxInstallStarted).To(BeNil())
+		Expect(host.Spec.Status.ErrorType).To(Equal(infrav1.ProvisioningError))
+		Expect(host.Spec.Status.ErrorCount).To(Equal(1))
+		robotMock.AssertCalled(GinkgoT(), "DeleteBootLinux", mock.Anything)
+		robotMock.AssertNotCalled(GinkgoT(), "GetBootLinux", mock.Anything)
+	})
+})
+
+var _ = Describe("cancelRobotLinuxInstall", func() {
+	var (
+		host      *infrav1.HetznerBareMetalHost
+		robotMock *robotmock.Client
+	)
+
+	BeforeEach(func() {
+		host = helpers.BareMetalHost("test-host", "default")
+		robotMock = &robotmock.Client{}
+	})
+
+	It("deactivates a started linux installation", func() {
+		host.Spec.Status.RobotLinuxInstallStarted = &metav1.Time{Time: time.Now()}
+		robotMock.On("DeleteBootLinux", mock.Anything).Return(&models.Linux{}, nil)
+
+		service := newTestService(host, robotMock, nil, nil, nil)
+
+		Expect(service.cancelRobotLinuxInstall()).To(Succeed())
+		Expect(host.Spec.Status.RobotLinuxInstallStarted).To(BeNil())
+		robotMock.AssertCalled(GinkgoT(), "DeleteBootLinux", mock.Anything)
+	})
+
+	It("ignores a linux installation that is not active anymore", func() {
+		host.Spec.Status.RobotLinuxInstallStarted = &metav1.Time{Time: time.Now()}
+		robotMock.On("DeleteBootLinux", mock.Anything).Return(nil, models.Error{Code: models.ErrorCodeNotFound})
+
+		service := newTestService(host, robotMock, nil, nil, nil)
+
+		Expect(service.cancelRobotLinuxInstall()).To(Succeed())
+		Expect(host.Spec.Status.RobotLinuxInstallStarted).To(BeNil())
+	})
+
+	It("does nothing if no linux installation has been started", func() {
+		service := newTestService(host, robotMock, nil, nil, nil)
+
+		Expect(service.cancelRobotLinuxInstall()).To(Succeed())
+		robotMock.AssertNotCalled(GinkgoT(), "DeleteBootLinux", mock.Anything)
+	})
+})
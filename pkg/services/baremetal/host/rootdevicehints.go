@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+var (
+	errNoMatchingStorageDevice      = errors.New("no storage device matches the root device hints policy")
+	errUnknownRootDeviceHintsPolicy = errors.New("unknown root device hints strategy")
+)
+
+// selectRootDeviceHints sets the root device hints of the host according to its RootDeviceHintsPolicy. The
+// decision is recorded in an event and in the RootDeviceHintsSelected condition.
+func (s *Service) selectRootDeviceHints() error {
+	host := s.scope.HetznerBareMetalHost
+	policy := host.Spec.RootDeviceHintsPolicy
+
+	hints, err := rootDeviceHintsFromPolicy(*policy, host.Spec.Status.HardwareDetails.Storage)
+	if err != nil {
+		conditions.MarkFalse(
+			host,
+			infrav1.RootDeviceHintsSelectedCondition,
+			infrav1.NoMatchingStorageDeviceReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			err.Error(),
+		)
+		return err
+	}
+
+	msg := fmt.Sprintf("selected WWNs %s by strategy %s with disk type %s",
+		strings.Join(hints.ListOfWWN(), ", "), policy.Strategy, diskTypeOrDefault(policy.DiskType))
+	host.Spec.RootDeviceHints = hints
+	conditions.Set(host, &clusterv1.Condition{
+		Type:    infrav1.RootDeviceHintsSelectedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.RootDeviceHintsSelectedByPolicyReason,
+		Message: msg,
+	})
+	record.Eventf(host, "RootDeviceHintsSelected", "Root device hints: %s", msg)
+	return nil
+}
+
+// rootDeviceHintsFromPolicy resolves the policy into root device hints. Ties are broken by the WWN, so
+// that the same storage devices result in the same hints.
+func rootDeviceHintsFromPolicy(policy infrav1.RootDeviceHintsPolicy, storage []infrav1.Storage) (*infrav1.RootDeviceHints, error) {
+	diskType := diskTypeOrDefault(policy.DiskType)
+
+	candidates := make([]infrav1.Storage, 0, len(storage))
+	for _, disk := range storage {
+		if disk.WWN == "" || !disk.MatchesDiskType(diskType) {
+			continue
+		}
+		candidates = append(candidates, disk)
+	}
+	slices.SortFunc(candidates, func(a, b infrav1.Storage) int {
+		return cmp.Or(cmp.Compare(a.SizeBytes, b.SizeBytes), strings.Compare(a.WWN, b.WWN))
+	})
+
+	switch policy.Strategy {
+	case infrav1.RootDeviceHintsStrategySmallestDisk:
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("%w: found no disk of type %s", errNoMatchingStorageDevice, diskType)
+		}
+		return &infrav1.RootDeviceHints{WWN: candidates[0].WWN}, nil
+
+	case infrav1.RootDeviceHintsStrategySmallestIdenticalPair:
+		// candidates are sorted by size, so the first pair that is found is the smallest one
+		for i, disk := range candidates {
+			for _, other := range candidates[i+1:] {
+				if other.SizeBytes != disk.SizeBytes {
+					break
+				}
+				if other.Model == disk.Model {
+					return &infrav1.RootDeviceHints{Raid: infrav1.Raid{WWN: []string{disk.WWN, other.WWN}}}, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("%w: found no two disks of type %s with the same model and size", errNoMatchingStorageDevice, diskType)
+	}
+
+	return nil, fmt.Errorf("%w: %q", errUnknownRootDeviceHintsPolicy, policy.Strategy)
+}
+
+func diskTypeOrDefault(diskType infrav1.DiskType) infrav1.DiskType {
+	if diskType == "" {
+		return infrav1.DiskTypeAny
+	}
+	return diskType
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	bmmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("rootDeviceHintsFromPolicy", func() {
+	storage := []infrav1.Storage{
+		{SizeBytes: 4000787030016, Model: "TOSHIBA MG08ACA16TE", WWN: "0x5000039b38d0b6a1", HCTL: "0:0:0:0", Rota: true},
+		{SizeBytes: 4000787030016, Model: "TOSHIBA MG08ACA16TE", WWN: "0x5000039b38d0b6a0", HCTL: "1:0:0:0", Rota: true},
+		{SizeBytes: 480103981056, Model: "SAMSUNG MZ7LH480HAHQ", WWN: "0x5002538e09a1b2c3", HCTL: "2:0:0:0"},
+		{SizeBytes: 1024209543168, Model: "SAMSUNG MZVL21T0HCLR", WWN: "eui.002538b411b2cee9"},
+		{SizeBytes: 1024209543168, Model: "SAMSUNG MZVL21T0HCLR", WWN: "eui.002538b411b2cee8"},
+		{SizeBytes: 3068773888, Model: "virtual", HCTL: "3:0:0:0"},
+	}
+
+	DescribeTable("selects the disks",
+		func(policy infrav1.RootDeviceHintsPolicy, expectedHints *infrav1.RootDeviceHints) {
+			hints, err := rootDeviceHintsFromPolicy(policy, storage)
+			Expect(err).To(Succeed())
+			Expect(hints).To(Equal(expectedHints))
+		},
+		Entry("smallest disk ignores disks without WWN",
+			infrav1.RootDeviceHintsPolicy{Strategy: infrav1.RootDeviceHintsStrategySmallestDisk},
+			&infrav1.RootDeviceHints{WWN: "0x5002538e09a1b2c3"}),
+		Entry("smallest NVMe disk",
+			infrav1.RootDeviceHintsPolicy{Strategy: infrav1.RootDeviceHintsStrategySmallestDisk, DiskType: infrav1.DiskTypeNVMe},
+			&infrav1.RootDeviceHints{WWN: "eui.002538b411b2cee8"}),
+		Entry("smallest identical pair",
+			infrav1.RootDeviceHintsPolicy{Strategy: infrav1.RootDeviceHintsStrategySmallestIdenticalPair, DiskType: infrav1.DiskTypeSSD},
+			&infrav1.RootDeviceHints{Raid: infrav1.Raid{WWN: []string{"eui.002538b411b2cee8", "eui.002538b411b2cee9"}}}),
+		Entry("smallest identical pair of HDDs",
+			infrav1.RootDeviceHintsPolicy{Strategy: infrav1.RootDeviceHintsStrategySmallestIdenticalPair, DiskType: infrav1.DiskTypeHDD},
+			&infrav1.RootDeviceHints{Raid: infrav1.Raid{WWN: []string{"0x5000039b38d0b6a0", "0x5000039b38d0b6a1"}}}),
+	)
+
+	It("gives an error if no disks match", func() {
+		_, err := rootDeviceHintsFromPolicy(infrav1.RootDeviceHintsPolicy{
+			Strategy: infrav1.RootDeviceHintsStrategySmallestIdenticalPair,
+			DiskType: infrav1.DiskTypeSSD,
+		}, storage[2:4])
+		Expect(err).To(MatchError(errNoMatchingStorageDevice))
+	})
+})
+
+var _ = Describe("actionRegistering with root device hints policy", func() {
+	storageStdOut := `NAME="loop0" TYPE="loop" HCTL="" MODEL="" VENDOR="" SERIAL="" SIZE="3068773888" WWN="" ROTA="0"
+NAME="nvme1n1" TYPE="disk" HCTL="" MODEL="SAMSUNG MZVLB512HAJQ-00000" VENDOR="" SERIAL="S3W8NX0N811179" SIZE="512110190592" WWN="eui.0025388801b4dff3" ROTA="0"
+NAME="nvme0n1" TYPE="disk" HCTL="" MODEL="SAMSUNG MZVLB512HAJQ-00000" VENDOR="" SERIAL="S3W8NX0N811178" SIZE="512110190592" WWN="eui.0025388801b4dff2" ROTA="0"`
+
+	It("selects the root device hints and completes", func() {
+		host := helpers.BareMetalHost("test-host", "default", helpers.WithIPv4(), helpers.WithConsumerRef())
+		host.Spec.RootDeviceHintsPolicy = &infrav1.RootDeviceHintsPolicy{Strategy: infrav1.RootDeviceHintsStrategySmallestIdenticalPair}
+		host.Spec.Status.InstallImage = &infrav1.InstallImage{Swraid: 1}
+		sshMock := registeringSSHMock(storageStdOut)
+		service := newTestService(host, nil, bmmock.NewSSHFactory(sshMock, sshMock, sshMock), nil, helpers.GetDefaultSSHSecret(rescueSSHKeyName, "default"))
+
+		Expect(service.actionRegistering(context.Background())).To(BeAssignableToTypeOf(actionComplete{}))
+		Expect(host.Spec.RootDeviceHints).To(Equal(&infrav1.RootDeviceHints{
+			Raid: infrav1.Raid{WWN: []string{"eui.0025388801b4dff2", "eui.0025388801b4dff3"}},
+		}))
+		Expect(conditions.IsTrue(host, infrav1.RootDeviceHintsSelectedCondition)).To(BeTrue())
+		Expect(conditions.GetReason(host, infrav1.RootDeviceHintsSelectedCondition)).To(Equal(infrav1.RootDeviceHintsSelectedByPolicyReason))
+	})
+
+	It("fails if the policy cannot be fulfilled", func() {
+		host := helpers.BareMetalHost("test-host", "default", helpers.WithIPv4(), helpers.WithConsumerRef())
+		host.Spec.RootDeviceHintsPolicy = &infrav1.RootDeviceHintsPolicy{
+			Strategy: infrav1.RootDeviceHintsStrategySmallestDisk,
+			DiskType: infrav1.DiskTypeHDD,
+		}
+		host.Spec.Status.InstallImage = &infrav1.InstallImage{}
+		sshMock := registeringSSHMock(storageStdOut)
+		service := newTestService(host, nil, bmmock.NewSSHFactory(sshMock, sshMock, sshMock), nil, helpers.GetDefaultSSHSecret(rescueSSHKeyName, "default"))
+
+		Expect(service.actionRegistering(context.Background())).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(host.Spec.RootDeviceHints).To(BeNil())
+		Expect(host.Spec.Status.ErrorType).To(Equal(infrav1.RegistrationError))
+		Expect(conditions.GetReason(host, infrav1.RootDeviceHintsSelectedCondition)).To(Equal(infrav1.NoMatchingStorageDeviceReason))
+	})
+})
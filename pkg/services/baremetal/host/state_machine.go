@@ -58,6 +58,8 @@ func (hsm *hostStateMachine) handlers() map[infrav1.ProvisioningState]stateHandl
 	return map[infrav1.ProvisioningState]stateHandler{
 		infrav1.StatePreparing:         hsm.handlePreparing,
 		infrav1.StateRegistering:       hsm.handleRegistering,
+		infrav1.StateBurnIn:            hsm.handleBurnIn,
+		infrav1.StateAdopting:          hsm.handleAdopting,
 		infrav1.StateImageInstalling:   hsm.handleImageInstalling,
 		infrav1.StateEnsureProvisioned: hsm.handleEnsureProvisioned,
 		infrav1.StateProvisioned:       hsm.handleProvisioned,
@@ -112,7 +114,7 @@ func (hsm *hostStateMachine) checkInitiateDelete() bool {
 	switch hsm.nextState {
 	default:
 		hsm.nextState = infrav1.StateDeleting
-	case infrav1.StateRegistering, infrav1.StateImageInstalling,
+	case infrav1.StateRegistering, infrav1.StateBurnIn, infrav1.StateImageInstalling,
 		infrav1.StateEnsureProvisioned, infrav1.StateProvisioned:
 		hsm.nextState = infrav1.StateDeprovisioning
 	case infrav1.StateDeprovisioning:
@@ -198,7 +200,7 @@ func (hsm *hostStateMachine) updateRescueSSHStatusAndValidateKey(rescueSSHSecret
 	if !hsm.host.Spec.Status.SSHStatus.CurrentRescue.Match(*rescueSSHSecret) {
 		// Take action depending on state
 		switch hsm.nextState {
-		case infrav1.StatePreparing, infrav1.StateRegistering, infrav1.StateImageInstalling:
+		case infrav1.StatePreparing, infrav1.StateRegistering, infrav1.StateBurnIn, infrav1.StateImageInstalling:
 			msg := "stopped provisioning host as rescue ssh secret was updated"
 			record.Warn(hsm.host, "HostProvisioningStopped", msg)
 			hsm.log.V(1).Info(msg, "state", hsm.nextState)
@@ -260,22 +262,63 @@ func (hsm *hostStateMachine) handleRegistering(ctx context.Context) actionResult
 	}
 
 	actResult := hsm.reconciler.actionRegistering(ctx)
+	if _, ok := actResult.(actionComplete); ok {
+		if needsBurnIn(hsm.host) {
+			hsm.nextState = infrav1.StateBurnIn
+		} else {
+			hsm.nextState = infrav1.StateImageInstalling
+		}
+	}
+	return actResult
+}
+
+func (hsm *hostStateMachine) handleBurnIn(ctx context.Context) actionResult {
+	if hsm.provisioningCancelled() {
+		hsm.nextState = infrav1.StateDeprovisioning
+		return actionComplete{}
+	}
+
+	actResult := hsm.reconciler.actionBurnIn(ctx)
 	if _, ok := actResult.(actionComplete); ok {
 		hsm.nextState = infrav1.StateImageInstalling
 	}
 	return actResult
 }
 
+func (hsm *hostStateMachine) handleAdopting(ctx context.Context) actionResult {
+	if hsm.provisioningCancelled() {
+		// Nothing has been changed on the server, so there is nothing to deprovision.
+		hsm.host.ClearError()
+		conditions.Delete(hsm.host, infrav1.ProvisionSucceededCondition)
+		hsm.nextState = infrav1.StateNone
+		return actionComplete{}
+	}
+
+	actResult := hsm.reconciler.actionAdopting(ctx)
+	if _, ok := actResult.(actionComplete); ok {
+		hsm.nextState = infrav1.StateProvisioned
+	}
+	return actResult
+}
+
 func (hsm *hostStateMachine) handleImageInstalling(ctx context.Context) actionResult {
 	if hsm.provisioningCancelled() {
 		hsm.nextState = infrav1.StateDeprovisioning
 		return actionComplete{}
 	}
 
+	robotLinuxInstallStarted := hsm.host.Spec.Status.RobotLinuxInstallStarted != nil
+
 	actResult := hsm.reconciler.actionImageInstalling(ctx)
 	switch actResult.(type) {
 	case actionComplete:
 		hsm.nextState = infrav1.StateEnsureProvisioned
+	case actionFailed:
+		// The linux installation of the robot API has been cancelled after a timeout. The server has to boot
+		// into the rescue system again before the installation can be retried.
+		if robotLinuxInstallStarted && hsm.host.Spec.Status.RobotLinuxInstallStarted == nil {
+			hsm.nextState = infrav1.StatePreparing
+		}
 	case actionError:
 		// re-enable rescue system. If installimage failed, then it is likely, that
 		// the next run (without reboot) fails with this error:
@@ -307,6 +350,13 @@ func (hsm *hostStateMachine) handleProvisioned(ctx context.Context) actionResult
 		hsm.nextState = infrav1.StateDeprovisioning
 		return actionComplete{}
 	}
+
+	if hsm.host.HasReprovisionAnnotation() {
+		// Like after a failed installimage, the host boots into the rescue system before the image gets installed.
+		hsm.reconciler.startReprovisioning()
+		hsm.nextState = infrav1.StatePreparing
+		return actionComplete{}
+	}
 	return hsm.reconciler.actionProvisioned(ctx)
 }
 
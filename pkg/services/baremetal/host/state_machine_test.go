@@ -17,13 +17,19 @@ limitations under the License.
 package host
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"github.com/syself/hrobot-go/models"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
 	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
 )
 
@@ -230,3 +236,55 @@ var _ = Describe("updateSSHKey", func() {
 		}),
 	)
 })
+
+var _ = Describe("handleProvisioned", func() {
+	It("prepares the host again if it should be reprovisioned", func() {
+		host := helpers.BareMetalHost(
+			"test-host",
+			"default",
+			helpers.WithSSHStatus(),
+			helpers.WithSSHSpecInclPorts(23, 24),
+		)
+		host.Annotations = map[string]string{
+			infrav1.ReprovisionAnnotation: "",
+			infrav1.RebootAnnotation:      "",
+		}
+		host.Spec.Status.ProvisioningState = infrav1.StateProvisioned
+		host.Spec.Status.InstallImage = &infrav1.InstallImage{Image: infrav1.Image{Name: "ubuntu-24.04"}}
+		host.Spec.Status.Rebooted = true
+
+		hsm := newTestHostStateMachine(host, newTestService(host, nil, nil, nil, nil))
+		hsm.nextState = infrav1.StateProvisioned
+
+		Expect(hsm.handleProvisioned(context.Background())).To(BeAssignableToTypeOf(actionComplete{}))
+		Expect(hsm.nextState).To(Equal(infrav1.StatePreparing))
+		Expect(host.Annotations).To(BeEmpty())
+		Expect(host.Spec.Status.Rebooted).To(BeFalse())
+	})
+})
+
+var _ = Describe("handleImageInstalling", func() {
+	It("prepares the host again if the linux installation of the robot API timed out", func() {
+		host := helpers.BareMetalHost(
+			"test-host",
+			"default",
+			helpers.WithSSHSpecInclPorts(23, 24),
+			helpers.WithConsumerRef(),
+		)
+		host.Spec.Status.ProvisioningState = infrav1.StateImageInstalling
+		host.Spec.Status.InstallImage = &infrav1.InstallImage{
+			Method:     infrav1.InstallMethodRobotLinux,
+			RobotLinux: &infrav1.RobotLinuxInstall{Dist: "Ubuntu 24.04 LTS base"},
+		}
+		host.Spec.Status.RobotLinuxInstallStarted = &metav1.Time{Time: time.Now().Add(-robotLinuxInstallTimeout - time.Minute)}
+
+		robotMock := &robotmock.Client{}
+		robotMock.On("DeleteBootLinux", mock.Anything).Return(&models.Linux{}, nil)
+
+		hsm := newTestHostStateMachine(host, newTestService(host, robotMock, nil, nil, nil))
+		hsm.nextState = infrav1.StateImageInstalling
+
+		Expect(hsm.handleImageInstalling(context.Background())).To(BeAssignableToTypeOf(actionFailed{}))
+		Expect(hsm.nextState).To(Equal(infrav1.StatePreparing))
+	})
+})
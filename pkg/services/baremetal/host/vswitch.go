@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+var errVSwitchSubnetNotIPv4 = errors.New("vSwitch subnet is not an IPv4 network")
+
+// vSwitchMTU is the maximum MTU of a vSwitch that is connected to an HCloud network.
+const vSwitchMTU = 1400
+
+// vSwitchNetworkConfig returns the part of the post install script that configures the VLAN interface of the
+// vSwitch. The script is empty if the cluster does not use a vSwitch. If the server has no IP in the vSwitch
+// subnet yet, an action result is returned to wait for it.
+func (s *Service) vSwitchNetworkConfig() (string, actionResult) {
+	hetznerCluster := s.scope.HetznerCluster
+	spec := hetznerCluster.Spec.HCloudNetwork.VSwitch
+	if spec == nil || !hetznerCluster.Spec.HCloudNetwork.Enabled {
+		return "", nil
+	}
+
+	var ip string
+	if status := hetznerCluster.Status.VSwitch; status != nil {
+		for _, server := range status.Servers {
+			if server.ServerID == s.scope.HetznerBareMetalHost.Spec.ServerID {
+				ip = server.IP
+				break
+			}
+		}
+	}
+
+	if ip == "" {
+		conditions.MarkFalse(
+			s.scope.HetznerBareMetalHost,
+			infrav1.ProvisionSucceededCondition,
+			infrav1.VSwitchIPNotAssignedReason,
+			clusterv1.ConditionSeverityInfo,
+			"waiting for server to be connected to vSwitch",
+		)
+		return "", actionContinue{delay: 10 * time.Second}
+	}
+
+	_, subnet, err := net.ParseCIDR(spec.SubnetCIDRBlock)
+	if err != nil {
+		return "", actionError{err: fmt.Errorf("invalid vSwitch subnet %q: %w", spec.SubnetCIDRBlock, err)}
+	}
+	if subnet.IP.To4() == nil {
+		return "", actionError{err: fmt.Errorf("%w: %s", errVSwitchSubnetNotIPv4, spec.SubnetCIDRBlock)}
+	}
+	prefixLen, _ := subnet.Mask.Size()
+
+	// the first IP of the subnet is the gateway to the HCloud network
+	gateway := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(gateway, binary.BigEndian.Uint32(subnet.IP.To4())+1)
+
+	return vSwitchNetworkScript(spec.VLANID, fmt.Sprintf("%s/%d", ip, prefixLen), gateway.String(), hetznerCluster.Spec.HCloudNetwork.CIDRBlock), nil
+}
+
+// vSwitchNetworkScript renders the configuration of the VLAN interface for netplan or ifupdown, depending on
+// what the installed operating system uses. The VLAN is linked to the interface of the public network.
+func vSwitchNetworkScript(vlanID int, address, gateway, networkCIDR string) string {
+	return fmt.Sprintf(`
+# configure vSwitch
+
+if [ -f /etc/netplan/01-netcfg.yaml ]; then
+    VSWITCH_LINK=$(awk '/ethernets:/ {getline; gsub(/[ :]/, ""); print; exit}' /etc/netplan/01-netcfg.yaml)
+    cat << EOF_VSWITCH > /etc/netplan/60-vswitch.yaml
+network:
+  version: 2
+  vlans:
+    vlan%[1]d:
+      id: %[1]d
+      link: ${VSWITCH_LINK}
+      mtu: %[5]d
+      addresses:
+        - %[2]s
+      routes:
+        - to: %[4]s
+          via: %[3]s
+EOF_VSWITCH
+    chmod 600 /etc/netplan/60-vswitch.yaml
+elif [ -f /etc/network/interfaces ]; then
+    VSWITCH_LINK=$(awk '$1 == "iface" && $2 != "lo" {print $2; exit}' /etc/network/interfaces)
+    cat << EOF_VSWITCH >> /etc/network/interfaces
+
+auto ${VSWITCH_LINK}.%[1]d
+iface ${VSWITCH_LINK}.%[1]d inet static
+    address %[2]s
+    mtu %[5]d
+    vlan-raw-device ${VSWITCH_LINK}
+    up ip route add %[4]s via %[3]s dev ${VSWITCH_LINK}.%[1]d
+EOF_VSWITCH
+else
+    echo "ERROR: no supported network configuration found for vSwitch"
+    exit 3
+fi
+# end of configure vSwitch
+`, vlanID, address, gateway, networkCIDR, vSwitchMTU)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package host
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/test/helpers"
+)
+
+var _ = Describe("vSwitchNetworkConfig", func() {
+	var service *Service
+
+	BeforeEach(func() {
+		host := helpers.BareMetalHost("test-host", "default")
+		host.Spec.ServerID = 42
+		service = newTestService(host, nil, nil, nil, nil)
+		service.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch = &infrav1.HCloudNetworkVSwitchSpec{
+			VLANID:          4000,
+			SubnetCIDRBlock: "10.0.1.0/24",
+		}
+	})
+
+	It("returns no script if no vSwitch is configured", func() {
+		service.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch = nil
+
+		script, res := service.vSwitchNetworkConfig()
+		Expect(res).To(BeNil())
+		Expect(script).To(BeEmpty())
+	})
+
+	It("waits until the server has an IP in the vSwitch", func() {
+		script, res := service.vSwitchNetworkConfig()
+		Expect(res).To(BeAssignableToTypeOf(actionContinue{}))
+		Expect(script).To(BeEmpty())
+		Expect(conditions.GetReason(service.scope.HetznerBareMetalHost, infrav1.ProvisionSucceededCondition)).
+			To(Equal(infrav1.VSwitchIPNotAssignedReason))
+	})
+
+	It("renders the VLAN interface with the IP of the server", func() {
+		service.scope.HetznerCluster.Status.VSwitch = &infrav1.VSwitchStatus{
+			ID:      1,
+			Servers: []infrav1.VSwitchServerStatus{{ServerID: 42, IP: "10.0.1.3"}},
+		}
+
+		script, res := service.vSwitchNetworkConfig()
+		Expect(res).To(BeNil())
+		Expect(script).To(ContainSubstring(`    vlan4000:
+      id: 4000
+      link: ${VSWITCH_LINK}
+      mtu: 1400
+      addresses:
+        - 10.0.1.3/24
+      routes:
+        - to: 10.0.0.0/16
+          via: 10.0.1.1
+`))
+		Expect(script).To(ContainSubstring("up ip route add 10.0.0.0/16 via 10.0.1.1 dev ${VSWITCH_LINK}.4000"))
+	})
+})
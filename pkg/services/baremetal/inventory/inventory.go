@@ -0,0 +1,274 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inventory creates HetznerBareMetalHosts for the servers of a Hetzner Robot account.
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/syself/hrobot-go/models"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hostpkg "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/host"
+)
+
+// rateLimitRetryDelay is the time to wait after the rate limit of the Robot API has been exceeded.
+const rateLimitRetryDelay = 5 * time.Minute
+
+// Service defines struct with BareMetalInventoryScope to reconcile HetznerBareMetalInventories.
+type Service struct {
+	scope *scope.BareMetalInventoryScope
+}
+
+// NewService outs a new service with BareMetalInventoryScope.
+func NewService(scope *scope.BareMetalInventoryScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile creates HetznerBareMetalHosts for new servers and flags the hosts whose servers have been cancelled.
+func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err error) {
+	inventory := s.scope.BareMetalInventory
+
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				inventory,
+				infrav1.InventorySyncedCondition,
+				infrav1.InventorySyncFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	servers, err := s.scope.RobotClient.ListBMServers()
+	if err != nil {
+		switch {
+		case models.IsError(err, models.ErrorCodeServerNotFound):
+			// the account has no servers
+			servers = nil
+		case models.IsError(err, models.ErrorCodeRateLimitExceeded):
+			conditions.MarkFalse(
+				inventory,
+				infrav1.InventorySyncedCondition,
+				infrav1.RateLimitExceededReason,
+				clusterv1.ConditionSeverityWarning,
+				"exceeded rate limit with calling robot function ListBMServers",
+			)
+			return reconcile.Result{RequeueAfter: rateLimitRetryDelay}, nil
+		default:
+			return reconcile.Result{}, fmt.Errorf("failed to list servers: %w", err)
+		}
+	}
+
+	var hostList infrav1.HetznerBareMetalHostList
+	if err := s.scope.Client.List(ctx, &hostList, client.InNamespace(inventory.Namespace)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to list HetznerBareMetalHosts: %w", err)
+	}
+
+	selected, err := s.selectServers(servers)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	hosts := hostList.Items
+	for _, server := range selected {
+		if server.Cancelled || slices.ContainsFunc(hosts, func(host infrav1.HetznerBareMetalHost) bool {
+			return host.Spec.ServerID == server.ServerNumber
+		}) {
+			continue
+		}
+
+		host, err := s.createHost(ctx, server)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if host != nil {
+			hosts = append(hosts, *host)
+		}
+	}
+
+	hostSelector, err := s.hostSelector()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	serversByID := make(map[int]models.Server, len(servers))
+	for _, server := range servers {
+		serversByID[server.ServerNumber] = server
+	}
+
+	var numHosts int
+	var cancelledServers []int
+	for i := range hosts {
+		host := &hosts[i]
+		if host.Labels[infrav1.InventoryLabel] != inventory.Name && !hostSelector.Matches(labels.Set(host.Labels)) {
+			continue
+		}
+		numHosts++
+
+		cancelled, err := s.flagCancelledServer(ctx, host, serversByID)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if cancelled {
+			cancelledServers = append(cancelledServers, host.Spec.ServerID)
+		}
+	}
+	slices.Sort(cancelledServers)
+
+	inventory.Status.Hosts = numHosts
+	inventory.Status.CancelledServers = cancelledServers
+	inventory.Status.LastSyncTime = ptr.To(metav1.Now())
+	conditions.MarkTrue(inventory, infrav1.InventorySyncedCondition)
+
+	return reconcile.Result{RequeueAfter: inventory.Spec.SyncInterval.Duration}, nil
+}
+
+// selectServers returns the servers that match the filter of the inventory.
+func (s *Service) selectServers(servers []models.Server) ([]models.Server, error) {
+	filter := s.scope.BareMetalInventory.Spec.Filter
+
+	namePattern, err := regexp.Compile(filter.NamePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name pattern %q: %w", filter.NamePattern, err)
+	}
+
+	selected := make([]models.Server, 0, len(servers))
+	for _, server := range servers {
+		if !namePattern.MatchString(server.Name) {
+			continue
+		}
+		if len(filter.Products) > 0 && !slices.Contains(filter.Products, server.Product) {
+			continue
+		}
+		selected = append(selected, server)
+	}
+	return selected, nil
+}
+
+// hostSelector returns the selector of existing hosts that belong to the inventory.
+func (s *Service) hostSelector() (labels.Selector, error) {
+	if s.scope.BareMetalInventory.Spec.Filter.HostSelector == nil {
+		return labels.Nothing(), nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(s.scope.BareMetalInventory.Spec.Filter.HostSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host selector: %w", err)
+	}
+	return selector, nil
+}
+
+// createHost creates a HetznerBareMetalHost for the server. It returns nil if a host with the same name exists already.
+func (s *Service) createHost(ctx context.Context, server models.Server) (*infrav1.HetznerBareMetalHost, error) {
+	inventory := s.scope.BareMetalInventory
+	template := inventory.Spec.HostTemplate
+
+	hostLabels := maps.Clone(template.Labels)
+	if hostLabels == nil {
+		hostLabels = make(map[string]string, 1)
+	}
+	hostLabels[infrav1.InventoryLabel] = inventory.Name
+
+	host := &infrav1.HetznerBareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        template.NamePrefix + strconv.Itoa(server.ServerNumber),
+			Namespace:   inventory.Namespace,
+			Labels:      hostLabels,
+			Annotations: maps.Clone(template.Annotations),
+		},
+		Spec: infrav1.HetznerBareMetalHostSpec{
+			ServerID:    server.ServerNumber,
+			Description: fmt.Sprintf("%s (%s, %s)", server.Name, server.Product, server.Dc),
+		},
+	}
+	if template.MaintenanceMode {
+		host.Spec.MaintenanceMode = ptr.To(true)
+	}
+	hostpkg.SetRobotServerLabels(host, &server)
+
+	if err := s.scope.Client.Create(ctx, host); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			record.Warnf(inventory, "HostNameTaken",
+				"Cannot create HetznerBareMetalHost %s/%s for server %d: a host with that name exists already",
+				host.Namespace, host.Name, server.ServerNumber)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to create HetznerBareMetalHost for server %d: %w", server.ServerNumber, err)
+	}
+
+	record.Eventf(inventory, "HostCreated", "Created HetznerBareMetalHost %s/%s for server %d", host.Namespace, host.Name, server.ServerNumber)
+	return host, nil
+}
+
+// flagCancelledServer annotates the host if its server has been cancelled or removed in Hetzner Robot. The
+// annotation is removed again if the cancellation has been revoked. It returns true if the server is cancelled.
+func (s *Service) flagCancelledServer(ctx context.Context, host *infrav1.HetznerBareMetalHost, servers map[int]models.Server) (bool, error) {
+	var value string
+	if server, found := servers[host.Spec.ServerID]; !found {
+		value = infrav1.RobotServerRemoved
+	} else if server.Cancelled {
+		value = server.PaidUntil
+		if value == "" {
+			value = "true"
+		}
+	}
+
+	current, flagged := host.Annotations[infrav1.RobotServerCancelledAnnotation]
+	if (value == "" && !flagged) || (value != "" && flagged && current == value) {
+		return value != "", nil
+	}
+
+	patchBase := client.MergeFrom(host.DeepCopy())
+	if value == "" {
+		delete(host.Annotations, infrav1.RobotServerCancelledAnnotation)
+	} else {
+		if host.Annotations == nil {
+			host.Annotations = make(map[string]string, 1)
+		}
+		host.Annotations[infrav1.RobotServerCancelledAnnotation] = value
+	}
+
+	if err := s.scope.Client.Patch(ctx, host, patchBase); err != nil {
+		return false, fmt.Errorf("failed to patch HetznerBareMetalHost %s/%s: %w", host.Namespace, host.Name, err)
+	}
+
+	if value != "" {
+		record.Warnf(host, "RobotServerCancelled", "Server %d has been cancelled in Hetzner Robot (%s)", host.Spec.ServerID, value)
+	}
+	return value != "", nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syself/hrobot-go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakek8sclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
+)
+
+var _ = Describe("Inventory", func() {
+	var (
+		bareMetalInventory *infrav1.HetznerBareMetalInventory
+		robotClient        *robotmock.Client
+		k8sClient          client.Client
+	)
+
+	newService := func(objects ...client.Object) *Service {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(infrav1.AddToScheme(scheme))
+		k8sClient = fakek8sclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+		return NewService(&scope.BareMetalInventoryScope{
+			Client:             k8sClient,
+			RobotClient:        robotClient,
+			BareMetalInventory: bareMetalInventory,
+		})
+	}
+
+	getHost := func(name string) *infrav1.HetznerBareMetalHost {
+		var host infrav1.HetznerBareMetalHost
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, &host)).To(Succeed())
+		return &host
+	}
+
+	BeforeEach(func() {
+		bareMetalInventory = &infrav1.HetznerBareMetalInventory{
+			ObjectMeta: metav1.ObjectMeta{Name: "inventory", Namespace: "default"},
+			Spec: infrav1.HetznerBareMetalInventorySpec{
+				Filter: infrav1.InventoryFilter{
+					NamePattern: "^k8s-",
+					Products:    []string{"AX41-NVMe", "AX52"},
+				},
+				HostTemplate: infrav1.InventoryHostTemplate{
+					NamePrefix:      "bm-",
+					Labels:          map[string]string{"pool": "workers"},
+					Annotations:     map[string]string{"team": "platform"},
+					MaintenanceMode: true,
+				},
+				SyncInterval: metav1.Duration{Duration: 10 * time.Minute},
+			},
+		}
+		robotClient = &robotmock.Client{}
+	})
+
+	It("creates hosts for new servers that match the filter", func() {
+		robotClient.On("ListBMServers").Return([]models.Server{
+			{ServerNumber: 1, Name: "k8s-1", Product: "AX41-NVMe", Dc: "FSN1-DC1"},
+			{ServerNumber: 2, Name: "k8s-2", Product: "EX44"},
+			{ServerNumber: 3, Name: "db-1", Product: "AX41-NVMe"},
+			{ServerNumber: 4, Name: "k8s-4", Product: "AX52", Cancelled: true, PaidUntil: "2026-12-31"},
+			{ServerNumber: 5, Name: "k8s-5", Product: "AX52"},
+		}, nil)
+
+		existing := &infrav1.HetznerBareMetalHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "handwritten", Namespace: "default"},
+			Spec:       infrav1.HetznerBareMetalHostSpec{ServerID: 5},
+		}
+		service := newService(existing)
+
+		res, err := service.Reconcile(context.Background())
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(10 * time.Minute))
+
+		var hostList infrav1.HetznerBareMetalHostList
+		Expect(k8sClient.List(context.Background(), &hostList)).To(Succeed())
+		Expect(hostList.Items).To(HaveLen(2))
+
+		host := getHost("bm-1")
+		Expect(host.Spec.ServerID).To(Equal(1))
+		Expect(host.Spec.Description).To(Equal("k8s-1 (AX41-NVMe, FSN1-DC1)"))
+		Expect(host.Spec.MaintenanceMode).To(HaveValue(BeTrue()))
+		Expect(host.Labels).To(Equal(map[string]string{
+			"pool":                          "workers",
+			infrav1.InventoryLabel:          "inventory",
+			infrav1.HardwareDatacenterLabel: "FSN1-DC1",
+			infrav1.HardwareProductLabel:    "AX41-NVMe",
+		}))
+		Expect(host.Annotations).To(Equal(map[string]string{"team": "platform"}))
+
+		Expect(bareMetalInventory.Status.Hosts).To(Equal(1))
+		Expect(bareMetalInventory.Status.LastSyncTime).ToNot(BeNil())
+		Expect(conditions.IsTrue(bareMetalInventory, infrav1.InventorySyncedCondition)).To(BeTrue())
+	})
+
+	It("flags hosts of the inventory whose servers have been cancelled", func() {
+		robotClient.On("ListBMServers").Return([]models.Server{
+			{ServerNumber: 1, Name: "k8s-1", Product: "AX41-NVMe", Cancelled: true, PaidUntil: "2026-12-31"},
+			{ServerNumber: 3, Name: "k8s-3", Product: "AX41-NVMe"},
+		}, nil)
+
+		newHost := func(name string, serverID int, hostLabels map[string]string, annotations map[string]string) *infrav1.HetznerBareMetalHost {
+			return &infrav1.HetznerBareMetalHost{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: hostLabels, Annotations: annotations},
+				Spec:       infrav1.HetznerBareMetalHostSpec{ServerID: serverID},
+			}
+		}
+		bareMetalInventory.Spec.Filter.HostSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"adopt": "true"}}
+
+		service := newService(
+			newHost("bm-1", 1, map[string]string{infrav1.InventoryLabel: "inventory"}, nil),
+			newHost("bm-2", 2, map[string]string{"adopt": "true"}, nil),
+			newHost("bm-3", 3, map[string]string{infrav1.InventoryLabel: "inventory"}, map[string]string{infrav1.RobotServerCancelledAnnotation: "2026-01-31"}),
+			newHost("other", 4, nil, nil),
+		)
+
+		_, err := service.Reconcile(context.Background())
+		Expect(err).To(Succeed())
+
+		Expect(getHost("bm-1").Annotations).To(HaveKeyWithValue(infrav1.RobotServerCancelledAnnotation, "2026-12-31"))
+		Expect(getHost("bm-2").Annotations).To(HaveKeyWithValue(infrav1.RobotServerCancelledAnnotation, infrav1.RobotServerRemoved))
+		Expect(getHost("bm-3").Annotations).ToNot(HaveKey(infrav1.RobotServerCancelledAnnotation))
+		Expect(getHost("other").Annotations).ToNot(HaveKey(infrav1.RobotServerCancelledAnnotation))
+
+		Expect(bareMetalInventory.Status.Hosts).To(Equal(3))
+		Expect(bareMetalInventory.Status.CancelledServers).To(Equal([]int{1, 2}))
+	})
+
+	It("waits if the rate limit has been exceeded", func() {
+		robotClient.On("ListBMServers").Return(nil, models.Error{Code: models.ErrorCodeRateLimitExceeded, Message: "rate limit exceeded"})
+
+		res, err := newService().Reconcile(context.Background())
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).To(Equal(rateLimitRetryDelay))
+		Expect(conditions.GetReason(bareMetalInventory, infrav1.InventorySyncedCondition)).To(Equal(infrav1.RateLimitExceededReason))
+	})
+})
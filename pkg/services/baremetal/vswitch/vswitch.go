@@ -0,0 +1,308 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vswitch implements the lifecycle of the Hetzner Robot vSwitch that connects bare metal servers with the HCloud network.
+package vswitch
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+
+	"github.com/syself/hrobot-go/models"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
+)
+
+var (
+	errMissingRobotClient = errors.New("missing robot client")
+	errVSwitchCancelled   = errors.New("vSwitch is cancelled")
+	errNoFreeIP           = errors.New("no free IP left in vSwitch subnet")
+)
+
+// Service struct contains cluster scope to reconcile the vSwitch.
+type Service struct {
+	scope *scope.ClusterScope
+}
+
+// NewService creates a new service object.
+func NewService(scope *scope.ClusterScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile makes sure that the vSwitch exists and that all bare metal servers of the cluster are connected to it.
+func (s *Service) Reconcile(ctx context.Context) (err error) {
+	spec := s.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch
+	if spec == nil || !s.scope.HetznerCluster.Spec.HCloudNetwork.Enabled {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				s.scope.HetznerCluster,
+				infrav1.VSwitchReadyCondition,
+				infrav1.VSwitchReconcileFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	if s.scope.RobotClient == nil {
+		return errMissingRobotClient
+	}
+
+	status, vSwitch, err := s.ensureVSwitch()
+	if err != nil {
+		return err
+	}
+	s.scope.HetznerCluster.Status.VSwitch = status
+
+	serverIDs, err := s.bareMetalServerIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list bare metal hosts: %w", err)
+	}
+
+	if err := s.reconcileServers(status, connectedServerIDs(vSwitch), serverIDs); err != nil {
+		return err
+	}
+
+	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.VSwitchReadyCondition)
+	return nil
+}
+
+// Delete removes the servers of the cluster from the vSwitch. Afterwards, the vSwitch is cancelled if it has
+// been created by the controller.
+func (s *Service) Delete(_ context.Context) error {
+	status := s.scope.HetznerCluster.Status.VSwitch
+	if status == nil {
+		// nothing to delete
+		return nil
+	}
+
+	if s.scope.RobotClient == nil {
+		return errMissingRobotClient
+	}
+
+	vSwitch, err := s.scope.RobotClient.GetVSwitch(status.ID)
+	if err != nil {
+		if !models.IsError(err, models.ErrorCodeNotFound) {
+			return fmt.Errorf("failed to get vSwitch %d: %w", status.ID, err)
+		}
+		// the vSwitch is gone already
+		s.scope.HetznerCluster.Status.VSwitch = nil
+		return nil
+	}
+
+	if err := s.reconcileServers(status, connectedServerIDs(vSwitch), nil); err != nil {
+		return err
+	}
+
+	if status.Created && !vSwitch.Cancelled {
+		if err := s.scope.RobotClient.CancelVSwitch(status.ID); err != nil && !models.IsError(err, models.ErrorCodeNotFound) {
+			record.Warnf(s.scope.HetznerCluster, "VSwitchCancelFailed", "Failed to cancel vSwitch %d: %s", status.ID, err.Error())
+			return fmt.Errorf("failed to cancel vSwitch %d: %w", status.ID, err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "VSwitchCancelled", "Cancelled vSwitch %d", status.ID)
+	}
+
+	s.scope.HetznerCluster.Status.VSwitch = nil
+	return nil
+}
+
+// ensureVSwitch returns the status of the vSwitch that is specified by ID or by the name of the HetznerCluster,
+// together with the vSwitch as returned by the Robot API. If it does not exist, the vSwitch gets created.
+func (s *Service) ensureVSwitch() (*infrav1.VSwitchStatus, *robotclient.VSwitch, error) {
+	spec := s.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch
+	status := s.scope.HetznerCluster.Status.VSwitch
+	if status == nil {
+		status = &infrav1.VSwitchStatus{}
+	}
+
+	id := status.ID
+	if spec.ID != nil {
+		id = *spec.ID
+	}
+
+	if id == 0 {
+		vSwitches, err := s.scope.RobotClient.ListVSwitches()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list vSwitches: %w", err)
+		}
+
+		name := s.scope.HetznerCluster.Name
+		for _, vSwitch := range vSwitches {
+			if vSwitch.Name == name && vSwitch.VLANID == spec.VLANID && !vSwitch.Cancelled {
+				id = vSwitch.ID
+				break
+			}
+		}
+
+		if id == 0 {
+			vSwitch, err := s.scope.RobotClient.CreateVSwitch(name, spec.VLANID)
+			if err != nil {
+				record.Warnf(s.scope.HetznerCluster, "VSwitchCreateFailed", "Failed to create vSwitch %s with VLAN ID %d: %s", name, spec.VLANID, err.Error())
+				return nil, nil, fmt.Errorf("failed to create vSwitch: %w", err)
+			}
+			record.Eventf(s.scope.HetznerCluster, "VSwitchCreated", "Created vSwitch %d with VLAN ID %d", vSwitch.ID, spec.VLANID)
+
+			status.ID = vSwitch.ID
+			status.Created = true
+			return status, vSwitch, nil
+		}
+	}
+
+	// the list of vSwitches does not contain the connected servers, so the vSwitch is always fetched by its ID
+	vSwitch, err := s.scope.RobotClient.GetVSwitch(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get vSwitch %d: %w", id, err)
+	}
+	if vSwitch.Cancelled {
+		return nil, nil, fmt.Errorf("%w: %d", errVSwitchCancelled, id)
+	}
+	status.ID = vSwitch.ID
+	return status, vSwitch, nil
+}
+
+// connectedServerIDs returns the IDs of the servers that are connected to the vSwitch according to the Robot API.
+func connectedServerIDs(vSwitch *robotclient.VSwitch) []int {
+	serverIDs := make([]int, 0, len(vSwitch.Servers))
+	for _, server := range vSwitch.Servers {
+		serverIDs = append(serverIDs, server.ServerNumber)
+	}
+	return serverIDs
+}
+
+// bareMetalServerIDs returns the IDs of the bare metal servers that are in use by the cluster.
+func (s *Service) bareMetalServerIDs(ctx context.Context) ([]int, error) {
+	var hostList infrav1.HetznerBareMetalHostList
+	if err := s.scope.Client.List(ctx, &hostList, client.InNamespace(s.scope.Namespace())); err != nil {
+		return nil, err
+	}
+
+	serverIDs := make([]int, 0, len(hostList.Items))
+	for _, host := range hostList.Items {
+		if host.Spec.ConsumerRef == nil || host.Spec.Status.HetznerClusterRef != s.scope.HetznerCluster.Name {
+			continue
+		}
+		serverIDs = append(serverIDs, host.Spec.ServerID)
+	}
+	slices.Sort(serverIDs)
+	return serverIDs, nil
+}
+
+// reconcileServers connects the given servers with the vSwitch and disconnects all others. Every server gets a
+// private IP of the vSwitch subnet. Which servers are connected is taken from the Robot API, as the status might
+// be outdated. Servers that are not known to the status are only disconnected from vSwitches that have been
+// created by the controller, as other servers might be connected to an existing vSwitch on purpose.
+func (s *Service) reconcileServers(status *infrav1.VSwitchStatus, connected, serverIDs []int) error {
+	var removed []int
+	for _, id := range connected {
+		if slices.Contains(serverIDs, id) {
+			continue
+		}
+		if status.Created || slices.ContainsFunc(status.Servers, func(server infrav1.VSwitchServerStatus) bool { return server.ServerID == id }) {
+			removed = append(removed, id)
+		}
+	}
+
+	if len(removed) > 0 {
+		if err := s.scope.RobotClient.RemoveServersFromVSwitch(status.ID, removed); err != nil {
+			return fmt.Errorf("failed to remove servers %v from vSwitch %d: %w", removed, status.ID, err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "VSwitchServersRemoved", "Removed servers %v from vSwitch %d", removed, status.ID)
+	}
+
+	// keep the IPs of the servers that stay connected
+	status.Servers = slices.DeleteFunc(status.Servers, func(server infrav1.VSwitchServerStatus) bool {
+		return !slices.Contains(serverIDs, server.ServerID)
+	})
+
+	var newServers []int
+	for _, id := range serverIDs {
+		if slices.ContainsFunc(status.Servers, func(server infrav1.VSwitchServerStatus) bool { return server.ServerID == id }) {
+			continue
+		}
+		ip, err := s.nextFreeIP(status.Servers)
+		if err != nil {
+			return err
+		}
+		status.Servers = append(status.Servers, infrav1.VSwitchServerStatus{ServerID: id, IP: ip})
+		newServers = append(newServers, id)
+	}
+
+	var added []int
+	for _, id := range serverIDs {
+		if !slices.Contains(connected, id) {
+			added = append(added, id)
+		}
+	}
+
+	if len(added) > 0 {
+		if err := s.scope.RobotClient.AddServersToVSwitch(status.ID, added); err != nil {
+			// forget the IPs that have just been assigned to servers which are not connected
+			status.Servers = slices.DeleteFunc(status.Servers, func(server infrav1.VSwitchServerStatus) bool {
+				return slices.Contains(newServers, server.ServerID) && slices.Contains(added, server.ServerID)
+			})
+			return fmt.Errorf("failed to add servers %v to vSwitch %d: %w", added, status.ID, err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "VSwitchServersAdded", "Added servers %v to vSwitch %d", added, status.ID)
+	}
+
+	return nil
+}
+
+// nextFreeIP returns the lowest IP of the vSwitch subnet that is not in use. The first IP of the subnet is
+// skipped as it is the gateway to the HCloud network.
+func (s *Service) nextFreeIP(servers []infrav1.VSwitchServerStatus) (string, error) {
+	cidr := s.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch.SubnetCIDRBlock
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", fmt.Errorf("invalid network %q: %w", cidr, err)
+	}
+
+	base := subnet.IP.To4()
+	if base == nil {
+		return "", fmt.Errorf("invalid network %q: only IPv4 is supported", cidr)
+	}
+	ones, bits := subnet.Mask.Size()
+	size := uint32(1) << uint32(bits-ones) //nolint:gosec // the mask size of an IPv4 network is at most 32.
+
+	start := binary.BigEndian.Uint32(base)
+	// skip the network address and the gateway, and leave out the broadcast address
+	for offset := uint32(2); offset < size-1; offset++ {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, start+offset)
+		if !slices.ContainsFunc(servers, func(server infrav1.VSwitchServerStatus) bool { return server.IP == ip.String() }) {
+			return ip.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", errNoFreeIP, cidr)
+}
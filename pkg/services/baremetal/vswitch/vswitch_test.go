@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vswitch
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakek8sclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
+)
+
+func newHost(name string, serverID int, clusterName string, consumed bool) *infrav1.HetznerBareMetalHost {
+	host := &infrav1.HetznerBareMetalHost{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: infrav1.HetznerBareMetalHostSpec{
+			ServerID: serverID,
+			Status:   infrav1.ControllerGeneratedStatus{HetznerClusterRef: clusterName},
+		},
+	}
+	if consumed {
+		host.Spec.ConsumerRef = &corev1.ObjectReference{Name: name}
+	}
+	return host
+}
+
+func newService(hetznerCluster *infrav1.HetznerCluster, robotClient *robotmock.Client, objects ...client.Object) *Service {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(infrav1.AddToScheme(scheme))
+
+	return NewService(&scope.ClusterScope{
+		Client:         fakek8sclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		HetznerCluster: hetznerCluster,
+		RobotClient:    robotClient,
+	})
+}
+
+var _ = Describe("vSwitch", func() {
+	var (
+		hetznerCluster *infrav1.HetznerCluster
+		robotClient    *robotmock.Client
+	)
+
+	BeforeEach(func() {
+		hetznerCluster = &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"},
+			Spec: infrav1.HetznerClusterSpec{
+				HCloudNetwork: infrav1.HCloudNetworkSpec{
+					Enabled:         true,
+					CIDRBlock:       "10.0.0.0/16",
+					SubnetCIDRBlock: "10.0.0.0/24",
+					VSwitch: &infrav1.HCloudNetworkVSwitchSpec{
+						VLANID:          4000,
+						SubnetCIDRBlock: "10.0.1.0/24",
+					},
+				},
+			},
+		}
+		robotClient = &robotmock.Client{}
+	})
+
+	It("creates the vSwitch and connects the servers of the cluster", func() {
+		robotClient.On("ListVSwitches").Return([]robotclient.VSwitch{
+			{ID: 1, Name: "hetzner-cluster", VLANID: 4000, Cancelled: true},
+			{ID: 2, Name: "other-cluster", VLANID: 4000},
+		}, nil)
+		robotClient.On("CreateVSwitch", "hetzner-cluster", 4000).Return(&robotclient.VSwitch{ID: 3, Name: "hetzner-cluster", VLANID: 4000}, nil)
+		robotClient.On("AddServersToVSwitch", 3, []int{1, 2}).Return(nil)
+
+		service := newService(hetznerCluster, robotClient,
+			newHost("host-1", 1, "hetzner-cluster", true),
+			newHost("host-2", 2, "hetzner-cluster", true),
+			newHost("host-3", 3, "hetzner-cluster", false),
+			newHost("host-4", 4, "other-cluster", true),
+		)
+
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.VSwitchReadyCondition)).To(BeTrue())
+		Expect(hetznerCluster.Status.VSwitch).To(Equal(&infrav1.VSwitchStatus{
+			ID:      3,
+			Created: true,
+			Servers: []infrav1.VSwitchServerStatus{
+				{ServerID: 1, IP: "10.0.1.2"},
+				{ServerID: 2, IP: "10.0.1.3"},
+			},
+		}))
+		robotClient.AssertExpectations(GinkgoT())
+	})
+
+	It("adopts an existing vSwitch and reuses free IPs", func() {
+		hetznerCluster.Spec.HCloudNetwork.VSwitch.ID = ptr.To(5)
+		hetznerCluster.Status.VSwitch = &infrav1.VSwitchStatus{
+			ID: 5,
+			Servers: []infrav1.VSwitchServerStatus{
+				{ServerID: 1, IP: "10.0.1.2"},
+				{ServerID: 2, IP: "10.0.1.3"},
+			},
+		}
+		robotClient.On("GetVSwitch", 5).Return(&robotclient.VSwitch{ID: 5, VLANID: 4000, Servers: []robotclient.VSwitchServer{
+			{ServerNumber: 1}, {ServerNumber: 2},
+		}}, nil)
+		robotClient.On("RemoveServersFromVSwitch", 5, []int{1}).Return(nil)
+		robotClient.On("AddServersToVSwitch", 5, []int{3}).Return(nil)
+
+		service := newService(hetznerCluster, robotClient,
+			newHost("host-2", 2, "hetzner-cluster", true),
+			newHost("host-3", 3, "hetzner-cluster", true),
+		)
+
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.VSwitch.Created).To(BeFalse())
+		Expect(hetznerCluster.Status.VSwitch.Servers).To(Equal([]infrav1.VSwitchServerStatus{
+			{ServerID: 2, IP: "10.0.1.3"},
+			{ServerID: 3, IP: "10.0.1.2"},
+		}))
+		robotClient.AssertExpectations(GinkgoT())
+	})
+
+	It("takes the connected servers from the Robot API", func() {
+		// the status does not know about server 1, e.g. because an update of the status got lost
+		hetznerCluster.Spec.HCloudNetwork.VSwitch.ID = ptr.To(5)
+		robotClient.On("GetVSwitch", 5).Return(&robotclient.VSwitch{ID: 5, VLANID: 4000, Servers: []robotclient.VSwitchServer{
+			{ServerNumber: 1}, {ServerNumber: 4},
+		}}, nil)
+		robotClient.On("AddServersToVSwitch", 5, []int{2}).Return(nil)
+
+		service := newService(hetznerCluster, robotClient,
+			newHost("host-1", 1, "hetzner-cluster", true),
+			newHost("host-2", 2, "hetzner-cluster", true),
+		)
+
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.VSwitch.Servers).To(Equal([]infrav1.VSwitchServerStatus{
+			{ServerID: 1, IP: "10.0.1.2"},
+			{ServerID: 2, IP: "10.0.1.3"},
+		}))
+		robotClient.AssertExpectations(GinkgoT())
+		// server 4 has been connected to the existing vSwitch by someone else
+		robotClient.AssertNotCalled(GinkgoT(), "RemoveServersFromVSwitch", mock.Anything, mock.Anything)
+	})
+
+	It("sets a condition if the vSwitch is cancelled", func() {
+		hetznerCluster.Spec.HCloudNetwork.VSwitch.ID = ptr.To(5)
+		robotClient.On("GetVSwitch", 5).Return(&robotclient.VSwitch{ID: 5, Cancelled: true}, nil)
+
+		service := newService(hetznerCluster, robotClient)
+
+		Expect(service.Reconcile(context.Background())).To(MatchError(errVSwitchCancelled))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.VSwitchReadyCondition)).To(Equal(infrav1.VSwitchReconcileFailedReason))
+	})
+
+	It("cancels only vSwitches that have been created by the controller", func() {
+		hetznerCluster.Status.VSwitch = &infrav1.VSwitchStatus{ID: 3, Created: true, Servers: []infrav1.VSwitchServerStatus{{ServerID: 1, IP: "10.0.1.2"}}}
+		robotClient.On("GetVSwitch", 3).Return(&robotclient.VSwitch{ID: 3, Servers: []robotclient.VSwitchServer{{ServerNumber: 1}}}, nil)
+		robotClient.On("RemoveServersFromVSwitch", 3, []int{1}).Return(nil)
+		robotClient.On("CancelVSwitch", 3).Return(nil)
+
+		Expect(newService(hetznerCluster, robotClient).Delete(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.VSwitch).To(BeNil())
+
+		// the servers are removed before the vSwitch is cancelled
+		Expect(robotClient.Calls[1].Method).To(Equal("RemoveServersFromVSwitch"))
+		Expect(robotClient.Calls[2].Method).To(Equal("CancelVSwitch"))
+
+		hetznerCluster.Status.VSwitch = &infrav1.VSwitchStatus{ID: 5, Servers: []infrav1.VSwitchServerStatus{{ServerID: 1, IP: "10.0.1.2"}}}
+		robotClient.On("GetVSwitch", 5).Return(&robotclient.VSwitch{ID: 5, Servers: []robotclient.VSwitchServer{{ServerNumber: 1}}}, nil)
+		robotClient.On("RemoveServersFromVSwitch", 5, []int{1}).Return(nil)
+
+		Expect(newService(hetznerCluster, robotClient).Delete(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.VSwitch).To(BeNil())
+		robotClient.AssertExpectations(GinkgoT())
+		robotClient.AssertNotCalled(GinkgoT(), "CancelVSwitch", 5)
+	})
+})
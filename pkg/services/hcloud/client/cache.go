@@ -0,0 +1,327 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hcloudclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// NewCachingFactory returns a factory for clients that cache the results of read calls for the given ttl.
+// All clients of one HCloud token share the same cache.
+func NewCachingFactory(factory Factory, ttl time.Duration) Factory {
+	return &cachingFactory{
+		factory: factory,
+		ttl:     ttl,
+		clients: make(map[string]*cachingClient),
+	}
+}
+
+type cachingFactory struct {
+	factory Factory
+	ttl     time.Duration
+
+	mutex   sync.Mutex
+	clients map[string]*cachingClient
+}
+
+var _ = Factory(&cachingFactory{})
+
+// NewClient returns the caching client of the HCloud token.
+func (f *cachingFactory) NewClient(hcloudToken string) Client {
+	hash := sha256.Sum256([]byte(hcloudToken))
+	key := hex.EncodeToString(hash[:])
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	c, found := f.clients[key]
+	if !found {
+		c = newCachingClient(f.factory.NewClient(hcloudToken), f.ttl, time.Now)
+		f.clients[key] = c
+	}
+	return c
+}
+
+// cachingClient caches the results of read calls of mostly static data and of servers. The cached servers
+// are invalidated by all calls of this client that change servers. Changes made by others become visible
+// after the ttl.
+type cachingClient struct {
+	Client
+
+	serverTypes      *ttlCache[[]*hcloud.ServerType]
+	serverTypeByName *ttlCache[*hcloud.ServerType]
+	sshKeys          *ttlCache[[]*hcloud.SSHKey]
+	images           *ttlCache[[]*hcloud.Image]
+	servers          *ttlCache[[]*hcloud.Server]
+	serverByID       *ttlCache[*hcloud.Server]
+}
+
+var _ Client = &cachingClient{}
+
+func newCachingClient(client Client, ttl time.Duration, now func() time.Time) *cachingClient {
+	return &cachingClient{
+		Client:           client,
+		serverTypes:      newTTLCache[[]*hcloud.ServerType](ttl, now),
+		serverTypeByName: newTTLCache[*hcloud.ServerType](ttl, now),
+		sshKeys:          newTTLCache[[]*hcloud.SSHKey](ttl, now),
+		images:           newTTLCache[[]*hcloud.Image](ttl, now),
+		servers:          newTTLCache[[]*hcloud.Server](ttl, now),
+		serverByID:       newTTLCache[*hcloud.Server](ttl, now),
+	}
+}
+
+// Reset implements the Reset method of the HCloudClient interface.
+func (c *cachingClient) Reset() {
+	c.serverTypes.invalidate()
+	c.serverTypeByName.invalidate()
+	c.sshKeys.invalidate()
+	c.images.invalidate()
+	c.invalidateServers()
+	c.Client.Reset()
+}
+
+func (c *cachingClient) invalidateServers() {
+	c.servers.invalidate()
+	c.serverByID.invalidate()
+}
+
+func (c *cachingClient) ListServerTypes(ctx context.Context) ([]*hcloud.ServerType, error) {
+	serverTypes, err := c.serverTypes.get("", func() ([]*hcloud.ServerType, error) {
+		return c.Client.ListServerTypes(ctx)
+	})
+	return copySlice(serverTypes), err
+}
+
+func (c *cachingClient) GetServerType(ctx context.Context, name string) (*hcloud.ServerType, error) {
+	return c.serverTypeByName.get(name, func() (*hcloud.ServerType, error) {
+		return c.Client.GetServerType(ctx, name)
+	})
+}
+
+func (c *cachingClient) ListSSHKeys(ctx context.Context, opts hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error) {
+	sshKeys, err := c.sshKeys.get(sshKeyListOptsKey(opts), func() ([]*hcloud.SSHKey, error) {
+		return c.Client.ListSSHKeys(ctx, opts)
+	})
+	return copySlice(sshKeys), err
+}
+
+func (c *cachingClient) ListImages(ctx context.Context, opts hcloud.ImageListOpts) ([]*hcloud.Image, error) {
+	images, err := c.images.get(imageListOptsKey(opts), func() ([]*hcloud.Image, error) {
+		return c.Client.ListImages(ctx, opts)
+	})
+	return copySlice(images), err
+}
+
+func (c *cachingClient) ListServers(ctx context.Context, opts hcloud.ServerListOpts) ([]*hcloud.Server, error) {
+	servers, err := c.servers.get(serverListOptsKey(opts), func() ([]*hcloud.Server, error) {
+		return c.Client.ListServers(ctx, opts)
+	})
+	return copySlice(servers), err
+}
+
+func (c *cachingClient) GetServer(ctx context.Context, id int64) (*hcloud.Server, error) {
+	return c.serverByID.get(fmt.Sprint(id), func() (*hcloud.Server, error) {
+		return c.Client.GetServer(ctx, id)
+	})
+}
+
+func (c *cachingClient) CreateServer(ctx context.Context, opts hcloud.ServerCreateOpts) (*hcloud.Server, error) {
+	defer c.invalidateServers()
+	return c.Client.CreateServer(ctx, opts)
+}
+
+func (c *cachingClient) DeleteServer(ctx context.Context, server *hcloud.Server) error {
+	defer c.invalidateServers()
+	return c.Client.DeleteServer(ctx, server)
+}
+
+func (c *cachingClient) AttachServerToNetwork(ctx context.Context, server *hcloud.Server, opts hcloud.ServerAttachToNetworkOpts) error {
+	defer c.invalidateServers()
+	return c.Client.AttachServerToNetwork(ctx, server, opts)
+}
+
+func (c *cachingClient) PowerOnServer(ctx context.Context, server *hcloud.Server) error {
+	defer c.invalidateServers()
+	return c.Client.PowerOnServer(ctx, server)
+}
+
+func (c *cachingClient) ShutdownServer(ctx context.Context, server *hcloud.Server) error {
+	defer c.invalidateServers()
+	return c.Client.ShutdownServer(ctx, server)
+}
+
+func (c *cachingClient) RebootServer(ctx context.Context, server *hcloud.Server) error {
+	defer c.invalidateServers()
+	return c.Client.RebootServer(ctx, server)
+}
+
+func (c *cachingClient) ChangeServerType(ctx context.Context, server *hcloud.Server, opts hcloud.ServerChangeTypeOpts) error {
+	defer c.invalidateServers()
+	return c.Client.ChangeServerType(ctx, server, opts)
+}
+
+func (c *cachingClient) AddServerToPlacementGroup(ctx context.Context, server *hcloud.Server, pg *hcloud.PlacementGroup) error {
+	defer c.invalidateServers()
+	return c.Client.AddServerToPlacementGroup(ctx, server, pg)
+}
+
+func (c *cachingClient) AddTargetServerToLoadBalancer(ctx context.Context, opts hcloud.LoadBalancerAddServerTargetOpts, lb *hcloud.LoadBalancer) error {
+	defer c.invalidateServers()
+	return c.Client.AddTargetServerToLoadBalancer(ctx, opts, lb)
+}
+
+func (c *cachingClient) DeleteTargetServerOfLoadBalancer(ctx context.Context, lb *hcloud.LoadBalancer, server *hcloud.Server) error {
+	defer c.invalidateServers()
+	return c.Client.DeleteTargetServerOfLoadBalancer(ctx, lb, server)
+}
+
+func (c *cachingClient) AttachVolumeToServer(ctx context.Context, volume *hcloud.Volume, opts hcloud.VolumeAttachOpts) error {
+	defer c.invalidateServers()
+	return c.Client.AttachVolumeToServer(ctx, volume, opts)
+}
+
+func (c *cachingClient) DetachVolume(ctx context.Context, volume *hcloud.Volume) error {
+	defer c.invalidateServers()
+	return c.Client.DetachVolume(ctx, volume)
+}
+
+func (c *cachingClient) ApplyFirewallToResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	defer c.invalidateServers()
+	return c.Client.ApplyFirewallToResources(ctx, firewall, resources)
+}
+
+func (c *cachingClient) RemoveFirewallFromResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	defer c.invalidateServers()
+	return c.Client.RemoveFirewallFromResources(ctx, firewall, resources)
+}
+
+func (c *cachingClient) DeletePrimaryIP(ctx context.Context, primaryIP *hcloud.PrimaryIP) error {
+	defer c.invalidateServers()
+	return c.Client.DeletePrimaryIP(ctx, primaryIP)
+}
+
+func (c *cachingClient) AssignFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP, server *hcloud.Server) error {
+	defer c.invalidateServers()
+	return c.Client.AssignFloatingIP(ctx, floatingIP, server)
+}
+
+func (c *cachingClient) UnassignFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP) error {
+	defer c.invalidateServers()
+	return c.Client.UnassignFloatingIP(ctx, floatingIP)
+}
+
+func (c *cachingClient) DeleteFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP) error {
+	defer c.invalidateServers()
+	return c.Client.DeleteFloatingIP(ctx, floatingIP)
+}
+
+// copySlice returns a copy of a cached slice, so that callers cannot change the cache.
+// listOptsKey returns the cache key of list options. The keys are built from the values of the options, and
+// pointers like ImageListOpts.BoundTo are dereferenced, so that equal options share the same entry.
+func listOptsKey(opts hcloud.ListOpts) string {
+	return fmt.Sprintf("page=%d,perPage=%d,labelSelector=%q", opts.Page, opts.PerPage, opts.LabelSelector)
+}
+
+func sshKeyListOptsKey(opts hcloud.SSHKeyListOpts) string {
+	return fmt.Sprintf("%s,name=%q,fingerprint=%q,sort=%q",
+		listOptsKey(opts.ListOpts), opts.Name, opts.Fingerprint, opts.Sort)
+}
+
+func imageListOptsKey(opts hcloud.ImageListOpts) string {
+	var boundTo int64
+	if opts.BoundTo != nil {
+		boundTo = opts.BoundTo.ID
+	}
+	return fmt.Sprintf("%s,type=%q,boundTo=%d,name=%q,sort=%q,status=%q,includeDeprecated=%t,architecture=%q",
+		listOptsKey(opts.ListOpts), opts.Type, boundTo, opts.Name, opts.Sort, opts.Status, opts.IncludeDeprecated,
+		opts.Architecture)
+}
+
+func serverListOptsKey(opts hcloud.ServerListOpts) string {
+	return fmt.Sprintf("%s,name=%q,status=%q,sort=%q", listOptsKey(opts.ListOpts), opts.Name, opts.Status, opts.Sort)
+}
+
+func copySlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// ttlCache caches values by key for a fixed duration. Errors are not cached.
+type ttlCache[T any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]ttlCacheEntry[T]
+	// generation is increased on every invalidation, so that values loaded before are not stored.
+	generation int
+}
+
+type ttlCacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+func newTTLCache[T any](ttl time.Duration, now func() time.Time) *ttlCache[T] {
+	return &ttlCache[T]{
+		ttl:     ttl,
+		now:     now,
+		entries: make(map[string]ttlCacheEntry[T]),
+	}
+}
+
+// get returns the cached value of the key or calls load if there is no valid entry.
+func (c *ttlCache[T]) get(key string, load func() (T, error)) (T, error) {
+	c.mutex.Lock()
+	entry, found := c.entries[key]
+	generation := c.generation
+	c.mutex.Unlock()
+
+	if found && c.now().Before(entry.expires) {
+		return entry.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mutex.Lock()
+	if generation == c.generation {
+		c.entries[key] = ttlCacheEntry[T]{value: value, expires: c.now().Add(c.ttl)}
+	}
+	c.mutex.Unlock()
+
+	return value, nil
+}
+
+func (c *ttlCache[T]) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = make(map[string]ttlCacheEntry[T])
+	c.generation++
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hcloudclient
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// countingClient counts the calls of the methods used in the tests. All other methods are not implemented.
+type countingClient struct {
+	Client
+
+	listServerTypesCalls int
+	listServersCalls     int
+	listImagesCalls      int
+	err                  error
+}
+
+func (c *countingClient) ListImages(context.Context, hcloud.ImageListOpts) ([]*hcloud.Image, error) {
+	c.listImagesCalls++
+	return []*hcloud.Image{{Name: "ubuntu-24.04"}}, c.err
+}
+
+func (c *countingClient) ListServerTypes(context.Context) ([]*hcloud.ServerType, error) {
+	c.listServerTypesCalls++
+	return []*hcloud.ServerType{{Name: "cpx31"}}, c.err
+}
+
+func (c *countingClient) ListServers(_ context.Context, opts hcloud.ServerListOpts) ([]*hcloud.Server, error) {
+	c.listServersCalls++
+	return []*hcloud.Server{{Name: opts.Name}}, c.err
+}
+
+func (c *countingClient) CreateServer(_ context.Context, opts hcloud.ServerCreateOpts) (*hcloud.Server, error) {
+	return &hcloud.Server{Name: opts.Name}, nil
+}
+
+var _ = Describe("cachingClient", func() {
+	var (
+		now      time.Time
+		inner    *countingClient
+		client   *cachingClient
+		ctx      = context.Background()
+		cacheTTL = time.Minute
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		inner = &countingClient{}
+		client = newCachingClient(inner, cacheTTL, func() time.Time { return now })
+	})
+
+	It("caches results until the ttl is reached", func() {
+		for i := 0; i < 3; i++ {
+			serverTypes, err := client.ListServerTypes(ctx)
+			Expect(err).To(Succeed())
+			Expect(serverTypes).To(HaveLen(1))
+		}
+		Expect(inner.listServerTypesCalls).To(Equal(1))
+
+		now = now.Add(cacheTTL)
+		_, err := client.ListServerTypes(ctx)
+		Expect(err).To(Succeed())
+		Expect(inner.listServerTypesCalls).To(Equal(2))
+	})
+
+	It("caches results per list options", func() {
+		_, err := client.ListServers(ctx, hcloud.ServerListOpts{Name: "a"})
+		Expect(err).To(Succeed())
+		servers, err := client.ListServers(ctx, hcloud.ServerListOpts{Name: "b"})
+		Expect(err).To(Succeed())
+		Expect(servers[0].Name).To(Equal("b"))
+		Expect(inner.listServersCalls).To(Equal(2))
+	})
+
+	It("caches results of equal list options with different pointers", func() {
+		_, err := client.ListImages(ctx, hcloud.ImageListOpts{BoundTo: &hcloud.Server{ID: 1}})
+		Expect(err).To(Succeed())
+		_, err = client.ListImages(ctx, hcloud.ImageListOpts{BoundTo: &hcloud.Server{ID: 1}})
+		Expect(err).To(Succeed())
+		Expect(inner.listImagesCalls).To(Equal(1))
+
+		_, err = client.ListImages(ctx, hcloud.ImageListOpts{BoundTo: &hcloud.Server{ID: 2}})
+		Expect(err).To(Succeed())
+		Expect(inner.listImagesCalls).To(Equal(2))
+	})
+
+	It("invalidates servers on changes", func() {
+		_, err := client.ListServers(ctx, hcloud.ServerListOpts{})
+		Expect(err).To(Succeed())
+
+		_, err = client.CreateServer(ctx, hcloud.ServerCreateOpts{Name: "new"})
+		Expect(err).To(Succeed())
+
+		_, err = client.ListServers(ctx, hcloud.ServerListOpts{})
+		Expect(err).To(Succeed())
+		Expect(inner.listServersCalls).To(Equal(2))
+	})
+
+	It("does not cache errors", func() {
+		inner.err = errors.New("test error")
+		_, err := client.ListServerTypes(ctx)
+		Expect(err).ToNot(Succeed())
+
+		inner.err = nil
+		_, err = client.ListServerTypes(ctx)
+		Expect(err).To(Succeed())
+		Expect(inner.listServerTypesCalls).To(Equal(2))
+	})
+
+	It("does not let callers change the cache", func() {
+		serverTypes, err := client.ListServerTypes(ctx)
+		Expect(err).To(Succeed())
+		serverTypes[0] = nil
+
+		serverTypes, err = client.ListServerTypes(ctx)
+		Expect(err).To(Succeed())
+		Expect(serverTypes[0]).ToNot(BeNil())
+	})
+})
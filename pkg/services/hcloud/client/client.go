@@ -40,7 +40,8 @@ var ErrUnauthorized = fmt.Errorf("unauthorized")
 
 // Client collects all methods used by the controller in the hcloud cloud API.
 type Client interface {
-	// Reset resets the local cache. Only implemented in the fake client.
+	// Reset resets the local cache. It is implemented by the fake client and by the caching client, which
+	// drops its cached results. The real client does nothing.
 	Reset()
 
 	CreateLoadBalancer(context.Context, hcloud.LoadBalancerCreateOpts) (*hcloud.LoadBalancer, error)
@@ -67,14 +68,35 @@ type Client interface {
 	PowerOnServer(context.Context, *hcloud.Server) error
 	ShutdownServer(context.Context, *hcloud.Server) error
 	RebootServer(context.Context, *hcloud.Server) error
+	ChangeServerType(context.Context, *hcloud.Server, hcloud.ServerChangeTypeOpts) error
 	CreateNetwork(context.Context, hcloud.NetworkCreateOpts) (*hcloud.Network, error)
 	ListNetworks(context.Context, hcloud.NetworkListOpts) ([]*hcloud.Network, error)
 	DeleteNetwork(context.Context, *hcloud.Network) error
+	AddSubnetToNetwork(context.Context, *hcloud.Network, hcloud.NetworkAddSubnetOpts) error
 	ListSSHKeys(context.Context, hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error)
 	CreatePlacementGroup(context.Context, hcloud.PlacementGroupCreateOpts) (*hcloud.PlacementGroup, error)
 	DeletePlacementGroup(context.Context, int64) error
 	ListPlacementGroups(context.Context, hcloud.PlacementGroupListOpts) ([]*hcloud.PlacementGroup, error)
 	AddServerToPlacementGroup(context.Context, *hcloud.Server, *hcloud.PlacementGroup) error
+	CreateVolume(context.Context, hcloud.VolumeCreateOpts) (*hcloud.Volume, error)
+	ListVolumes(context.Context, hcloud.VolumeListOpts) ([]*hcloud.Volume, error)
+	AttachVolumeToServer(context.Context, *hcloud.Volume, hcloud.VolumeAttachOpts) error
+	DetachVolume(context.Context, *hcloud.Volume) error
+	DeleteVolume(context.Context, *hcloud.Volume) error
+	CreateFirewall(context.Context, hcloud.FirewallCreateOpts) (*hcloud.Firewall, error)
+	ListFirewalls(context.Context, hcloud.FirewallListOpts) ([]*hcloud.Firewall, error)
+	DeleteFirewall(context.Context, *hcloud.Firewall) error
+	SetFirewallRules(context.Context, *hcloud.Firewall, []hcloud.FirewallRule) error
+	ApplyFirewallToResources(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error
+	RemoveFirewallFromResources(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error
+	ListPrimaryIPs(context.Context, hcloud.PrimaryIPListOpts) ([]*hcloud.PrimaryIP, error)
+	UpdatePrimaryIP(context.Context, *hcloud.PrimaryIP, hcloud.PrimaryIPUpdateOpts) error
+	DeletePrimaryIP(context.Context, *hcloud.PrimaryIP) error
+	CreateFloatingIP(context.Context, hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error)
+	ListFloatingIPs(context.Context, hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error)
+	AssignFloatingIP(context.Context, *hcloud.FloatingIP, *hcloud.Server) error
+	UnassignFloatingIP(context.Context, *hcloud.FloatingIP) error
+	DeleteFloatingIP(context.Context, *hcloud.FloatingIP) error
 }
 
 // Factory is the interface for creating new Client objects.
@@ -111,7 +133,12 @@ var DebugAPICalls bool
 
 // NewClient creates new HCloud clients.
 func (f *factory) NewClient(hcloudToken string) Client {
-	httpClient := &http.Client{}
+	httpClient := &http.Client{
+		Transport: &rateLimitTransport{
+			roundTripper: http.DefaultTransport,
+			budget:       requestBudgetFor(hcloudToken),
+		},
+	}
 
 	hcloudClient := realClient{client: hcloud.NewClient(
 		hcloud.WithToken(hcloudToken),
@@ -282,6 +309,11 @@ func (c *realClient) PowerOnServer(ctx context.Context, server *hcloud.Server) e
 	return err
 }
 
+func (c *realClient) ChangeServerType(ctx context.Context, server *hcloud.Server, opts hcloud.ServerChangeTypeOpts) error {
+	_, _, err := c.client.Server.ChangeType(ctx, server, opts)
+	return err
+}
+
 func (c *realClient) DeleteServer(ctx context.Context, server *hcloud.Server) error {
 	_, _, err := c.client.Server.DeleteWithResult(ctx, server)
 	return err
@@ -305,6 +337,11 @@ func (c *realClient) DeleteNetwork(ctx context.Context, network *hcloud.Network)
 	return err
 }
 
+func (c *realClient) AddSubnetToNetwork(ctx context.Context, network *hcloud.Network, opts hcloud.NetworkAddSubnetOpts) error {
+	_, _, err := c.client.Network.AddSubnet(ctx, network, opts)
+	return err
+}
+
 func (c *realClient) ListSSHKeys(ctx context.Context, opts hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error) {
 	res, _, err := c.client.SSHKey.List(ctx, opts)
 	return res, err
@@ -328,3 +365,110 @@ func (c *realClient) AddServerToPlacementGroup(ctx context.Context, server *hclo
 	_, _, err := c.client.Server.AddToPlacementGroup(ctx, server, pg)
 	return err
 }
+
+func (c *realClient) CreateVolume(ctx context.Context, opts hcloud.VolumeCreateOpts) (*hcloud.Volume, error) {
+	res, _, err := c.client.Volume.Create(ctx, opts)
+	return res.Volume, err
+}
+
+func (c *realClient) ListVolumes(ctx context.Context, opts hcloud.VolumeListOpts) ([]*hcloud.Volume, error) {
+	resp, err := c.client.Volume.AllWithOpts(ctx, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return resp, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return resp, err
+}
+
+func (c *realClient) AttachVolumeToServer(ctx context.Context, volume *hcloud.Volume, opts hcloud.VolumeAttachOpts) error {
+	_, _, err := c.client.Volume.AttachWithOpts(ctx, volume, opts)
+	return err
+}
+
+func (c *realClient) DetachVolume(ctx context.Context, volume *hcloud.Volume) error {
+	_, _, err := c.client.Volume.Detach(ctx, volume)
+	return err
+}
+
+func (c *realClient) DeleteVolume(ctx context.Context, volume *hcloud.Volume) error {
+	_, err := c.client.Volume.Delete(ctx, volume)
+	return err
+}
+
+func (c *realClient) CreateFirewall(ctx context.Context, opts hcloud.FirewallCreateOpts) (*hcloud.Firewall, error) {
+	res, _, err := c.client.Firewall.Create(ctx, opts)
+	return res.Firewall, err
+}
+
+func (c *realClient) ListFirewalls(ctx context.Context, opts hcloud.FirewallListOpts) ([]*hcloud.Firewall, error) {
+	resp, err := c.client.Firewall.AllWithOpts(ctx, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return resp, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return resp, err
+}
+
+func (c *realClient) DeleteFirewall(ctx context.Context, firewall *hcloud.Firewall) error {
+	_, err := c.client.Firewall.Delete(ctx, firewall)
+	return err
+}
+
+func (c *realClient) SetFirewallRules(ctx context.Context, firewall *hcloud.Firewall, rules []hcloud.FirewallRule) error {
+	_, _, err := c.client.Firewall.SetRules(ctx, firewall, hcloud.FirewallSetRulesOpts{Rules: rules})
+	return err
+}
+
+func (c *realClient) ApplyFirewallToResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	_, _, err := c.client.Firewall.ApplyResources(ctx, firewall, resources)
+	return err
+}
+
+func (c *realClient) RemoveFirewallFromResources(ctx context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	_, _, err := c.client.Firewall.RemoveResources(ctx, firewall, resources)
+	return err
+}
+
+func (c *realClient) ListPrimaryIPs(ctx context.Context, opts hcloud.PrimaryIPListOpts) ([]*hcloud.PrimaryIP, error) {
+	resp, err := c.client.PrimaryIP.AllWithOpts(ctx, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return resp, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return resp, err
+}
+
+func (c *realClient) UpdatePrimaryIP(ctx context.Context, primaryIP *hcloud.PrimaryIP, opts hcloud.PrimaryIPUpdateOpts) error {
+	_, _, err := c.client.PrimaryIP.Update(ctx, primaryIP, opts)
+	return err
+}
+
+func (c *realClient) DeletePrimaryIP(ctx context.Context, primaryIP *hcloud.PrimaryIP) error {
+	_, err := c.client.PrimaryIP.Delete(ctx, primaryIP)
+	return err
+}
+
+func (c *realClient) CreateFloatingIP(ctx context.Context, opts hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error) {
+	res, _, err := c.client.FloatingIP.Create(ctx, opts)
+	return res.FloatingIP, err
+}
+
+func (c *realClient) ListFloatingIPs(ctx context.Context, opts hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error) {
+	resp, err := c.client.FloatingIP.AllWithOpts(ctx, opts)
+	if err != nil && strings.Contains(err.Error(), errStringUnauthorized) {
+		return resp, fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	return resp, err
+}
+
+func (c *realClient) AssignFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP, server *hcloud.Server) error {
+	_, _, err := c.client.FloatingIP.Assign(ctx, floatingIP, server)
+	return err
+}
+
+func (c *realClient) UnassignFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP) error {
+	_, _, err := c.client.FloatingIP.Unassign(ctx, floatingIP)
+	return err
+}
+
+func (c *realClient) DeleteFloatingIP(ctx context.Context, floatingIP *hcloud.FloatingIP) error {
+	_, err := c.client.FloatingIP.Delete(ctx, floatingIP)
+	return err
+}
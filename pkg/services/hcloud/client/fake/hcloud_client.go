@@ -43,11 +43,19 @@ type cacheHCloudClient struct {
 	placementGroupCache     placementGroupCache
 	loadBalancerCache       loadBalancerCache
 	networkCache            networkCache
+	volumeCache             volumeCache
+	firewallCache           firewallCache
+	primaryIPCache          primaryIPCache
+	floatingIPCache         floatingIPCache
 	counterMutex            sync.Mutex
 	serverIDCounter         int64
 	placementGroupIDCounter int64
 	loadBalancerIDCounter   int64
 	networkIDCounter        int64
+	volumeIDCounter         int64
+	firewallIDCounter       int64
+	primaryIPIDCounter      int64
+	floatingIPIDCounter     int64
 }
 
 // NewClient gives reference to the fake client using cache for HCloud API.
@@ -64,6 +72,10 @@ func (c *cacheHCloudClient) Reset() {
 	cacheHCloudClientInstance.networkCache = networkCache{}
 	cacheHCloudClientInstance.loadBalancerCache = loadBalancerCache{}
 	cacheHCloudClientInstance.placementGroupCache = placementGroupCache{}
+	cacheHCloudClientInstance.volumeCache = volumeCache{}
+	cacheHCloudClientInstance.firewallCache = firewallCache{}
+	cacheHCloudClientInstance.primaryIPCache = primaryIPCache{}
+	cacheHCloudClientInstance.floatingIPCache = floatingIPCache{}
 
 	cacheHCloudClientInstance.serverCache = serverCache{
 		idMap:   make(map[int64]*hcloud.Server),
@@ -81,11 +93,29 @@ func (c *cacheHCloudClient) Reset() {
 		idMap:   make(map[int64]*hcloud.Network),
 		nameMap: make(map[string]struct{}),
 	}
+	cacheHCloudClientInstance.volumeCache = volumeCache{
+		idMap:   make(map[int64]*hcloud.Volume),
+		nameMap: make(map[string]struct{}),
+	}
+	cacheHCloudClientInstance.firewallCache = firewallCache{
+		idMap:   make(map[int64]*hcloud.Firewall),
+		nameMap: make(map[string]struct{}),
+	}
+	cacheHCloudClientInstance.primaryIPCache = primaryIPCache{
+		idMap: make(map[int64]*hcloud.PrimaryIP),
+	}
+	cacheHCloudClientInstance.floatingIPCache = floatingIPCache{
+		idMap: make(map[int64]*hcloud.FloatingIP),
+	}
 
 	cacheHCloudClientInstance.serverIDCounter = 0
 	cacheHCloudClientInstance.placementGroupIDCounter = 0
 	cacheHCloudClientInstance.loadBalancerIDCounter = 0
 	cacheHCloudClientInstance.networkIDCounter = 0
+	cacheHCloudClientInstance.volumeIDCounter = 0
+	cacheHCloudClientInstance.firewallIDCounter = 0
+	cacheHCloudClientInstance.primaryIPIDCounter = 0
+	cacheHCloudClientInstance.floatingIPIDCounter = 0
 }
 
 type cacheHCloudClientFactory struct{}
@@ -107,6 +137,20 @@ var cacheHCloudClientInstance = &cacheHCloudClient{
 		idMap:   make(map[int64]*hcloud.Network),
 		nameMap: make(map[string]struct{}),
 	},
+	volumeCache: volumeCache{
+		idMap:   make(map[int64]*hcloud.Volume),
+		nameMap: make(map[string]struct{}),
+	},
+	firewallCache: firewallCache{
+		idMap:   make(map[int64]*hcloud.Firewall),
+		nameMap: make(map[string]struct{}),
+	},
+	primaryIPCache: primaryIPCache{
+		idMap: make(map[int64]*hcloud.PrimaryIP),
+	},
+	floatingIPCache: floatingIPCache{
+		idMap: make(map[int64]*hcloud.FloatingIP),
+	},
 }
 
 // NewHCloudClientFactory creates new fake HCloud client factories using cache.
@@ -136,6 +180,24 @@ type placementGroupCache struct {
 	nameMap map[string]struct{}
 }
 
+type volumeCache struct {
+	idMap   map[int64]*hcloud.Volume
+	nameMap map[string]struct{}
+}
+
+type firewallCache struct {
+	idMap   map[int64]*hcloud.Firewall
+	nameMap map[string]struct{}
+}
+
+type primaryIPCache struct {
+	idMap map[int64]*hcloud.PrimaryIP
+}
+
+type floatingIPCache struct {
+	idMap map[int64]*hcloud.FloatingIP
+}
+
 var defaultSSHKey = hcloud.SSHKey{
 	ID:          1,
 	Name:        "testsshkey",
@@ -462,6 +524,16 @@ func (c *cacheHCloudClient) CreateServer(_ context.Context, opts hcloud.ServerCr
 		Status:         hcloud.ServerStatusRunning,
 	}
 
+	if opts.Location != nil {
+		server.Datacenter = &hcloud.Datacenter{Name: opts.Location.Name + "-dc1", Location: opts.Location}
+	}
+
+	if opts.PublicNet != nil {
+		if err := c.assignPrimaryIPs(server, opts.PublicNet); err != nil {
+			return server, err
+		}
+	}
+
 	for _, network := range opts.Networks {
 		network, found := c.networkCache.idMap[network.ID]
 		if !found {
@@ -471,6 +543,18 @@ func (c *cacheHCloudClient) CreateServer(_ context.Context, opts hcloud.ServerCr
 		server.PrivateNet = append(server.PrivateNet, hcloud.ServerPrivateNet{IP: ip})
 	}
 
+	for _, volume := range opts.Volumes {
+		volume, found := c.volumeCache.idMap[volume.ID]
+		if !found {
+			return server, hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+		}
+		if volume.Server != nil {
+			return server, hcloud.Error{Code: hcloud.ErrorCodeVolumeAlreadyAttached, Message: "already attached"}
+		}
+		volume.Server = server
+		server.Volumes = append(server.Volumes, volume)
+	}
+
 	// Add server to cache
 	c.serverCache.idMap[server.ID] = server
 	c.serverCache.nameMap[server.Name] = struct{}{}
@@ -547,11 +631,48 @@ func (c *cacheHCloudClient) PowerOnServer(_ context.Context, server *hcloud.Serv
 	return nil
 }
 
+func (c *cacheHCloudClient) ChangeServerType(_ context.Context, server *hcloud.Server, opts hcloud.ServerChangeTypeOpts) error {
+	if _, found := c.serverCache.idMap[server.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	if c.serverCache.idMap[server.ID].Status != hcloud.ServerStatusOff {
+		return hcloud.Error{Code: hcloud.ErrorCodeServerNotStopped, Message: "server not stopped"}
+	}
+	c.serverCache.idMap[server.ID].ServerType = opts.ServerType
+	return nil
+}
+
 func (c *cacheHCloudClient) DeleteServer(_ context.Context, server *hcloud.Server) error {
 	if _, found := c.serverCache.idMap[server.ID]; !found {
 		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
 	}
 	n := c.serverCache.idMap[server.ID]
+
+	// volumes get detached when the server is deleted
+	for _, volume := range n.Volumes {
+		volume.Server = nil
+	}
+
+	// primary IPs get deleted together with the server if auto delete is set, otherwise they get unassigned
+	for id, primaryIP := range c.primaryIPCache.idMap {
+		if primaryIP.AssigneeID != n.ID {
+			continue
+		}
+		if primaryIP.AutoDelete {
+			delete(c.primaryIPCache.idMap, id)
+			continue
+		}
+		primaryIP.AssigneeID = 0
+		primaryIP.AssigneeType = ""
+	}
+
+	// floating IPs get unassigned when the server is deleted
+	for _, floatingIP := range c.floatingIPCache.idMap {
+		if floatingIP.Server != nil && floatingIP.Server.ID == n.ID {
+			floatingIP.Server = nil
+		}
+	}
+
 	delete(c.serverCache.nameMap, n.Name)
 	delete(c.serverCache.idMap, server.ID)
 	return nil
@@ -663,6 +784,14 @@ func (c *cacheHCloudClient) DeleteNetwork(_ context.Context, network *hcloud.Net
 	return nil
 }
 
+func (c *cacheHCloudClient) AddSubnetToNetwork(_ context.Context, network *hcloud.Network, opts hcloud.NetworkAddSubnetOpts) error {
+	if _, found := c.networkCache.idMap[network.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	c.networkCache.idMap[network.ID].Subnets = append(c.networkCache.idMap[network.ID].Subnets, opts.Subnet)
+	return nil
+}
+
 func (c *cacheHCloudClient) ListSSHKeys(_ context.Context, _ hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error) {
 	return []*hcloud.SSHKey{&defaultSSHKey}, nil
 }
@@ -746,6 +875,129 @@ func (c *cacheHCloudClient) AddServerToPlacementGroup(_ context.Context, server
 	return nil
 }
 
+func (c *cacheHCloudClient) CreateVolume(_ context.Context, opts hcloud.VolumeCreateOpts) (*hcloud.Volume, error) {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	if _, found := c.volumeCache.nameMap[opts.Name]; found {
+		return nil, fmt.Errorf("already exists")
+	}
+
+	c.volumeIDCounter++
+	volume := &hcloud.Volume{
+		ID:          c.volumeIDCounter,
+		Name:        opts.Name,
+		Labels:      opts.Labels,
+		Size:        opts.Size,
+		Location:    opts.Location,
+		Format:      opts.Format,
+		Status:      hcloud.VolumeStatusAvailable,
+		LinuxDevice: fmt.Sprintf("/dev/disk/by-id/scsi-0HC_Volume_%d", c.volumeIDCounter),
+	}
+
+	if opts.Server != nil {
+		server, found := c.serverCache.idMap[opts.Server.ID]
+		if !found {
+			return nil, hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+		}
+		volume.Server = server
+		server.Volumes = append(server.Volumes, volume)
+	}
+
+	// Add volume to cache
+	c.volumeCache.idMap[volume.ID] = volume
+	c.volumeCache.nameMap[volume.Name] = struct{}{}
+	return volume, nil
+}
+
+func (c *cacheHCloudClient) ListVolumes(_ context.Context, opts hcloud.VolumeListOpts) ([]*hcloud.Volume, error) {
+	volumes := make([]*hcloud.Volume, 0, len(c.volumeCache.idMap))
+
+	labels, err := utils.LabelSelectorToLabels(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
+	}
+
+	for _, volume := range c.volumeCache.idMap {
+		// if name is set and is not correct, continue
+		if opts.Name != "" && volume.Name != opts.Name {
+			continue
+		}
+
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := volume.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			volumes = append(volumes, volume)
+		}
+	}
+
+	return volumes, nil
+}
+
+func (c *cacheHCloudClient) AttachVolumeToServer(_ context.Context, volume *hcloud.Volume, opts hcloud.VolumeAttachOpts) error {
+	// Check if volume exists
+	if _, found := c.volumeCache.idMap[volume.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// Check if server exists
+	server, found := c.serverCache.idMap[opts.Server.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// check if already attached
+	if c.volumeCache.idMap[volume.ID].Server != nil {
+		return hcloud.Error{Code: hcloud.ErrorCodeVolumeAlreadyAttached, Message: "already attached"}
+	}
+
+	// Attach it
+	c.volumeCache.idMap[volume.ID].Server = server
+	server.Volumes = append(server.Volumes, c.volumeCache.idMap[volume.ID])
+	return nil
+}
+
+func (c *cacheHCloudClient) DetachVolume(_ context.Context, volume *hcloud.Volume) error {
+	// Check if volume exists
+	if _, found := c.volumeCache.idMap[volume.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	// detach it if it is attached
+	if server := c.volumeCache.idMap[volume.ID].Server; server != nil {
+		for i, v := range server.Volumes {
+			if v.ID == volume.ID {
+				server.Volumes = append(server.Volumes[:i], server.Volumes[i+1:]...)
+				break
+			}
+		}
+	}
+	c.volumeCache.idMap[volume.ID].Server = nil
+	return nil
+}
+
+func (c *cacheHCloudClient) DeleteVolume(_ context.Context, volume *hcloud.Volume) error {
+	if _, found := c.volumeCache.idMap[volume.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	v := c.volumeCache.idMap[volume.ID]
+
+	// attached volumes cannot be deleted
+	if v.Server != nil {
+		return hcloud.Error{Code: hcloud.ErrorCodeLocked, Message: "volume is attached"}
+	}
+
+	delete(c.volumeCache.nameMap, v.Name)
+	delete(c.volumeCache.idMap, volume.ID)
+	return nil
+}
+
 func isIntInList(list []int64, str int64) bool {
 	for _, s := range list {
 		if s == str {
@@ -754,3 +1006,393 @@ func isIntInList(list []int64, str int64) bool {
 	}
 	return false
 }
+
+func (c *cacheHCloudClient) CreateFirewall(_ context.Context, opts hcloud.FirewallCreateOpts) (*hcloud.Firewall, error) {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	if _, found := c.firewallCache.nameMap[opts.Name]; found {
+		return nil, fmt.Errorf("already exists")
+	}
+
+	c.firewallIDCounter++
+	firewall := &hcloud.Firewall{
+		ID:        c.firewallIDCounter,
+		Name:      opts.Name,
+		Labels:    opts.Labels,
+		Rules:     append([]hcloud.FirewallRule(nil), opts.Rules...),
+		AppliedTo: make([]hcloud.FirewallResource, 0, len(opts.ApplyTo)),
+	}
+	for _, resource := range opts.ApplyTo {
+		firewall.AppliedTo = append(firewall.AppliedTo, copyFirewallResource(resource))
+	}
+
+	// Add firewall to cache
+	c.firewallCache.idMap[firewall.ID] = firewall
+	c.firewallCache.nameMap[firewall.Name] = struct{}{}
+	return firewall, nil
+}
+
+func (c *cacheHCloudClient) ListFirewalls(_ context.Context, opts hcloud.FirewallListOpts) ([]*hcloud.Firewall, error) {
+	firewalls := make([]*hcloud.Firewall, 0, len(c.firewallCache.idMap))
+
+	labels, err := utils.LabelSelectorToLabels(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
+	}
+
+	for _, firewall := range c.firewallCache.idMap {
+		if opts.Name != "" && firewall.Name != opts.Name {
+			continue
+		}
+
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := firewall.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			firewalls = append(firewalls, firewall)
+		}
+	}
+
+	return firewalls, nil
+}
+
+func (c *cacheHCloudClient) DeleteFirewall(_ context.Context, firewall *hcloud.Firewall) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	f, found := c.firewallCache.idMap[firewall.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	if len(f.AppliedTo) > 0 {
+		return hcloud.Error{Code: hcloud.ErrorCodeResourceInUse, Message: "firewall is still in use"}
+	}
+
+	delete(c.firewallCache.nameMap, f.Name)
+	delete(c.firewallCache.idMap, f.ID)
+	return nil
+}
+
+func (c *cacheHCloudClient) SetFirewallRules(_ context.Context, firewall *hcloud.Firewall, rules []hcloud.FirewallRule) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	f, found := c.firewallCache.idMap[firewall.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	f.Rules = append([]hcloud.FirewallRule(nil), rules...)
+	return nil
+}
+
+func (c *cacheHCloudClient) ApplyFirewallToResources(_ context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	f, found := c.firewallCache.idMap[firewall.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	for _, resource := range resources {
+		if findFirewallResource(f.AppliedTo, resource) != -1 {
+			return hcloud.Error{Code: hcloud.ErrorCodeFirewallAlreadyApplied, Message: "already applied"}
+		}
+		if resource.Type == hcloud.FirewallResourceTypeServer {
+			if _, found := c.serverCache.idMap[resource.Server.ID]; !found {
+				return hcloud.Error{Code: hcloud.ErrorCodeFirewallResourceNotFound, Message: "resource not found"}
+			}
+		}
+	}
+
+	for _, resource := range resources {
+		f.AppliedTo = append(f.AppliedTo, copyFirewallResource(resource))
+	}
+	return nil
+}
+
+func (c *cacheHCloudClient) RemoveFirewallFromResources(_ context.Context, firewall *hcloud.Firewall, resources []hcloud.FirewallResource) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	f, found := c.firewallCache.idMap[firewall.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	for _, resource := range resources {
+		if findFirewallResource(f.AppliedTo, resource) == -1 {
+			return hcloud.Error{Code: hcloud.ErrorCodeFirewallAlreadyRemoved, Message: "already removed"}
+		}
+	}
+
+	for _, resource := range resources {
+		i := findFirewallResource(f.AppliedTo, resource)
+		f.AppliedTo = append(f.AppliedTo[:i], f.AppliedTo[i+1:]...)
+	}
+	return nil
+}
+
+func findFirewallResource(resources []hcloud.FirewallResource, resource hcloud.FirewallResource) int {
+	for i, r := range resources {
+		if r.Type != resource.Type {
+			continue
+		}
+		switch r.Type {
+		case hcloud.FirewallResourceTypeServer:
+			if r.Server.ID == resource.Server.ID {
+				return i
+			}
+		case hcloud.FirewallResourceTypeLabelSelector:
+			if r.LabelSelector.Selector == resource.LabelSelector.Selector {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func copyFirewallResource(resource hcloud.FirewallResource) hcloud.FirewallResource {
+	out := hcloud.FirewallResource{Type: resource.Type}
+	if resource.Server != nil {
+		out.Server = &hcloud.FirewallResourceServer{ID: resource.Server.ID}
+	}
+	if resource.LabelSelector != nil {
+		out.LabelSelector = &hcloud.FirewallResourceLabelSelector{Selector: resource.LabelSelector.Selector}
+	}
+	return out
+}
+
+// assignPrimaryIPs assigns the given primary IPs to the server or creates new ones, like Hetzner's Cloud API does.
+func (c *cacheHCloudClient) assignPrimaryIPs(server *hcloud.Server, publicNet *hcloud.ServerCreatePublicNet) error {
+	if publicNet.EnableIPv4 {
+		primaryIP, err := c.primaryIPForServer(server, publicNet.IPv4, hcloud.PrimaryIPTypeIPv4)
+		if err != nil {
+			return err
+		}
+		server.PublicNet.IPv4 = hcloud.ServerPublicNetIPv4{ID: primaryIP.ID, IP: primaryIP.IP}
+	}
+
+	if publicNet.EnableIPv6 {
+		primaryIP, err := c.primaryIPForServer(server, publicNet.IPv6, hcloud.PrimaryIPTypeIPv6)
+		if err != nil {
+			return err
+		}
+		server.PublicNet.IPv6 = hcloud.ServerPublicNetIPv6{ID: primaryIP.ID, IP: primaryIP.IP, Network: primaryIP.Network}
+	}
+
+	return nil
+}
+
+func (c *cacheHCloudClient) primaryIPForServer(server *hcloud.Server, requested *hcloud.PrimaryIP, ipType hcloud.PrimaryIPType) (*hcloud.PrimaryIP, error) {
+	if requested != nil {
+		primaryIP, found := c.primaryIPCache.idMap[requested.ID]
+		if !found {
+			return nil, hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+		}
+		if primaryIP.AssigneeID != 0 {
+			return nil, hcloud.Error{Code: hcloud.ErrorCodeConflict, Message: "primary IP is already assigned"}
+		}
+		primaryIP.AssigneeID = server.ID
+		primaryIP.AssigneeType = "server"
+		return primaryIP, nil
+	}
+
+	c.primaryIPIDCounter++
+	id := c.primaryIPIDCounter
+	primaryIP := &hcloud.PrimaryIP{
+		ID:           id,
+		Name:         fmt.Sprintf("primary_ip-%d", id),
+		Type:         ipType,
+		AssigneeID:   server.ID,
+		AssigneeType: "server",
+		AutoDelete:   true,
+		Datacenter:   server.Datacenter,
+		Labels:       map[string]string{},
+	}
+	if ipType == hcloud.PrimaryIPTypeIPv4 {
+		primaryIP.IP = net.IPv4(203, 0, 113, byte(id))
+	} else {
+		_, network, err := net.ParseCIDR(fmt.Sprintf("2001:db8:%x::/64", id))
+		if err != nil {
+			return nil, err
+		}
+		primaryIP.IP = network.IP
+		primaryIP.Network = network
+	}
+
+	c.primaryIPCache.idMap[primaryIP.ID] = primaryIP
+	return primaryIP, nil
+}
+
+func (c *cacheHCloudClient) ListPrimaryIPs(_ context.Context, opts hcloud.PrimaryIPListOpts) ([]*hcloud.PrimaryIP, error) {
+	primaryIPs := make([]*hcloud.PrimaryIP, 0, len(c.primaryIPCache.idMap))
+
+	labels, err := utils.LabelSelectorToLabels(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
+	}
+
+	for _, primaryIP := range c.primaryIPCache.idMap {
+		if opts.Name != "" && primaryIP.Name != opts.Name {
+			continue
+		}
+
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := primaryIP.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			primaryIPs = append(primaryIPs, primaryIP)
+		}
+	}
+
+	return primaryIPs, nil
+}
+
+func (c *cacheHCloudClient) UpdatePrimaryIP(_ context.Context, primaryIP *hcloud.PrimaryIP, opts hcloud.PrimaryIPUpdateOpts) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	p, found := c.primaryIPCache.idMap[primaryIP.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	if opts.AutoDelete != nil {
+		p.AutoDelete = *opts.AutoDelete
+	}
+	if opts.Labels != nil {
+		p.Labels = *opts.Labels
+	}
+	if opts.Name != "" {
+		p.Name = opts.Name
+	}
+	return nil
+}
+
+func (c *cacheHCloudClient) DeletePrimaryIP(_ context.Context, primaryIP *hcloud.PrimaryIP) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	p, found := c.primaryIPCache.idMap[primaryIP.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	if p.AssigneeID != 0 {
+		return hcloud.Error{Code: hcloud.ErrorCodeConflict, Message: "primary IP is still assigned"}
+	}
+
+	delete(c.primaryIPCache.idMap, p.ID)
+	return nil
+}
+
+func (c *cacheHCloudClient) CreateFloatingIP(_ context.Context, opts hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error) {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	c.floatingIPIDCounter++
+	floatingIP := &hcloud.FloatingIP{
+		ID:           c.floatingIPIDCounter,
+		Type:         opts.Type,
+		HomeLocation: opts.HomeLocation,
+		Labels:       opts.Labels,
+	}
+	if opts.Name != nil {
+		floatingIP.Name = *opts.Name
+	}
+	if opts.Description != nil {
+		floatingIP.Description = *opts.Description
+	}
+	if opts.Type == hcloud.FloatingIPTypeIPv4 {
+		floatingIP.IP = net.IPv4(198, 51, 100, byte(floatingIP.ID))
+	} else {
+		floatingIP.IP = net.ParseIP(fmt.Sprintf("2001:db8:f:%x::1", floatingIP.ID))
+	}
+	if opts.Server != nil {
+		floatingIP.Server = &hcloud.Server{ID: opts.Server.ID}
+	}
+
+	c.floatingIPCache.idMap[floatingIP.ID] = floatingIP
+	return floatingIP, nil
+}
+
+func (c *cacheHCloudClient) ListFloatingIPs(_ context.Context, opts hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error) {
+	floatingIPs := make([]*hcloud.FloatingIP, 0, len(c.floatingIPCache.idMap))
+
+	labels, err := utils.LabelSelectorToLabels(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert label selector to labels: %w", err)
+	}
+
+	for _, floatingIP := range c.floatingIPCache.idMap {
+		if opts.Name != "" && floatingIP.Name != opts.Name {
+			continue
+		}
+
+		allLabelsFound := true
+		for key, label := range labels {
+			if val, found := floatingIP.Labels[key]; !found || val != label {
+				allLabelsFound = false
+				break
+			}
+		}
+		if allLabelsFound {
+			floatingIPs = append(floatingIPs, floatingIP)
+		}
+	}
+
+	return floatingIPs, nil
+}
+
+func (c *cacheHCloudClient) AssignFloatingIP(_ context.Context, floatingIP *hcloud.FloatingIP, server *hcloud.Server) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	f, found := c.floatingIPCache.idMap[floatingIP.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+	if _, found := c.serverCache.idMap[server.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "server not found"}
+	}
+
+	f.Server = &hcloud.Server{ID: server.ID}
+	return nil
+}
+
+func (c *cacheHCloudClient) UnassignFloatingIP(_ context.Context, floatingIP *hcloud.FloatingIP) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	f, found := c.floatingIPCache.idMap[floatingIP.ID]
+	if !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	f.Server = nil
+	return nil
+}
+
+func (c *cacheHCloudClient) DeleteFloatingIP(_ context.Context, floatingIP *hcloud.FloatingIP) error {
+	c.counterMutex.Lock()
+	defer c.counterMutex.Unlock()
+
+	if _, found := c.floatingIPCache.idMap[floatingIP.ID]; !found {
+		return hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "not found"}
+	}
+
+	delete(c.floatingIPCache.idMap, floatingIP.ID)
+	return nil
+}
@@ -760,3 +760,283 @@ var _ = Describe("Placement groups", func() {
 		Expect(hcloud.IsError(err, hcloud.ErrorCodeNotFound)).To(BeTrue())
 	})
 })
+
+var _ = Describe("Volumes", func() {
+	var listOpts hcloud.VolumeListOpts
+	listOpts.LabelSelector = labelSelector
+
+	opts := hcloud.VolumeCreateOpts{
+		Name: "test-volume",
+		Size: 10,
+		Labels: map[string]string{
+			"key1": "val1",
+			"key2": "val2",
+		},
+		Location: &hcloud.Location{Name: "fsn1"},
+	}
+
+	client := factory.NewClient("")
+	var server *hcloud.Server
+	var volume *hcloud.Volume
+
+	BeforeEach(func() {
+		client.Reset()
+		var err error
+		server, err = client.CreateServer(ctx, hcloud.ServerCreateOpts{
+			Name: "test-server",
+			ServerType: &hcloud.ServerType{
+				Name: "cpx11",
+			},
+		})
+		Expect(err).To(Succeed())
+
+		volume, err = client.CreateVolume(ctx, opts)
+		Expect(err).To(Succeed())
+	})
+
+	It("creates a volume with an ID", func() {
+		Expect(volume.ID).ToNot(Equal(0))
+		Expect(volume.LinuxDevice).ToNot(BeEmpty())
+	})
+
+	It("gives an error when a volume is created twice", func() {
+		_, err := client.CreateVolume(ctx, opts)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("lists volumes", func() {
+		resp, err := client.ListVolumes(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp)).To(Equal(1))
+		Expect(resp[0].ID).To(Equal(volume.ID))
+	})
+
+	It("attaches a volume to a server", func() {
+		Expect(client.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{Server: server})).To(Succeed())
+		resp, err := client.ListVolumes(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp)).To(Equal(1))
+		Expect(resp[0].Server).ToNot(BeNil())
+		Expect(resp[0].Server.ID).To(Equal(server.ID))
+	})
+
+	It("attaches a volume when the server is created", func() {
+		newServer, err := client.CreateServer(ctx, hcloud.ServerCreateOpts{
+			Name:    "test-server-with-volume",
+			Volumes: []*hcloud.Volume{volume},
+		})
+		Expect(err).To(Succeed())
+		Expect(len(newServer.Volumes)).To(Equal(1))
+		Expect(volume.Server.ID).To(Equal(newServer.ID))
+	})
+
+	It("gives an error when a volume is attached twice", func() {
+		Expect(client.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{Server: server})).To(Succeed())
+		err := client.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{Server: server})
+		Expect(err).ToNot(Succeed())
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeVolumeAlreadyAttached)).To(BeTrue())
+	})
+
+	It("gives an error when a volume is attached to a non-existing server", func() {
+		err := client.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{Server: &hcloud.Server{ID: 999}})
+		Expect(err).ToNot(Succeed())
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeNotFound)).To(BeTrue())
+	})
+
+	It("detaches a volume", func() {
+		Expect(client.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{Server: server})).To(Succeed())
+		Expect(client.DetachVolume(ctx, volume)).To(Succeed())
+		Expect(volume.Server).To(BeNil())
+		Expect(len(server.Volumes)).To(Equal(0))
+	})
+
+	It("detaches a volume when the server is deleted", func() {
+		Expect(client.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{Server: server})).To(Succeed())
+		Expect(client.DeleteServer(ctx, server)).To(Succeed())
+		Expect(volume.Server).To(BeNil())
+	})
+
+	It("deletes a volume", func() {
+		Expect(client.DeleteVolume(ctx, volume)).To(Succeed())
+		resp, err := client.ListVolumes(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(len(resp)).To(Equal(0))
+	})
+
+	It("gives an error when an attached volume is deleted", func() {
+		Expect(client.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{Server: server})).To(Succeed())
+		err := client.DeleteVolume(ctx, volume)
+		Expect(err).ToNot(Succeed())
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeLocked)).To(BeTrue())
+	})
+
+	It("gives an error when a non-existing volume is deleted", func() {
+		err := client.DeleteVolume(ctx, &hcloud.Volume{ID: 999})
+		Expect(err).ToNot(Succeed())
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeNotFound)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Firewalls", func() {
+	var listOpts hcloud.FirewallListOpts
+	listOpts.LabelSelector = labelSelector
+
+	opts := hcloud.FirewallCreateOpts{
+		Name: "test-firewall",
+		Labels: map[string]string{
+			"key1": "val1",
+			"key2": "val2",
+		},
+		ApplyTo: []hcloud.FirewallResource{
+			{
+				Type:          hcloud.FirewallResourceTypeLabelSelector,
+				LabelSelector: &hcloud.FirewallResourceLabelSelector{Selector: "key==value"},
+			},
+		},
+	}
+
+	client := factory.NewClient("")
+	var firewall *hcloud.Firewall
+
+	BeforeEach(func() {
+		client.Reset()
+		var err error
+		firewall, err = client.CreateFirewall(ctx, opts)
+		Expect(err).To(Succeed())
+	})
+
+	It("creates a firewall with an ID", func() {
+		Expect(firewall.ID).ToNot(Equal(0))
+		Expect(firewall.AppliedTo).To(HaveLen(1))
+	})
+
+	It("gives an error when a firewall is created twice", func() {
+		_, err := client.CreateFirewall(ctx, opts)
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("lists firewalls with the right label", func() {
+		firewalls, err := client.ListFirewalls(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(firewalls).To(HaveLen(1))
+	})
+
+	It("sets rules of a firewall", func() {
+		rules := []hcloud.FirewallRule{{Direction: hcloud.FirewallRuleDirectionIn, Protocol: hcloud.FirewallRuleProtocolICMP}}
+		Expect(client.SetFirewallRules(ctx, firewall, rules)).To(Succeed())
+		Expect(firewall.Rules).To(HaveLen(1))
+	})
+
+	It("gives an error when a firewall is applied twice", func() {
+		err := client.ApplyFirewallToResources(ctx, firewall, opts.ApplyTo)
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyApplied)).To(BeTrue())
+	})
+
+	It("does not delete a firewall that is still applied", func() {
+		err := client.DeleteFirewall(ctx, firewall)
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeResourceInUse)).To(BeTrue())
+	})
+
+	It("deletes a firewall after it is removed from all resources", func() {
+		Expect(client.RemoveFirewallFromResources(ctx, firewall, opts.ApplyTo)).To(Succeed())
+		Expect(client.DeleteFirewall(ctx, firewall)).To(Succeed())
+
+		firewalls, err := client.ListFirewalls(ctx, listOpts)
+		Expect(err).To(Succeed())
+		Expect(firewalls).To(BeEmpty())
+	})
+
+	It("gives an error when a firewall is deleted that does not exist", func() {
+		err := client.DeleteFirewall(ctx, &hcloud.Firewall{ID: 9999})
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeNotFound)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Primary IPs", func() {
+	client := factory.NewClient("")
+	var server *hcloud.Server
+
+	BeforeEach(func() {
+		client.Reset()
+		var err error
+		server, err = client.CreateServer(ctx, hcloud.ServerCreateOpts{
+			Name:      "test-server",
+			Location:  &hcloud.Location{Name: "fsn1"},
+			PublicNet: &hcloud.ServerCreatePublicNet{EnableIPv4: true, EnableIPv6: true},
+		})
+		Expect(err).To(Succeed())
+	})
+
+	It("creates primary IPs together with the server", func() {
+		Expect(server.PublicNet.IPv4.ID).ToNot(BeZero())
+		Expect(server.PublicNet.IPv6.ID).ToNot(BeZero())
+
+		primaryIPs, err := client.ListPrimaryIPs(ctx, hcloud.PrimaryIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(primaryIPs).To(HaveLen(2))
+	})
+
+	It("deletes primary IPs with auto delete together with the server", func() {
+		Expect(client.DeleteServer(ctx, server)).To(Succeed())
+
+		primaryIPs, err := client.ListPrimaryIPs(ctx, hcloud.PrimaryIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(primaryIPs).To(BeEmpty())
+	})
+
+	It("assigns kept primary IPs to a new server", func() {
+		autoDelete := false
+		primaryIP := &hcloud.PrimaryIP{ID: server.PublicNet.IPv4.ID}
+		Expect(client.UpdatePrimaryIP(ctx, primaryIP, hcloud.PrimaryIPUpdateOpts{AutoDelete: &autoDelete})).To(Succeed())
+
+		// an assigned primary IP cannot be deleted
+		Expect(client.DeletePrimaryIP(ctx, primaryIP)).ToNot(Succeed())
+
+		Expect(client.DeleteServer(ctx, server)).To(Succeed())
+
+		newServer, err := client.CreateServer(ctx, hcloud.ServerCreateOpts{
+			Name:      "new-server",
+			PublicNet: &hcloud.ServerCreatePublicNet{EnableIPv4: true, IPv4: primaryIP},
+		})
+		Expect(err).To(Succeed())
+		Expect(newServer.PublicNet.IPv4.ID).To(Equal(primaryIP.ID))
+		Expect(newServer.PublicNet.IPv4.IP).To(Equal(server.PublicNet.IPv4.IP))
+	})
+})
+
+var _ = Describe("Floating IPs", func() {
+	client := factory.NewClient("")
+	var server *hcloud.Server
+
+	BeforeEach(func() {
+		client.Reset()
+		var err error
+		server, err = client.CreateServer(ctx, hcloud.ServerCreateOpts{Name: "test-server"})
+		Expect(err).To(Succeed())
+	})
+
+	It("creates, assigns and deletes floating IPs", func() {
+		floatingIP, err := client.CreateFloatingIP(ctx, hcloud.FloatingIPCreateOpts{
+			Type:   hcloud.FloatingIPTypeIPv4,
+			Labels: map[string]string{"key": "value"},
+		})
+		Expect(err).To(Succeed())
+		Expect(floatingIP.IP).ToNot(BeNil())
+
+		floatingIPs, err := client.ListFloatingIPs(ctx, hcloud.FloatingIPListOpts{ListOpts: hcloud.ListOpts{LabelSelector: "key==value"}})
+		Expect(err).To(Succeed())
+		Expect(floatingIPs).To(HaveLen(1))
+
+		Expect(client.AssignFloatingIP(ctx, floatingIP, server)).To(Succeed())
+		Expect(floatingIPs[0].Server.ID).To(Equal(server.ID))
+
+		// floating IPs get unassigned when the server is deleted
+		Expect(client.DeleteServer(ctx, server)).To(Succeed())
+		Expect(floatingIPs[0].Server).To(BeNil())
+
+		Expect(client.DeleteFloatingIP(ctx, floatingIP)).To(Succeed())
+		floatingIPs, err = client.ListFloatingIPs(ctx, hcloud.FloatingIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(floatingIPs).To(BeEmpty())
+	})
+})
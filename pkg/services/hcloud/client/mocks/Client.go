@@ -71,6 +71,24 @@ func (_m *Client) AddServiceToLoadBalancer(_a0 context.Context, _a1 *hcloud.Load
 	return r0
 }
 
+// AddSubnetToNetwork provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AddSubnetToNetwork(_a0 context.Context, _a1 *hcloud.Network, _a2 hcloud.NetworkAddSubnetOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSubnetToNetwork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Network, hcloud.NetworkAddSubnetOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddTargetServerToLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) AddTargetServerToLoadBalancer(_a0 context.Context, _a1 hcloud.LoadBalancerAddServerTargetOpts, _a2 *hcloud.LoadBalancer) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -89,6 +107,42 @@ func (_m *Client) AddTargetServerToLoadBalancer(_a0 context.Context, _a1 hcloud.
 	return r0
 }
 
+// ApplyFirewallToResources provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) ApplyFirewallToResources(_a0 context.Context, _a1 *hcloud.Firewall, _a2 []hcloud.FirewallResource) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyFirewallToResources")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AssignFloatingIP provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AssignFloatingIP(_a0 context.Context, _a1 *hcloud.FloatingIP, _a2 *hcloud.Server) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignFloatingIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.FloatingIP, *hcloud.Server) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AttachLoadBalancerToNetwork provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) AttachLoadBalancerToNetwork(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 hcloud.LoadBalancerAttachToNetworkOpts) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -125,6 +179,24 @@ func (_m *Client) AttachServerToNetwork(_a0 context.Context, _a1 *hcloud.Server,
 	return r0
 }
 
+// AttachVolumeToServer provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) AttachVolumeToServer(_a0 context.Context, _a1 *hcloud.Volume, _a2 hcloud.VolumeAttachOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachVolumeToServer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Volume, hcloud.VolumeAttachOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ChangeLoadBalancerAlgorithm provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) ChangeLoadBalancerAlgorithm(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 hcloud.LoadBalancerChangeAlgorithmOpts) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -161,6 +233,84 @@ func (_m *Client) ChangeLoadBalancerType(_a0 context.Context, _a1 *hcloud.LoadBa
 	return r0
 }
 
+// ChangeServerType provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) ChangeServerType(_a0 context.Context, _a1 *hcloud.Server, _a2 hcloud.ServerChangeTypeOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeServerType")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Server, hcloud.ServerChangeTypeOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateFirewall provides a mock function with given fields: _a0, _a1
+func (_m *Client) CreateFirewall(_a0 context.Context, _a1 hcloud.FirewallCreateOpts) (*hcloud.Firewall, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFirewall")
+	}
+
+	var r0 *hcloud.Firewall
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallCreateOpts) (*hcloud.Firewall, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallCreateOpts) *hcloud.Firewall); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Firewall)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FirewallCreateOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateFloatingIP provides a mock function with given fields: _a0, _a1
+func (_m *Client) CreateFloatingIP(_a0 context.Context, _a1 hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateFloatingIP")
+	}
+
+	var r0 *hcloud.FloatingIP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPCreateOpts) (*hcloud.FloatingIP, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPCreateOpts) *hcloud.FloatingIP); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.FloatingIP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FloatingIPCreateOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateLoadBalancer provides a mock function with given fields: _a0, _a1
 func (_m *Client) CreateLoadBalancer(_a0 context.Context, _a1 hcloud.LoadBalancerCreateOpts) (*hcloud.LoadBalancer, error) {
 	ret := _m.Called(_a0, _a1)
@@ -281,6 +431,72 @@ func (_m *Client) CreateServer(_a0 context.Context, _a1 hcloud.ServerCreateOpts)
 	return r0, r1
 }
 
+// CreateVolume provides a mock function with given fields: _a0, _a1
+func (_m *Client) CreateVolume(_a0 context.Context, _a1 hcloud.VolumeCreateOpts) (*hcloud.Volume, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVolume")
+	}
+
+	var r0 *hcloud.Volume
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeCreateOpts) (*hcloud.Volume, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeCreateOpts) *hcloud.Volume); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*hcloud.Volume)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.VolumeCreateOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteFirewall provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeleteFirewall(_a0 context.Context, _a1 *hcloud.Firewall) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFirewall")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Firewall) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFloatingIP provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeleteFloatingIP(_a0 context.Context, _a1 *hcloud.FloatingIP) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFloatingIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.FloatingIP) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteIPTargetOfLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) DeleteIPTargetOfLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 net.IP) error {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -353,6 +569,24 @@ func (_m *Client) DeletePlacementGroup(_a0 context.Context, _a1 int64) error {
 	return r0
 }
 
+// DeletePrimaryIP provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeletePrimaryIP(_a0 context.Context, _a1 *hcloud.PrimaryIP) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePrimaryIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.PrimaryIP) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) DeleteServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	ret := _m.Called(_a0, _a1)
@@ -407,6 +641,42 @@ func (_m *Client) DeleteTargetServerOfLoadBalancer(_a0 context.Context, _a1 *hcl
 	return r0
 }
 
+// DeleteVolume provides a mock function with given fields: _a0, _a1
+func (_m *Client) DeleteVolume(_a0 context.Context, _a1 *hcloud.Volume) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteVolume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Volume) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachVolume provides a mock function with given fields: _a0, _a1
+func (_m *Client) DetachVolume(_a0 context.Context, _a1 *hcloud.Volume) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachVolume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Volume) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) GetServer(_a0 context.Context, _a1 int64) (*hcloud.Server, error) {
 	ret := _m.Called(_a0, _a1)
@@ -467,6 +737,66 @@ func (_m *Client) GetServerType(_a0 context.Context, _a1 string) (*hcloud.Server
 	return r0, r1
 }
 
+// ListFirewalls provides a mock function with given fields: _a0, _a1
+func (_m *Client) ListFirewalls(_a0 context.Context, _a1 hcloud.FirewallListOpts) ([]*hcloud.Firewall, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFirewalls")
+	}
+
+	var r0 []*hcloud.Firewall
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallListOpts) ([]*hcloud.Firewall, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FirewallListOpts) []*hcloud.Firewall); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*hcloud.Firewall)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FirewallListOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListFloatingIPs provides a mock function with given fields: _a0, _a1
+func (_m *Client) ListFloatingIPs(_a0 context.Context, _a1 hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFloatingIPs")
+	}
+
+	var r0 []*hcloud.FloatingIP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPListOpts) ([]*hcloud.FloatingIP, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.FloatingIPListOpts) []*hcloud.FloatingIP); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*hcloud.FloatingIP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.FloatingIPListOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListImages provides a mock function with given fields: _a0, _a1
 func (_m *Client) ListImages(_a0 context.Context, _a1 hcloud.ImageListOpts) ([]*hcloud.Image, error) {
 	ret := _m.Called(_a0, _a1)
@@ -587,6 +917,36 @@ func (_m *Client) ListPlacementGroups(_a0 context.Context, _a1 hcloud.PlacementG
 	return r0, r1
 }
 
+// ListPrimaryIPs provides a mock function with given fields: _a0, _a1
+func (_m *Client) ListPrimaryIPs(_a0 context.Context, _a1 hcloud.PrimaryIPListOpts) ([]*hcloud.PrimaryIP, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPrimaryIPs")
+	}
+
+	var r0 []*hcloud.PrimaryIP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.PrimaryIPListOpts) ([]*hcloud.PrimaryIP, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.PrimaryIPListOpts) []*hcloud.PrimaryIP); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*hcloud.PrimaryIP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.PrimaryIPListOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListSSHKeys provides a mock function with given fields: _a0, _a1
 func (_m *Client) ListSSHKeys(_a0 context.Context, _a1 hcloud.SSHKeyListOpts) ([]*hcloud.SSHKey, error) {
 	ret := _m.Called(_a0, _a1)
@@ -677,6 +1037,36 @@ func (_m *Client) ListServers(_a0 context.Context, _a1 hcloud.ServerListOpts) ([
 	return r0, r1
 }
 
+// ListVolumes provides a mock function with given fields: _a0, _a1
+func (_m *Client) ListVolumes(_a0 context.Context, _a1 hcloud.VolumeListOpts) ([]*hcloud.Volume, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListVolumes")
+	}
+
+	var r0 []*hcloud.Volume
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeListOpts) ([]*hcloud.Volume, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, hcloud.VolumeListOpts) []*hcloud.Volume); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*hcloud.Volume)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, hcloud.VolumeListOpts) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PowerOnServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) PowerOnServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	ret := _m.Called(_a0, _a1)
@@ -713,11 +1103,47 @@ func (_m *Client) RebootServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	return r0
 }
 
+// RemoveFirewallFromResources provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) RemoveFirewallFromResources(_a0 context.Context, _a1 *hcloud.Firewall, _a2 []hcloud.FirewallResource) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFirewallFromResources")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Firewall, []hcloud.FirewallResource) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Reset provides a mock function with given fields:
 func (_m *Client) Reset() {
 	_m.Called()
 }
 
+// SetFirewallRules provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) SetFirewallRules(_a0 context.Context, _a1 *hcloud.Firewall, _a2 []hcloud.FirewallRule) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetFirewallRules")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.Firewall, []hcloud.FirewallRule) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ShutdownServer provides a mock function with given fields: _a0, _a1
 func (_m *Client) ShutdownServer(_a0 context.Context, _a1 *hcloud.Server) error {
 	ret := _m.Called(_a0, _a1)
@@ -736,6 +1162,24 @@ func (_m *Client) ShutdownServer(_a0 context.Context, _a1 *hcloud.Server) error
 	return r0
 }
 
+// UnassignFloatingIP provides a mock function with given fields: _a0, _a1
+func (_m *Client) UnassignFloatingIP(_a0 context.Context, _a1 *hcloud.FloatingIP) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnassignFloatingIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.FloatingIP) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateLoadBalancer provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) UpdateLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalancer, _a2 hcloud.LoadBalancerUpdateOpts) (*hcloud.LoadBalancer, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -766,6 +1210,24 @@ func (_m *Client) UpdateLoadBalancer(_a0 context.Context, _a1 *hcloud.LoadBalanc
 	return r0, r1
 }
 
+// UpdatePrimaryIP provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) UpdatePrimaryIP(_a0 context.Context, _a1 *hcloud.PrimaryIP, _a2 hcloud.PrimaryIPUpdateOpts) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePrimaryIP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *hcloud.PrimaryIP, hcloud.PrimaryIPUpdateOpts) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
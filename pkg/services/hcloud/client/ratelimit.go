@@ -0,0 +1,290 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hcloudclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// defaultRequestLimit is the number of requests per hour that the HCloud API allows for one project.
+	defaultRequestLimit = 3600
+
+	// requestRefillRate is the number of requests per second that become available again.
+	requestRefillRate = 1.0
+
+	// lowPriorityReserve is the share of the budget that low priority requests must not use. It is kept free
+	// for requests that create or delete resources.
+	lowPriorityReserve = 0.1
+
+	// maxThrottleWait is the maximum time a request waits for the budget. Requests that would have to wait
+	// longer fail right away with a rate limit error.
+	maxThrottleWait = 20 * time.Second
+
+	// requestBudgetIdleTimeout is the time after which an unused budget is removed. The budget has refilled
+	// completely by then, so a new budget for the token starts with the same state.
+	requestBudgetIdleTimeout = time.Hour
+
+	rateLimitExceededBody = `{"error":{"code":"rate_limit_exceeded","message":"client-side request budget exhausted"}}`
+)
+
+var errRequestBudgetExhausted = errors.New("request budget exhausted")
+
+type requestPriority string
+
+const (
+	requestPriorityHigh requestPriority = "high"
+	requestPriorityLow  requestPriority = "low"
+)
+
+// priorityOf returns the priority of a request. Read requests like list calls have a lower priority than
+// requests that change resources.
+func priorityOf(req *http.Request) requestPriority {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return requestPriorityLow
+	}
+	return requestPriorityHigh
+}
+
+var (
+	rateLimitRemainingGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "caph_hcloud_api_rate_limit_remaining",
+		Help: "Remaining requests of an HCloud token as reported by the HCloud API.",
+	}, []string{"token"})
+
+	requestBudgetGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "caph_hcloud_api_request_budget",
+		Help: "Requests that are currently available in the client-side budget of an HCloud token.",
+	}, []string{"token"})
+
+	throttledRequestsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "caph_hcloud_api_throttled_requests_total",
+		Help: "Requests to the HCloud API that had to wait for the client-side budget.",
+	}, []string{"token", "priority"})
+
+	rejectedRequestsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "caph_hcloud_api_rejected_requests_total",
+		Help: "Requests to the HCloud API that were rejected because the client-side budget was exhausted.",
+	}, []string{"token", "priority"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		rateLimitRemainingGauge,
+		requestBudgetGauge,
+		throttledRequestsCounter,
+		rejectedRequestsCounter,
+	)
+}
+
+var (
+	requestBudgetsMutex sync.Mutex
+	requestBudgets      = make(map[string]*requestBudget)
+)
+
+// requestBudgetFor returns the budget of an HCloud token. The budget is shared by all clients of the process
+// that use the same token. Budgets of tokens that have not been used for a while, e.g. because they were
+// rotated, are removed.
+func requestBudgetFor(hcloudToken string) *requestBudget {
+	hash := sha256.Sum256([]byte(hcloudToken))
+	key := hex.EncodeToString(hash[:])
+
+	requestBudgetsMutex.Lock()
+	defer requestBudgetsMutex.Unlock()
+
+	evictIdleRequestBudgets(key, time.Now())
+
+	budget, found := requestBudgets[key]
+	if !found {
+		// only use a prefix of the hash to identify the token in metrics
+		budget = newRequestBudget(key[:8], time.Now)
+		requestBudgets[key] = budget
+	}
+	return budget
+}
+
+// evictIdleRequestBudgets removes the budgets that have been idle for longer than requestBudgetIdleTimeout,
+// except for the budget with the given key, together with their metrics. requestBudgetsMutex has to be held.
+func evictIdleRequestBudgets(keep string, now time.Time) {
+	for key, budget := range requestBudgets {
+		if key == keep || !budget.idleSince(now.Add(-requestBudgetIdleTimeout)) {
+			continue
+		}
+		delete(requestBudgets, key)
+		rateLimitRemainingGauge.DeleteLabelValues(budget.token)
+		requestBudgetGauge.DeleteLabelValues(budget.token)
+		throttledRequestsCounter.DeletePartialMatch(prometheus.Labels{"token": budget.token})
+		rejectedRequestsCounter.DeletePartialMatch(prometheus.Labels{"token": budget.token})
+	}
+}
+
+// requestBudget is a token bucket that limits the requests made with one HCloud token. It refills at the
+// rate of the HCloud API and is corrected with the rate limit headers of the responses.
+type requestBudget struct {
+	mutex     sync.Mutex
+	token     string
+	limit     float64
+	available float64
+	last      time.Time
+	now       func() time.Time
+}
+
+func newRequestBudget(token string, now func() time.Time) *requestBudget {
+	return &requestBudget{
+		token:     token,
+		limit:     defaultRequestLimit,
+		available: defaultRequestLimit,
+		last:      now(),
+		now:       now,
+	}
+}
+
+// idleSince returns true if the budget has not been used since the given time.
+func (b *requestBudget) idleSince(t time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.last.Before(t)
+}
+
+// refill adds the requests that became available since the last call. The mutex has to be held.
+func (b *requestBudget) refill() {
+	now := b.now()
+	b.available = min(b.limit, b.available+now.Sub(b.last).Seconds()*requestRefillRate)
+	b.last = now
+}
+
+// take takes one request from the budget. If there is not enough budget, it returns the time to wait
+// until the request can be taken.
+func (b *requestBudget) take(priority requestPriority) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+
+	needed := 1.0
+	if priority == requestPriorityLow {
+		needed += b.limit * lowPriorityReserve
+	}
+
+	if b.available >= needed {
+		b.available--
+		requestBudgetGauge.WithLabelValues(b.token).Set(b.available)
+		return 0
+	}
+
+	return time.Duration((needed - b.available) / requestRefillRate * float64(time.Second))
+}
+
+// wait blocks until a request of the given priority can be taken from the budget.
+func (b *requestBudget) wait(ctx context.Context, priority requestPriority) error {
+	throttled := false
+	for {
+		wait := b.take(priority)
+		if wait == 0 {
+			return nil
+		}
+		if wait > maxThrottleWait {
+			rejectedRequestsCounter.WithLabelValues(b.token, string(priority)).Inc()
+			return errRequestBudgetExhausted
+		}
+		if !throttled {
+			throttledRequestsCounter.WithLabelValues(b.token, string(priority)).Inc()
+			throttled = true
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// update corrects the budget with the rate limit headers of a response. Other processes might use the same
+// token, so the budget never exceeds what the API reports as remaining.
+func (b *requestBudget) update(resp *http.Response) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+
+	if limit, err := strconv.Atoi(resp.Header.Get("RateLimit-Limit")); err == nil && limit > 0 {
+		b.limit = float64(limit)
+		b.available = min(b.available, b.limit)
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("RateLimit-Remaining")); err == nil {
+		rateLimitRemainingGauge.WithLabelValues(b.token).Set(float64(remaining))
+		b.available = min(b.available, float64(remaining))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		b.available = 0
+	}
+
+	requestBudgetGauge.WithLabelValues(b.token).Set(b.available)
+}
+
+// rateLimitTransport throttles the requests to the HCloud API with the budget of the HCloud token.
+type rateLimitTransport struct {
+	roundTripper http.RoundTripper
+	budget       *requestBudget
+}
+
+// RoundTrip waits for the budget before sending the request. If the budget is exhausted, it answers with
+// a rate limit error, the same way the HCloud API does.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.budget.wait(req.Context(), priorityOf(req)); err != nil {
+		if errors.Is(err, errRequestBudgetExhausted) {
+			return rateLimitExceededResponse(req), nil
+		}
+		return nil, err
+	}
+
+	resp, err := t.roundTripper.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	t.budget.update(resp)
+	return resp, nil
+}
+
+func rateLimitExceededResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "429 Too Many Requests",
+		StatusCode:    http.StatusTooManyRequests,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(rateLimitExceededBody)),
+		ContentLength: int64(len(rateLimitExceededBody)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hcloudclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHCloudClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HCloud client tests")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("requestBudget", func() {
+	var (
+		now    time.Time
+		budget *requestBudget
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		budget = newRequestBudget("test", func() time.Time { return now })
+	})
+
+	It("keeps a reserve for high priority requests", func() {
+		budget.available = 1 + defaultRequestLimit*lowPriorityReserve
+
+		Expect(budget.take(requestPriorityLow)).To(BeZero())
+		Expect(budget.take(requestPriorityLow)).To(Equal(time.Second))
+		Expect(budget.take(requestPriorityHigh)).To(BeZero())
+	})
+
+	It("refills over time", func() {
+		budget.available = 0
+		Expect(budget.take(requestPriorityHigh)).To(Equal(time.Second))
+
+		now = now.Add(time.Second)
+		Expect(budget.take(requestPriorityHigh)).To(BeZero())
+	})
+
+	It("is corrected by the rate limit headers", func() {
+		budget.update(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Ratelimit-Limit": []string{"100"}, "Ratelimit-Remaining": []string{"20"}},
+		})
+		Expect(budget.limit).To(Equal(100.0))
+		Expect(budget.available).To(Equal(20.0))
+
+		budget.update(&http.Response{StatusCode: http.StatusTooManyRequests})
+		Expect(budget.available).To(BeZero())
+	})
+
+	It("rejects requests that would have to wait too long", func() {
+		budget.available = 0
+		Expect(budget.wait(context.Background(), requestPriorityLow)).To(MatchError(errRequestBudgetExhausted))
+	})
+})
+
+var _ = Describe("rateLimitTransport", func() {
+	var (
+		budget    *requestBudget
+		transport *rateLimitTransport
+		requests  int
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		budget = newRequestBudget("test", time.Now)
+		transport = &rateLimitTransport{
+			roundTripper: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				requests++
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Ratelimit-Remaining": []string{"0"}},
+					Body:       http.NoBody,
+				}, nil
+			}),
+			budget: budget,
+		}
+	})
+
+	It("answers with a rate limit error once the budget is exhausted", func() {
+		req, err := http.NewRequest(http.MethodGet, "https://api.hetzner.cloud/v1/servers", http.NoBody)
+		Expect(err).To(Succeed())
+
+		resp, err := transport.RoundTrip(req)
+		Expect(err).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp, err = transport.RoundTrip(req)
+		Expect(err).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).To(Succeed())
+		Expect(string(body)).To(ContainSubstring("rate_limit_exceeded"))
+
+		Expect(requests).To(Equal(1))
+	})
+})
+
+var _ = Describe("requestBudgetFor", func() {
+	It("shares the budget of a token", func() {
+		Expect(requestBudgetFor("token-a")).To(BeIdenticalTo(requestBudgetFor("token-a")))
+		Expect(requestBudgetFor("token-a")).ToNot(BeIdenticalTo(requestBudgetFor("token-b")))
+	})
+
+	It("removes budgets that have not been used for a while", func() {
+		idle := requestBudgetFor("token-idle")
+		used := requestBudgetFor("token-used")
+		now := time.Now()
+		idle.last = now.Add(-requestBudgetIdleTimeout - time.Minute)
+
+		requestBudgetsMutex.Lock()
+		evictIdleRequestBudgets("", now)
+		requestBudgetsMutex.Unlock()
+
+		Expect(requestBudgetFor("token-idle")).ToNot(BeIdenticalTo(idle))
+		Expect(requestBudgetFor("token-used")).To(BeIdenticalTo(used))
+	})
+})
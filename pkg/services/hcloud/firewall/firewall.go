@@ -0,0 +1,381 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package firewall implements the lifecycle of HCloud firewalls.
+package firewall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// Service struct contains cluster scope to reconcile firewalls.
+type Service struct {
+	scope *scope.ClusterScope
+}
+
+// NewService creates new service object.
+func NewService(scope *scope.ClusterScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile implements life cycle of firewalls.
+func (s *Service) Reconcile(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				s.scope.HetznerCluster,
+				infrav1.FirewallsSyncedCondition,
+				infrav1.FirewallsSyncFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	firewallsSpec := s.scope.HetznerCluster.Spec.HCloudFirewalls
+
+	// nothing to do if no firewalls are configured and none have been created before
+	if len(firewallsSpec) == 0 && len(s.scope.HetznerCluster.Status.HCloudFirewalls) == 0 {
+		return nil
+	}
+
+	// find firewalls
+	firewalls, err := s.findFirewalls(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find firewalls: %w", err)
+	}
+
+	// Create arrays and maps to make diff
+	firewallNamesExisting := make([]string, len(firewalls))
+	firewallNamesDesired := make([]string, len(firewallsSpec))
+	firewallExistingMap := make(map[string]*hcloud.Firewall)
+	firewallDesiredMap := make(map[string]infrav1.HCloudFirewallSpec)
+
+	for i, fwSpec := range firewallsSpec {
+		firewallNamesDesired[i] = fwSpec.Name
+		firewallDesiredMap[fwSpec.Name] = firewallsSpec[i]
+	}
+
+	for i, fw := range firewalls {
+		name := strings.TrimPrefix(fw.Name, s.scope.HetznerCluster.Name+"-")
+		firewallNamesExisting[i] = name
+		firewallExistingMap[name] = fw
+	}
+
+	// make diff of existing and desired firewalls
+	toCreate, toDelete := utils.DifferenceOfStringSlices(firewallNamesDesired, firewallNamesExisting)
+
+	var multierr error
+
+	// create new firewalls
+	for _, fwName := range toCreate {
+		rules, err := hcloudFirewallRules(firewallDesiredMap[fwName].Rules)
+		if err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("invalid rules of firewall %q: %w", fwName, err))
+			continue
+		}
+
+		opts := hcloud.FirewallCreateOpts{
+			Name:    fmt.Sprintf("%s-%s", s.scope.HetznerCluster.Name, fwName),
+			Labels:  map[string]string{s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned)},
+			Rules:   rules,
+			ApplyTo: []hcloud.FirewallResource{s.labelSelectorResource(firewallDesiredMap[fwName])},
+		}
+
+		if _, err := s.scope.HCloudClient.CreateFirewall(ctx, opts); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "CreateFirewall")
+			multierr = errors.Join(multierr, fmt.Errorf("failed to create firewall %q: %w", fwName, err))
+			continue
+		}
+		record.Eventf(s.scope.HetznerCluster, "FirewallCreated", "Created firewall %s", opts.Name)
+	}
+
+	// update existing firewalls
+	for fwName, fw := range firewallExistingMap {
+		fwSpec, found := firewallDesiredMap[fwName]
+		if !found {
+			continue
+		}
+		if err := s.updateFirewall(ctx, fw, fwSpec); err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("failed to update firewall %q: %w", fwName, err))
+		}
+	}
+
+	// delete old firewalls
+	for _, fwName := range toDelete {
+		if err := s.deleteFirewall(ctx, firewallExistingMap[fwName]); err != nil {
+			multierr = errors.Join(multierr, fmt.Errorf("failed to delete firewall %q: %w", fwName, err))
+		}
+	}
+
+	if multierr != nil {
+		return fmt.Errorf("aggregate error - creating/updating/deleting firewalls: %w", multierr)
+	}
+
+	// Update status
+	if len(toCreate) > 0 || len(toDelete) > 0 {
+		// No need to update status if nothing changed
+		firewalls, err = s.findFirewalls(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find firewalls: %w", err)
+	}
+
+	s.scope.HetznerCluster.Status.HCloudFirewalls = statusFromHCloudFirewalls(firewalls, s.scope.HetznerCluster.Name)
+	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.FirewallsSyncedCondition)
+
+	return nil
+}
+
+// Delete implements deletion of firewalls.
+func (s *Service) Delete(ctx context.Context) error {
+	if len(s.scope.HetznerCluster.Status.HCloudFirewalls) == 0 {
+		return nil
+	}
+
+	firewalls, err := s.findFirewalls(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find firewalls: %w", err)
+	}
+
+	statusIDs := make(map[int64]struct{}, len(s.scope.HetznerCluster.Status.HCloudFirewalls))
+	for _, fw := range s.scope.HetznerCluster.Status.HCloudFirewalls {
+		statusIDs[fw.ID] = struct{}{}
+	}
+
+	var multierr error
+	deleted := 0
+	for _, firewall := range firewalls {
+		if _, found := statusIDs[firewall.ID]; !found {
+			continue
+		}
+		if err := s.deleteFirewall(ctx, firewall); err != nil {
+			multierr = errors.Join(multierr, err)
+			continue
+		}
+		deleted++
+	}
+
+	if multierr != nil {
+		return fmt.Errorf("aggregate error - deleting firewalls: %w", multierr)
+	}
+
+	if deleted > 0 {
+		record.Eventf(s.scope.HetznerCluster, "FirewallsDeleted", "Deleted %d firewalls", deleted)
+	}
+
+	return nil
+}
+
+// updateFirewall updates rules and applied resources of an existing firewall if they differ from the spec.
+func (s *Service) updateFirewall(ctx context.Context, firewall *hcloud.Firewall, fwSpec infrav1.HCloudFirewallSpec) error {
+	rules, err := hcloudFirewallRules(fwSpec.Rules)
+	if err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+
+	if !equalFirewallRules(firewall.Rules, rules) {
+		if err := s.scope.HCloudClient.SetFirewallRules(ctx, firewall, rules); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "SetFirewallRules")
+			return fmt.Errorf("failed to set rules: %w", err)
+		}
+		record.Eventf(s.scope.HetznerCluster, "FirewallRulesUpdated", "Updated rules of firewall %s", firewall.Name)
+	}
+
+	desired := s.labelSelectorResource(fwSpec)
+
+	// remove resources the firewall should not be applied to anymore
+	var toRemove []hcloud.FirewallResource
+	applied := false
+	for _, resource := range firewall.AppliedTo {
+		if resource.Type == hcloud.FirewallResourceTypeLabelSelector && resource.LabelSelector != nil &&
+			resource.LabelSelector.Selector == desired.LabelSelector.Selector {
+			applied = true
+			continue
+		}
+		toRemove = append(toRemove, resource)
+	}
+
+	if !applied {
+		if err := s.scope.HCloudClient.ApplyFirewallToResources(ctx, firewall, []hcloud.FirewallResource{desired}); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ApplyFirewallToResources")
+			if !hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyApplied) {
+				return fmt.Errorf("failed to apply firewall to resources: %w", err)
+			}
+		}
+	}
+
+	if len(toRemove) > 0 {
+		if err := s.scope.HCloudClient.RemoveFirewallFromResources(ctx, firewall, toRemove); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "RemoveFirewallFromResources")
+			if !hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyRemoved) {
+				return fmt.Errorf("failed to remove firewall from resources: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteFirewall removes the firewall from all resources and deletes it afterwards. A firewall that is still
+// applied to resources cannot be deleted.
+func (s *Service) deleteFirewall(ctx context.Context, firewall *hcloud.Firewall) error {
+	if len(firewall.AppliedTo) > 0 {
+		if err := s.scope.HCloudClient.RemoveFirewallFromResources(ctx, firewall, firewall.AppliedTo); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "RemoveFirewallFromResources")
+			if !hcloud.IsError(err, hcloud.ErrorCodeFirewallAlreadyRemoved) && !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				return fmt.Errorf("failed to remove firewall %v from resources: %w", firewall.ID, err)
+			}
+		}
+	}
+
+	if err := s.scope.HCloudClient.DeleteFirewall(ctx, firewall); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteFirewall")
+		if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			return fmt.Errorf("failed to delete firewall %v: %w", firewall.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) findFirewalls(ctx context.Context) ([]*hcloud.Firewall, error) {
+	clusterTagKey := s.scope.HetznerCluster.ClusterTagKey()
+	labels := map[string]string{clusterTagKey: string(infrav1.ResourceLifecycleOwned)}
+	opts := hcloud.FirewallListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(labels)
+
+	firewalls, err := s.scope.HCloudClient.ListFirewalls(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListFirewalls")
+		return nil, fmt.Errorf("failed to list firewalls: %w", err)
+	}
+	return firewalls, nil
+}
+
+// labelSelectorResource returns the resource the firewall is applied to. Only servers of the cluster are selected.
+func (s *Service) labelSelectorResource(fwSpec infrav1.HCloudFirewallSpec) hcloud.FirewallResource {
+	selector := fmt.Sprintf("%s==%s", s.scope.HetznerCluster.ClusterTagKey(), infrav1.ResourceLifecycleOwned)
+	if fwSpec.LabelSelector != nil && *fwSpec.LabelSelector != "" {
+		selector = fmt.Sprintf("%s,%s", selector, *fwSpec.LabelSelector)
+	}
+
+	return hcloud.FirewallResource{
+		Type:          hcloud.FirewallResourceTypeLabelSelector,
+		LabelSelector: &hcloud.FirewallResourceLabelSelector{Selector: selector},
+	}
+}
+
+// hcloudFirewallRules converts the rules of the spec to rules of the HCloud API.
+func hcloudFirewallRules(rules []infrav1.HCloudFirewallRule) ([]hcloud.FirewallRule, error) {
+	hcloudRules := make([]hcloud.FirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		sourceIPs, err := parseCIDRs(rule.SourceIPs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source IPs: %w", err)
+		}
+		destinationIPs, err := parseCIDRs(rule.DestinationIPs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination IPs: %w", err)
+		}
+
+		hcloudRules = append(hcloudRules, hcloud.FirewallRule{
+			Direction:      hcloud.FirewallRuleDirection(rule.Direction),
+			Protocol:       hcloud.FirewallRuleProtocol(rule.Protocol),
+			Port:           rule.Port,
+			SourceIPs:      sourceIPs,
+			DestinationIPs: destinationIPs,
+			Description:    rule.Description,
+		})
+	}
+	return hcloudRules, nil
+}
+
+func parseCIDRs(cidrs []string) ([]net.IPNet, error) {
+	ipNets := make([]net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CIDR %q: %w", cidr, err)
+		}
+		ipNets = append(ipNets, *ipNet)
+	}
+	return ipNets, nil
+}
+
+// equalFirewallRules compares two lists of firewall rules. The order of the rules is relevant.
+func equalFirewallRules(a, b []hcloud.FirewallRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if firewallRuleString(a[i]) != firewallRuleString(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func firewallRuleString(rule hcloud.FirewallRule) string {
+	sourceIPs := make([]string, len(rule.SourceIPs))
+	for i := range rule.SourceIPs {
+		sourceIPs[i] = rule.SourceIPs[i].String()
+	}
+	destinationIPs := make([]string, len(rule.DestinationIPs))
+	for i := range rule.DestinationIPs {
+		destinationIPs[i] = rule.DestinationIPs[i].String()
+	}
+
+	var port, description string
+	if rule.Port != nil {
+		port = *rule.Port
+	}
+	if rule.Description != nil {
+		description = *rule.Description
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", rule.Direction, rule.Protocol, port,
+		strings.Join(sourceIPs, ","), strings.Join(destinationIPs, ","), description)
+}
+
+// statusFromHCloudFirewalls gets the information of the Hetzner firewalls and returns it in our status object.
+func statusFromHCloudFirewalls(firewalls []*hcloud.Firewall, clusterName string) []infrav1.HCloudFirewallStatus {
+	status := make([]infrav1.HCloudFirewallStatus, len(firewalls))
+	for i, fw := range firewalls {
+		status[i] = infrav1.HCloudFirewallStatus{
+			ID:   fw.ID,
+			Name: strings.TrimPrefix(fw.Name, clusterName+"-"),
+		}
+	}
+	return status
+}
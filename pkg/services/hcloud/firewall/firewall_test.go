@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firewall
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("Reconcile", func() {
+	var (
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+	)
+	client := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	BeforeEach(func() {
+		client.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{
+			Spec: infrav1.HetznerClusterSpec{
+				HCloudFirewalls: []infrav1.HCloudFirewallSpec{
+					{
+						Name: "control-plane",
+						Rules: []infrav1.HCloudFirewallRule{
+							{
+								Direction: "in",
+								Protocol:  "tcp",
+								Port:      ptr.To("6443"),
+								SourceIPs: []string{"0.0.0.0/0", "::/0"},
+							},
+						},
+						LabelSelector: ptr.To("machine_type==control_plane"),
+					},
+				},
+			},
+		}
+		hetznerCluster.Name = "hetzner-cluster"
+
+		service = NewService(&scope.ClusterScope{
+			HetznerCluster: hetznerCluster,
+			HCloudClient:   client,
+		})
+	})
+
+	It("creates firewalls applied to servers of the cluster", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.FirewallsSyncedCondition)).To(BeTrue())
+		Expect(hetznerCluster.Status.HCloudFirewalls).To(HaveLen(1))
+		Expect(hetznerCluster.Status.HCloudFirewalls[0].Name).To(Equal("control-plane"))
+
+		firewalls, err := client.ListFirewalls(context.Background(), hcloud.FirewallListOpts{Name: "hetzner-cluster-control-plane"})
+		Expect(err).To(Succeed())
+		Expect(firewalls).To(HaveLen(1))
+		Expect(firewalls[0].Rules).To(HaveLen(1))
+		Expect(firewalls[0].Rules[0].SourceIPs).To(HaveLen(2))
+		Expect(firewalls[0].AppliedTo).To(HaveLen(1))
+		Expect(firewalls[0].AppliedTo[0].LabelSelector.Selector).To(Equal("caph-cluster-hetzner-cluster==owned,machine_type==control_plane"))
+	})
+
+	It("updates rules and label selector of existing firewalls", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		hetznerCluster.Spec.HCloudFirewalls[0].Rules[0].Port = ptr.To("443")
+		hetznerCluster.Spec.HCloudFirewalls[0].LabelSelector = nil
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		firewalls, err := client.ListFirewalls(context.Background(), hcloud.FirewallListOpts{Name: "hetzner-cluster-control-plane"})
+		Expect(err).To(Succeed())
+		Expect(firewalls).To(HaveLen(1))
+		Expect(*firewalls[0].Rules[0].Port).To(Equal("443"))
+		Expect(firewalls[0].AppliedTo).To(HaveLen(1))
+		Expect(firewalls[0].AppliedTo[0].LabelSelector.Selector).To(Equal("caph-cluster-hetzner-cluster==owned"))
+	})
+
+	It("deletes firewalls that are removed from the spec", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		hetznerCluster.Spec.HCloudFirewalls = nil
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		Expect(hetznerCluster.Status.HCloudFirewalls).To(BeEmpty())
+		firewalls, err := client.ListFirewalls(context.Background(), hcloud.FirewallListOpts{})
+		Expect(err).To(Succeed())
+		Expect(firewalls).To(BeEmpty())
+	})
+
+	It("does nothing if no firewalls are configured", func() {
+		hetznerCluster.Spec.HCloudFirewalls = nil
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		Expect(hetznerCluster.Status.HCloudFirewalls).To(BeEmpty())
+		Expect(conditions.Has(hetznerCluster, infrav1.FirewallsSyncedCondition)).To(BeFalse())
+	})
+
+	It("deletes all firewalls of the cluster", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(service.Delete(context.Background())).To(Succeed())
+
+		firewalls, err := client.ListFirewalls(context.Background(), hcloud.FirewallListOpts{})
+		Expect(err).To(Succeed())
+		Expect(firewalls).To(BeEmpty())
+	})
+})
+
+var _ = Describe("equalFirewallRules", func() {
+	It("ignores the representation of CIDRs", func() {
+		a, err := hcloudFirewallRules([]infrav1.HCloudFirewallRule{{Direction: "in", Protocol: "icmp", SourceIPs: []string{"10.0.0.1/8"}}})
+		Expect(err).To(Succeed())
+		b, err := hcloudFirewallRules([]infrav1.HCloudFirewallRule{{Direction: "in", Protocol: "icmp", SourceIPs: []string{"10.0.0.0/8"}}})
+		Expect(err).To(Succeed())
+		Expect(equalFirewallRules(a, b)).To(BeTrue())
+	})
+
+	It("detects changed rules", func() {
+		a, err := hcloudFirewallRules([]infrav1.HCloudFirewallRule{{Direction: "in", Protocol: "tcp", Port: ptr.To("22"), SourceIPs: []string{"10.0.0.0/8"}}})
+		Expect(err).To(Succeed())
+		b, err := hcloudFirewallRules([]infrav1.HCloudFirewallRule{{Direction: "in", Protocol: "tcp", Port: ptr.To("2222"), SourceIPs: []string{"10.0.0.0/8"}}})
+		Expect(err).To(Succeed())
+		Expect(equalFirewallRules(a, b)).To(BeFalse())
+	})
+})
@@ -0,0 +1,263 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package floatingip implements the lifecycle of the floating IP that is used as control plane endpoint.
+package floatingip
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+)
+
+// HealthCheckInterval is the interval in which the API server behind the floating IP is checked.
+const HealthCheckInterval = 30 * time.Second
+
+var (
+	errFloatingIPNotFound = errors.New("floating IP not found")
+	errMissingRegion      = errors.New("neither region of floating IP nor control plane regions are set")
+)
+
+// floatingIP abstracts floating IPs of HCloud and failover IPs of Hetzner Robot.
+type floatingIP interface {
+	status() *infrav1.ControlPlaneFloatingIPStatus
+	isAssignedTo(candidate) bool
+	assign(context.Context, candidate) error
+}
+
+// candidate is a control plane node the floating IP can be assigned to.
+type candidate struct {
+	machine  *clusterv1.Machine
+	serverID int64
+	ip       string
+}
+
+// Service struct contains cluster scope to reconcile the floating IP of the control plane.
+type Service struct {
+	scope *scope.ClusterScope
+
+	// checkAPIServer returns nil if the API server can be reached via the given endpoint.
+	checkAPIServer func(context.Context, clusterv1.APIEndpoint) error
+}
+
+// NewService creates new service object.
+func NewService(scope *scope.ClusterScope) *Service {
+	s := &Service{
+		scope: scope,
+	}
+	s.checkAPIServer = s.isAPIServerReady
+	return s
+}
+
+// Reconcile makes sure that the floating IP exists and that it is assigned to a healthy control plane node.
+// If the API server cannot be reached via the floating IP, it is moved to another control plane node.
+func (s *Service) Reconcile(ctx context.Context) (err error) {
+	spec := s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP
+	if spec == nil {
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				s.scope.HetznerCluster,
+				infrav1.ControlPlaneFloatingIPReadyCondition,
+				infrav1.ControlPlaneFloatingIPSyncFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	var fip floatingIP
+	if spec.Type == infrav1.ControlPlaneFloatingIPTypeRobot {
+		fip, err = s.getFailoverIP()
+	} else {
+		fip, err = s.getOrCreateHCloudFloatingIP(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	s.scope.HetznerCluster.Status.ControlPlaneFloatingIP = fip.status()
+
+	candidates, err := s.candidates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get control plane nodes: %w", err)
+	}
+
+	// Before the control plane is initialized, there is no API server that could be checked.
+	initialized := conditions.IsTrue(s.scope.Cluster, clusterv1.ControlPlaneInitializedCondition)
+
+	var current *candidate
+	for i := range candidates {
+		if fip.isAssignedTo(candidates[i]) {
+			current = &candidates[i]
+			break
+		}
+	}
+
+	if current != nil {
+		if !initialized {
+			conditions.MarkTrue(s.scope.HetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)
+			return nil
+		}
+		apiErr := s.checkAPIServer(ctx, s.endpoint(fip.status().IP))
+		if apiErr == nil {
+			conditions.MarkTrue(s.scope.HetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)
+			return nil
+		}
+		record.Warnf(s.scope.HetznerCluster, "ControlPlaneFloatingIPUnhealthy",
+			"API server cannot be reached via floating IP %s on machine %s: %s", fip.status().IP, current.machine.Name, apiErr.Error())
+	}
+
+	for _, c := range candidates {
+		if current != nil && c.machine.Name == current.machine.Name {
+			continue
+		}
+		if initialized && (c.ip == "" || s.checkAPIServer(ctx, s.endpoint(c.ip)) != nil) {
+			continue
+		}
+
+		if err := fip.assign(ctx, c); err != nil {
+			return fmt.Errorf("failed to assign floating IP to machine %s: %w", c.machine.Name, err)
+		}
+		s.scope.HetznerCluster.Status.ControlPlaneFloatingIP = fip.status()
+
+		record.Eventf(s.scope.HetznerCluster, "ControlPlaneFloatingIPAssigned", "Assigned floating IP %s to machine %s", fip.status().IP, c.machine.Name)
+		conditions.MarkTrue(s.scope.HetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)
+		return nil
+	}
+
+	conditions.MarkFalse(
+		s.scope.HetznerCluster,
+		infrav1.ControlPlaneFloatingIPReadyCondition,
+		infrav1.ControlPlaneFloatingIPNotAssignedReason,
+		clusterv1.ConditionSeverityWarning,
+		"no healthy control plane node found for floating IP %s",
+		fip.status().IP,
+	)
+	return nil
+}
+
+// Delete deletes the floating IP if it has been created by the controller.
+func (s *Service) Delete(ctx context.Context) error {
+	spec := s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP
+	if spec == nil || spec.Type == infrav1.ControlPlaneFloatingIPTypeRobot {
+		return nil
+	}
+
+	if err := s.deleteHCloudFloatingIP(ctx); err != nil {
+		return err
+	}
+
+	s.scope.HetznerCluster.Status.ControlPlaneFloatingIP = nil
+	return nil
+}
+
+// candidates returns the control plane nodes that the floating IP can be assigned to, sorted by age.
+func (s *Service) candidates(ctx context.Context) ([]candidate, error) {
+	var machineList clusterv1.MachineList
+	if err := s.scope.Client.List(ctx, &machineList,
+		client.InNamespace(s.scope.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: s.scope.Cluster.Name},
+		client.HasLabels{clusterv1.MachineControlPlaneLabel},
+	); err != nil {
+		return nil, err
+	}
+
+	expectedKind := "HCloudMachine"
+	if s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP.Type == infrav1.ControlPlaneFloatingIPTypeRobot {
+		expectedKind = "HetznerBareMetalMachine"
+	}
+
+	candidates := make([]candidate, 0, len(machineList.Items))
+	for i := range machineList.Items {
+		machine := &machineList.Items[i]
+		if !machine.DeletionTimestamp.IsZero() || machine.Spec.InfrastructureRef.Kind != expectedKind {
+			continue
+		}
+
+		c := candidate{
+			machine: machine,
+			ip:      externalIPv4(machine),
+		}
+
+		if expectedKind == "HCloudMachine" {
+			serverID, err := hcloudutil.ServerIDFromProviderID(machine.Spec.ProviderID)
+			if err != nil {
+				continue
+			}
+			c.serverID = serverID
+		} else if c.ip == "" {
+			continue
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.machine.CreationTimestamp.Equal(&b.machine.CreationTimestamp) {
+			return cmp.Compare(a.machine.Name, b.machine.Name)
+		}
+		if a.machine.CreationTimestamp.Before(&b.machine.CreationTimestamp) {
+			return -1
+		}
+		return 1
+	})
+	return candidates, nil
+}
+
+func (s *Service) endpoint(host string) clusterv1.APIEndpoint {
+	return clusterv1.APIEndpoint{
+		Host: host,
+		Port: int32(s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP.Port), //nolint:gosec // Validation for the port range (1 to 65535) is already done via kubebuilder.
+	}
+}
+
+func (s *Service) isAPIServerReady(ctx context.Context, endpoint clusterv1.APIEndpoint) error {
+	clientConfig, err := s.scope.ClientConfigWithAPIEndpoint(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to get client config: %w", err)
+	}
+	return scope.IsControlPlaneReady(ctx, clientConfig)
+}
+
+// externalIPv4 returns the first external IPv4 address of the machine.
+func externalIPv4(machine *clusterv1.Machine) string {
+	for _, address := range machine.Status.Addresses {
+		if address.Type != clusterv1.MachineExternalIP {
+			continue
+		}
+		if ip := net.ParseIP(address.Address); ip != nil && ip.To4() != nil {
+			return address.Address
+		}
+	}
+	return ""
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package floatingip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syself/hrobot-go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakek8sclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	robotmock "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/mocks/robot"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+)
+
+var errAPIServerUnreachable = errors.New("API server unreachable")
+
+func newControlPlaneMachine(name, kind string, serverID int64, ip string) *clusterv1.Machine {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:         "cluster",
+				clusterv1.MachineControlPlaneLabel: "",
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName:       "cluster",
+			InfrastructureRef: corev1.ObjectReference{Kind: kind, Name: name},
+		},
+		Status: clusterv1.MachineStatus{
+			Addresses: clusterv1.MachineAddresses{
+				{Type: clusterv1.MachineExternalIP, Address: "2001:db8::1"},
+				{Type: clusterv1.MachineExternalIP, Address: ip},
+			},
+		},
+	}
+	if serverID != 0 {
+		machine.Spec.ProviderID = ptr.To(hcloudutil.ProviderIDFromServerID(int(serverID)))
+	}
+	return machine
+}
+
+func newService(hetznerCluster *infrav1.HetznerCluster, cluster *clusterv1.Cluster, objects ...client.Object) *Service {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clusterv1.AddToScheme(scheme))
+
+	return NewService(&scope.ClusterScope{
+		Client:         fakek8sclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Cluster:        cluster,
+		HetznerCluster: hetznerCluster,
+		HCloudClient:   fakeclient.NewHCloudClientFactory().NewClient(""),
+	})
+}
+
+// healthyHosts returns a health check that succeeds only for the given hosts.
+func healthyHosts(hosts ...string) func(context.Context, clusterv1.APIEndpoint) error {
+	return func(_ context.Context, endpoint clusterv1.APIEndpoint) error {
+		for _, host := range hosts {
+			if endpoint.Host == host {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s", errAPIServerUnreachable, endpoint.Host)
+	}
+}
+
+var _ = Describe("HCloud floating IP", func() {
+	var (
+		hetznerCluster *infrav1.HetznerCluster
+		cluster        *clusterv1.Cluster
+		servers        []*hcloud.Server
+		machines       []client.Object
+	)
+	hcloudClient := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	BeforeEach(func() {
+		hcloudClient.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"},
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneRegions:    []infrav1.Region{"fsn1"},
+				ControlPlaneFloatingIP: &infrav1.ControlPlaneFloatingIPSpec{Type: infrav1.ControlPlaneFloatingIPTypeHCloud, Port: 6443},
+			},
+		}
+		cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+
+		servers = nil
+		machines = nil
+		for i, name := range []string{"control-plane-a", "control-plane-b"} {
+			server, err := hcloudClient.CreateServer(context.Background(), hcloud.ServerCreateOpts{Name: name})
+			Expect(err).To(Succeed())
+			servers = append(servers, server)
+			machines = append(machines, newControlPlaneMachine(name, "HCloudMachine", server.ID, fmt.Sprintf("192.0.2.%d", i+1)))
+		}
+	})
+
+	It("creates the floating IP and assigns it to the oldest control plane node before the control plane is initialized", func() {
+		service := newService(hetznerCluster, cluster, machines...)
+		service.checkAPIServer = healthyHosts()
+
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)).To(BeTrue())
+		Expect(hetznerCluster.Status.ControlPlaneFloatingIP).ToNot(BeNil())
+		Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ServerID).To(Equal(servers[0].ID))
+
+		floatingIPs, err := hcloudClient.ListFloatingIPs(context.Background(), hcloud.FloatingIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(floatingIPs).To(HaveLen(1))
+		Expect(floatingIPs[0].IP.String()).To(Equal(hetznerCluster.Status.ControlPlaneFloatingIP.IP))
+		Expect(floatingIPs[0].HomeLocation.Name).To(Equal("fsn1"))
+		Expect(floatingIPs[0].Labels).To(HaveKeyWithValue(hetznerCluster.ClusterTagKey(), string(infrav1.ResourceLifecycleOwned)))
+	})
+
+	Context("with initialized control plane", func() {
+		BeforeEach(func() {
+			conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+
+			service := newService(hetznerCluster, &clusterv1.Cluster{ObjectMeta: cluster.ObjectMeta}, machines...)
+			Expect(service.Reconcile(context.Background())).To(Succeed())
+			Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ServerID).To(Equal(servers[0].ID))
+		})
+
+		It("keeps the floating IP on the node if the API server is healthy", func() {
+			service := newService(hetznerCluster, cluster, machines...)
+			service.checkAPIServer = healthyHosts(hetznerCluster.Status.ControlPlaneFloatingIP.IP)
+
+			Expect(service.Reconcile(context.Background())).To(Succeed())
+			Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ServerID).To(Equal(servers[0].ID))
+			Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)).To(BeTrue())
+		})
+
+		It("moves the floating IP to a healthy node if the API server cannot be reached", func() {
+			service := newService(hetznerCluster, cluster, machines...)
+			service.checkAPIServer = healthyHosts("192.0.2.2")
+
+			Expect(service.Reconcile(context.Background())).To(Succeed())
+			Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ServerID).To(Equal(servers[1].ID))
+			Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)).To(BeTrue())
+		})
+
+		It("sets a condition if no control plane node is healthy", func() {
+			service := newService(hetznerCluster, cluster, machines...)
+			service.checkAPIServer = healthyHosts()
+
+			Expect(service.Reconcile(context.Background())).To(Succeed())
+			Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ServerID).To(Equal(servers[0].ID))
+			Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)).To(Equal(infrav1.ControlPlaneFloatingIPNotAssignedReason))
+		})
+	})
+
+	It("gives an error if the referenced floating IP does not exist", func() {
+		hetznerCluster.Spec.ControlPlaneFloatingIP.IP = ptr.To("198.51.100.200")
+		service := newService(hetznerCluster, cluster, machines...)
+
+		Expect(service.Reconcile(context.Background())).To(MatchError(errFloatingIPNotFound))
+		Expect(conditions.GetReason(hetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)).To(Equal(infrav1.ControlPlaneFloatingIPSyncFailedReason))
+	})
+
+	It("deletes the floating IP that has been created for the cluster", func() {
+		service := newService(hetznerCluster, cluster, machines...)
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		Expect(service.Delete(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.ControlPlaneFloatingIP).To(BeNil())
+
+		floatingIPs, err := hcloudClient.ListFloatingIPs(context.Background(), hcloud.FloatingIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(floatingIPs).To(BeEmpty())
+	})
+
+	It("does not delete a referenced floating IP", func() {
+		floatingIP, err := hcloudClient.CreateFloatingIP(context.Background(), hcloud.FloatingIPCreateOpts{Type: hcloud.FloatingIPTypeIPv4})
+		Expect(err).To(Succeed())
+
+		hetznerCluster.Spec.ControlPlaneFloatingIP.IP = ptr.To(floatingIP.IP.String())
+		service := newService(hetznerCluster, cluster, machines...)
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ID).To(Equal(floatingIP.ID))
+
+		Expect(service.Delete(context.Background())).To(Succeed())
+
+		floatingIPs, err := hcloudClient.ListFloatingIPs(context.Background(), hcloud.FloatingIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(floatingIPs).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Robot failover IP", func() {
+	var (
+		hetznerCluster *infrav1.HetznerCluster
+		cluster        *clusterv1.Cluster
+		robotClient    *robotmock.Client
+		service        *Service
+	)
+
+	BeforeEach(func() {
+		hetznerCluster = &infrav1.HetznerCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hetzner-cluster", Namespace: "default"},
+			Spec: infrav1.HetznerClusterSpec{
+				ControlPlaneFloatingIP: &infrav1.ControlPlaneFloatingIPSpec{
+					Type: infrav1.ControlPlaneFloatingIPTypeRobot,
+					IP:   ptr.To("198.51.100.1"),
+					Port: 6443,
+				},
+			},
+		}
+		cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+		conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+
+		robotClient = &robotmock.Client{}
+		robotClient.On("GetFailoverIP", "198.51.100.1").Return(&models.Failover{IP: "198.51.100.1", ActiveServerIP: "192.0.2.1"}, nil)
+
+		service = newService(hetznerCluster, cluster,
+			newControlPlaneMachine("bm-control-plane-a", "HetznerBareMetalMachine", 0, "192.0.2.1"),
+			newControlPlaneMachine("bm-control-plane-b", "HetznerBareMetalMachine", 0, "192.0.2.2"),
+			// HCloud machines cannot get a failover IP
+			newControlPlaneMachine("control-plane-c", "HCloudMachine", 1, "192.0.2.3"),
+		)
+		service.scope.RobotClient = robotClient
+	})
+
+	It("keeps the failover IP on the server if the API server is healthy", func() {
+		service.checkAPIServer = healthyHosts("198.51.100.1")
+
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ActiveServerIP).To(Equal("192.0.2.1"))
+		robotClient.AssertNotCalled(GinkgoT(), "SetFailoverIP", "198.51.100.1", "192.0.2.2")
+	})
+
+	It("routes the failover IP to a healthy bare metal server if the API server cannot be reached", func() {
+		robotClient.On("SetFailoverIP", "198.51.100.1", "192.0.2.2").Return(&models.Failover{IP: "198.51.100.1", ActiveServerIP: "192.0.2.2"}, nil)
+		service.checkAPIServer = healthyHosts("192.0.2.2", "192.0.2.3")
+
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+		Expect(hetznerCluster.Status.ControlPlaneFloatingIP.ActiveServerIP).To(Equal("192.0.2.2"))
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.ControlPlaneFloatingIPReadyCondition)).To(BeTrue())
+		robotClient.AssertNumberOfCalls(GinkgoT(), "SetFailoverIP", 1)
+	})
+
+	It("gives an error without robot client", func() {
+		service.scope.RobotClient = nil
+		Expect(service.Reconcile(context.Background())).To(MatchError(errMissingRobotClient))
+	})
+})
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package floatingip
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	hcloudclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// hcloudFloatingIP is a floating IP of HCloud that is assigned to HCloud servers.
+type hcloudFloatingIP struct {
+	client     hcloudclient.Client
+	floatingIP *hcloud.FloatingIP
+}
+
+var _ floatingIP = &hcloudFloatingIP{}
+
+func (f *hcloudFloatingIP) status() *infrav1.ControlPlaneFloatingIPStatus {
+	status := &infrav1.ControlPlaneFloatingIPStatus{
+		ID: f.floatingIP.ID,
+		IP: f.floatingIP.IP.String(),
+	}
+	if f.floatingIP.Server != nil {
+		status.ServerID = f.floatingIP.Server.ID
+	}
+	return status
+}
+
+func (f *hcloudFloatingIP) isAssignedTo(c candidate) bool {
+	return f.floatingIP.Server != nil && f.floatingIP.Server.ID == c.serverID
+}
+
+func (f *hcloudFloatingIP) assign(ctx context.Context, c candidate) error {
+	server := &hcloud.Server{ID: c.serverID}
+	if err := f.client.AssignFloatingIP(ctx, f.floatingIP, server); err != nil {
+		return err
+	}
+	f.floatingIP.Server = server
+	return nil
+}
+
+func (s *Service) getOrCreateHCloudFloatingIP(ctx context.Context) (*hcloudFloatingIP, error) {
+	floatingIP, err := s.findHCloudFloatingIP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if floatingIP == nil {
+		if s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP.IP != nil {
+			return nil, fmt.Errorf("%w: %s", errFloatingIPNotFound, *s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP.IP)
+		}
+
+		floatingIP, err = s.createHCloudFloatingIP(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create floating IP: %w", err)
+		}
+	}
+
+	return &hcloudFloatingIP{
+		client:     s.scope.HCloudClient,
+		floatingIP: floatingIP,
+	}, nil
+}
+
+// findHCloudFloatingIP returns the floating IP given in the spec or the one that has been created for the cluster.
+func (s *Service) findHCloudFloatingIP(ctx context.Context) (*hcloud.FloatingIP, error) {
+	opts := hcloud.FloatingIPListOpts{}
+
+	ip := s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP.IP
+	if ip == nil {
+		labels := map[string]string{
+			s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+		}
+		opts.LabelSelector = utils.LabelsToLabelSelector(labels)
+	}
+
+	floatingIPs, err := s.scope.HCloudClient.ListFloatingIPs(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListFloatingIPs")
+		return nil, fmt.Errorf("failed to list floating IPs: %w", err)
+	}
+
+	for _, floatingIP := range floatingIPs {
+		if ip == nil || floatingIP.IP.String() == *ip {
+			return floatingIP, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Service) createHCloudFloatingIP(ctx context.Context) (*hcloud.FloatingIP, error) {
+	hc := s.scope.HetznerCluster
+
+	region := hc.Spec.ControlPlaneFloatingIP.Region
+	if region == "" && len(hc.Spec.ControlPlaneRegions) > 0 {
+		region = hc.Spec.ControlPlaneRegions[0]
+	}
+	if region == "" {
+		return nil, errMissingRegion
+	}
+
+	name := fmt.Sprintf("%s-control-plane", hc.Name)
+	description := fmt.Sprintf("control plane endpoint of cluster %s", hc.Name)
+	opts := hcloud.FloatingIPCreateOpts{
+		Type:         hcloud.FloatingIPTypeIPv4,
+		HomeLocation: &hcloud.Location{Name: string(region)},
+		Name:         &name,
+		Description:  &description,
+		Labels: map[string]string{
+			hc.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+		},
+	}
+
+	floatingIP, err := s.scope.HCloudClient.CreateFloatingIP(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(hc, err, "CreateFloatingIP")
+		record.Warnf(hc, "FailedCreateFloatingIP", "Failed to create floating IP: %s", err)
+		return nil, err
+	}
+
+	record.Eventf(hc, "SuccessfulCreateFloatingIP", "Created floating IP %s", floatingIP.IP.String())
+	return floatingIP, nil
+}
+
+// deleteHCloudFloatingIP deletes the floating IP if it has been created for the cluster.
+func (s *Service) deleteHCloudFloatingIP(ctx context.Context) error {
+	if s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP.IP != nil {
+		return nil
+	}
+
+	floatingIP, err := s.findHCloudFloatingIP(ctx)
+	if err != nil {
+		return err
+	}
+	if floatingIP == nil {
+		return nil
+	}
+
+	if floatingIP.Server != nil {
+		if err := s.scope.HCloudClient.UnassignFloatingIP(ctx, floatingIP); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "UnassignFloatingIP")
+			if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				return fmt.Errorf("failed to unassign floating IP %v: %w", floatingIP.ID, err)
+			}
+		}
+	}
+
+	if err := s.scope.HCloudClient.DeleteFloatingIP(ctx, floatingIP); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeleteFloatingIP")
+		if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			return fmt.Errorf("failed to delete floating IP %v: %w", floatingIP.ID, err)
+		}
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "SuccessfulDeleteFloatingIP", "Deleted floating IP %s", floatingIP.IP.String())
+	return nil
+}
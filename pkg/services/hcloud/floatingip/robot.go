@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package floatingip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/syself/hrobot-go/models"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	robotclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/baremetal/client/robot"
+)
+
+var errMissingRobotClient = errors.New("missing robot client")
+
+// robotFailoverIP is a failover IP of Hetzner Robot that is routed to bare metal servers.
+type robotFailoverIP struct {
+	client   robotclient.Client
+	failover *models.Failover
+}
+
+var _ floatingIP = &robotFailoverIP{}
+
+func (f *robotFailoverIP) status() *infrav1.ControlPlaneFloatingIPStatus {
+	return &infrav1.ControlPlaneFloatingIPStatus{
+		IP:             f.failover.IP,
+		ActiveServerIP: f.failover.ActiveServerIP,
+	}
+}
+
+func (f *robotFailoverIP) isAssignedTo(c candidate) bool {
+	return f.failover.ActiveServerIP != "" && f.failover.ActiveServerIP == c.ip
+}
+
+func (f *robotFailoverIP) assign(_ context.Context, c candidate) error {
+	failover, err := f.client.SetFailoverIP(f.failover.IP, c.ip)
+	if err != nil {
+		return err
+	}
+	f.failover = failover
+	return nil
+}
+
+func (s *Service) getFailoverIP() (*robotFailoverIP, error) {
+	if s.scope.RobotClient == nil {
+		return nil, errMissingRobotClient
+	}
+
+	ip := *s.scope.HetznerCluster.Spec.ControlPlaneFloatingIP.IP
+	failover, err := s.scope.RobotClient.GetFailoverIP(ip)
+	if err != nil {
+		if models.IsError(err, models.ErrorCodeNotFound) {
+			return nil, fmt.Errorf("%w: %s", errFloatingIPNotFound, ip)
+		}
+		return nil, fmt.Errorf("failed to get failover IP %s: %w", ip, err)
+	}
+
+	return &robotFailoverIP{
+		client:   s.scope.RobotClient,
+		failover: failover,
+	}, nil
+}
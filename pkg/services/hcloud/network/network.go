@@ -80,6 +80,10 @@ func (s *Service) Reconcile(ctx context.Context) (err error) {
 		}
 	}
 
+	if err := s.reconcileVSwitchSubnet(ctx, network); err != nil {
+		return fmt.Errorf("failed to reconcile vSwitch subnet: %w", err)
+	}
+
 	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.NetworkReadyCondition)
 	s.scope.HetznerCluster.Status.Network = statusFromHCloudNetwork(network)
 
@@ -130,6 +134,43 @@ func (s *Service) createOpts() (hcloud.NetworkCreateOpts, error) {
 	}, nil
 }
 
+// reconcileVSwitchSubnet links the vSwitch of the cluster with the network by adding a subnet of type vswitch.
+func (s *Service) reconcileVSwitchSubnet(ctx context.Context, network *hcloud.Network) error {
+	spec := s.scope.HetznerCluster.Spec.HCloudNetwork.VSwitch
+	status := s.scope.HetznerCluster.Status.VSwitch
+	if spec == nil || status == nil {
+		// the vSwitch is not configured or does not exist yet
+		return nil
+	}
+
+	for _, subnet := range network.Subnets {
+		if subnet.Type == hcloud.NetworkSubnetTypeVSwitch && subnet.VSwitchID == int64(status.ID) {
+			return nil
+		}
+	}
+
+	_, ipRange, err := net.ParseCIDR(spec.SubnetCIDRBlock)
+	if err != nil {
+		return fmt.Errorf("invalid network %q: %w", spec.SubnetCIDRBlock, err)
+	}
+
+	subnet := hcloud.NetworkSubnet{
+		Type:        hcloud.NetworkSubnetTypeVSwitch,
+		IPRange:     ipRange,
+		NetworkZone: hcloud.NetworkZone(s.scope.HetznerCluster.Spec.HCloudNetwork.NetworkZone),
+		VSwitchID:   int64(status.ID),
+	}
+
+	if err := s.scope.HCloudClient.AddSubnetToNetwork(ctx, network, hcloud.NetworkAddSubnetOpts{Subnet: subnet}); err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "AddSubnetToNetwork")
+		record.Warnf(s.scope.HetznerCluster, "VSwitchSubnetAddFailed", "Failed to add subnet for vSwitch %d to network %d", status.ID, network.ID)
+		return fmt.Errorf("failed to add subnet to network: %w", err)
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "VSwitchSubnetAdded", "Added subnet for vSwitch %d to network %d", status.ID, network.ID)
+	return nil
+}
+
 // Delete implements deletion of the network.
 func (s *Service) Delete(ctx context.Context) error {
 	if s.scope.HetznerCluster.Status.Network == nil {
@@ -172,7 +213,14 @@ func (s *Service) findNetwork(ctx context.Context) (*hcloud.Network, error) {
 		return nil, nil
 	}
 
-	if len(networks[0].Subnets) > 1 {
+	// the subnet of the vSwitch is managed separately
+	var cloudSubnets int
+	for _, subnet := range networks[0].Subnets {
+		if subnet.Type != hcloud.NetworkSubnetTypeVSwitch {
+			cloudSubnets++
+		}
+	}
+	if cloudSubnets > 1 {
 		return nil, fmt.Errorf("multiple subnets not allowed")
 	}
 
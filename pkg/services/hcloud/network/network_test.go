@@ -17,6 +17,7 @@ limitations under the License.
 package network
 
 import (
+	"context"
 	"net"
 	"testing"
 
@@ -26,6 +27,7 @@ import (
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
 )
 
 func TestNetwork(t *testing.T) {
@@ -84,3 +86,56 @@ var _ = Describe("Test createOpts", func() {
 		Expect(err).ToNot(BeNil())
 	})
 })
+
+var _ = Describe("Test reconcileVSwitchSubnet", func() {
+	var hetznerCluster infrav1.HetznerCluster
+	var service Service
+	var network *hcloud.Network
+	hcloudClient := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	BeforeEach(func() {
+		hcloudClient.Reset()
+
+		hetznerCluster = infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Spec.HCloudNetwork = infrav1.HCloudNetworkSpec{
+			Enabled:         true,
+			CIDRBlock:       "10.0.0.0/16",
+			SubnetCIDRBlock: "10.0.0.0/24",
+			NetworkZone:     "eu-central",
+			VSwitch: &infrav1.HCloudNetworkVSwitchSpec{
+				VLANID:          4000,
+				SubnetCIDRBlock: "10.0.1.0/24",
+			},
+		}
+		hetznerCluster.Status.VSwitch = &infrav1.VSwitchStatus{ID: 42}
+
+		service = Service{&scope.ClusterScope{HetznerCluster: &hetznerCluster, HCloudClient: hcloudClient}}
+
+		opts, err := service.createOpts()
+		Expect(err).To(BeNil())
+		network, err = hcloudClient.CreateNetwork(context.Background(), opts)
+		Expect(err).To(BeNil())
+	})
+
+	It("adds the subnet of the vSwitch only once", func() {
+		Expect(service.reconcileVSwitchSubnet(context.Background(), network)).To(Succeed())
+		Expect(service.reconcileVSwitchSubnet(context.Background(), network)).To(Succeed())
+
+		found, err := service.findNetwork(context.Background())
+		Expect(err).To(BeNil())
+		Expect(found.Subnets).To(HaveLen(2))
+		Expect(found.Subnets[1].Type).To(Equal(hcloud.NetworkSubnetTypeVSwitch))
+		Expect(found.Subnets[1].VSwitchID).To(Equal(int64(42)))
+		Expect(found.Subnets[1].IPRange.String()).To(Equal("10.0.1.0/24"))
+	})
+
+	It("does nothing if the vSwitch does not exist yet", func() {
+		hetznerCluster.Status.VSwitch = nil
+		Expect(service.reconcileVSwitchSubnet(context.Background(), network)).To(Succeed())
+
+		found, err := service.findNetwork(context.Background())
+		Expect(err).To(BeNil())
+		Expect(found.Subnets).To(HaveLen(1))
+	})
+})
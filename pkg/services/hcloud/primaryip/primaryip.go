@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package primaryip implements the lifecycle of the pool of HCloud primary IPs of a cluster.
+package primaryip
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// Service struct contains cluster scope to reconcile the pool of primary IPs.
+type Service struct {
+	scope *scope.ClusterScope
+}
+
+// NewService creates new service object.
+func NewService(scope *scope.ClusterScope) *Service {
+	return &Service{
+		scope: scope,
+	}
+}
+
+// Reconcile updates the status of the pool of primary IPs. Primary IPs are added to the pool by the
+// HCloudMachines when their servers are deleted.
+func (s *Service) Reconcile(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			conditions.MarkFalse(
+				s.scope.HetznerCluster,
+				infrav1.PrimaryIPPoolSyncedCondition,
+				infrav1.PrimaryIPPoolSyncFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+		}
+	}()
+
+	// nothing to do if no machine uses the pool and the pool is empty
+	if len(s.scope.HetznerCluster.Status.HCloudPrimaryIPPool) == 0 {
+		used, err := s.poolUsed(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check whether machines use the primary IP pool: %w", err)
+		}
+		if !used {
+			return nil
+		}
+	}
+
+	primaryIPs, err := s.findPrimaryIPs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find primary IPs: %w", err)
+	}
+
+	s.scope.HetznerCluster.Status.HCloudPrimaryIPPool = statusFromHCloudPrimaryIPs(primaryIPs)
+	conditions.MarkTrue(s.scope.HetznerCluster, infrav1.PrimaryIPPoolSyncedCondition)
+
+	return nil
+}
+
+// Delete deletes all primary IPs of the pool.
+func (s *Service) Delete(ctx context.Context) error {
+	primaryIPs, err := s.findPrimaryIPs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find primary IPs: %w", err)
+	}
+
+	if len(primaryIPs) == 0 {
+		return nil
+	}
+
+	var multierr error
+	for _, primaryIP := range primaryIPs {
+		if err := s.scope.HCloudClient.DeletePrimaryIP(ctx, primaryIP); err != nil {
+			hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "DeletePrimaryIP")
+			if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				multierr = errors.Join(multierr, fmt.Errorf("failed to delete primary IP %v: %w", primaryIP.ID, err))
+			}
+		}
+	}
+
+	if multierr != nil {
+		return fmt.Errorf("aggregate error - deleting primary IPs: %w", multierr)
+	}
+
+	record.Eventf(s.scope.HetznerCluster, "PrimaryIPPoolDeleted", "Deleted %d primary IPs of pool", len(primaryIPs))
+
+	return nil
+}
+
+// poolUsed returns whether any HCloudMachine of the cluster keeps its primary IPs in the pool.
+func (s *Service) poolUsed(ctx context.Context) (bool, error) {
+	var hcloudMachines infrav1.HCloudMachineList
+	if err := s.scope.Client.List(ctx, &hcloudMachines,
+		client.InNamespace(s.scope.HetznerCluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: s.scope.Cluster.Name},
+	); err != nil {
+		return false, fmt.Errorf("failed to list HCloudMachines: %w", err)
+	}
+
+	return slices.ContainsFunc(hcloudMachines.Items, func(hcloudMachine infrav1.HCloudMachine) bool {
+		publicNetwork := hcloudMachine.Spec.PublicNetwork
+		return publicNetwork != nil && publicNetwork.UsePrimaryIPPool
+	}), nil
+}
+
+func (s *Service) findPrimaryIPs(ctx context.Context) ([]*hcloud.PrimaryIP, error) {
+	clusterTagKey := s.scope.HetznerCluster.ClusterTagKey()
+	labels := map[string]string{
+		clusterTagKey:               string(infrav1.ResourceLifecycleOwned),
+		infrav1.PrimaryIPPoolTagKey: infrav1.PrimaryIPPoolControlPlane,
+	}
+	opts := hcloud.PrimaryIPListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(labels)
+
+	primaryIPs, err := s.scope.HCloudClient.ListPrimaryIPs(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HetznerCluster, err, "ListPrimaryIPs")
+		return nil, fmt.Errorf("failed to list primary IPs: %w", err)
+	}
+	return primaryIPs, nil
+}
+
+// statusFromHCloudPrimaryIPs gets the information of the Hetzner primary IPs and returns it in our status object.
+func statusFromHCloudPrimaryIPs(primaryIPs []*hcloud.PrimaryIP) []infrav1.HCloudPrimaryIPStatus {
+	status := make([]infrav1.HCloudPrimaryIPStatus, len(primaryIPs))
+	for i, primaryIP := range primaryIPs {
+		status[i] = infrav1.HCloudPrimaryIPStatus{
+			ID:       primaryIP.ID,
+			IP:       primaryIP.IP.String(),
+			Type:     string(primaryIP.Type),
+			ServerID: primaryIP.AssigneeID,
+		}
+		if primaryIP.Datacenter != nil && primaryIP.Datacenter.Location != nil {
+			status[i].Region = infrav1.Region(primaryIP.Datacenter.Location.Name)
+		}
+	}
+
+	slices.SortFunc(status, func(a, b infrav1.HCloudPrimaryIPStatus) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return status
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package primaryip
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("Primary IP pool", func() {
+	var (
+		hetznerCluster *infrav1.HetznerCluster
+		service        *Service
+	)
+	client := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	BeforeEach(func() {
+		client.Reset()
+
+		hetznerCluster = &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+		hetznerCluster.Namespace = "default"
+
+		scheme := runtime.NewScheme()
+		Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+		hcloudMachine := &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "control-plane",
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster"},
+			},
+			Spec: infrav1.HCloudMachineSpec{
+				PublicNetwork: &infrav1.PublicNetworkSpec{EnableIPv4: true, UsePrimaryIPPool: true},
+			},
+		}
+
+		service = NewService(&scope.ClusterScope{
+			Client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(hcloudMachine).Build(),
+			Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}},
+			HetznerCluster: hetznerCluster,
+			HCloudClient:   client,
+		})
+
+		// the primary IPs of both servers are kept, but only the ones of the first are in the pool
+		for i, name := range []string{"control-plane", "other"} {
+			server, err := client.CreateServer(context.Background(), hcloud.ServerCreateOpts{
+				Name:      name,
+				Location:  &hcloud.Location{Name: "fsn1"},
+				PublicNet: &hcloud.ServerCreatePublicNet{EnableIPv4: true},
+			})
+			Expect(err).To(Succeed())
+
+			labels := map[string]string{}
+			if i == 0 {
+				labels = map[string]string{
+					hetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+					infrav1.PrimaryIPPoolTagKey:    infrav1.PrimaryIPPoolControlPlane,
+				}
+			}
+			autoDelete := false
+			Expect(client.UpdatePrimaryIP(context.Background(), &hcloud.PrimaryIP{ID: server.PublicNet.IPv4.ID}, hcloud.PrimaryIPUpdateOpts{
+				AutoDelete: &autoDelete,
+				Labels:     &labels,
+			})).To(Succeed())
+			Expect(client.DeleteServer(context.Background(), server)).To(Succeed())
+		}
+	})
+
+	It("sets the primary IPs of the pool in status", func() {
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		Expect(conditions.IsTrue(hetznerCluster, infrav1.PrimaryIPPoolSyncedCondition)).To(BeTrue())
+		Expect(hetznerCluster.Status.HCloudPrimaryIPPool).To(HaveLen(1))
+		Expect(hetznerCluster.Status.HCloudPrimaryIPPool[0].Type).To(Equal("ipv4"))
+		Expect(hetznerCluster.Status.HCloudPrimaryIPPool[0].Region).To(Equal(infrav1.Region("fsn1")))
+		Expect(hetznerCluster.Status.HCloudPrimaryIPPool[0].ServerID).To(BeZero())
+	})
+
+	It("does not sync the pool if no machine uses it", func() {
+		service.scope.Cluster.Name = "other-cluster"
+		Expect(service.Reconcile(context.Background())).To(Succeed())
+
+		Expect(conditions.Has(hetznerCluster, infrav1.PrimaryIPPoolSyncedCondition)).To(BeFalse())
+		Expect(hetznerCluster.Status.HCloudPrimaryIPPool).To(BeEmpty())
+	})
+
+	It("deletes only the primary IPs of the pool", func() {
+		Expect(service.Delete(context.Background())).To(Succeed())
+
+		primaryIPs, err := client.ListPrimaryIPs(context.Background(), hcloud.PrimaryIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(primaryIPs).To(HaveLen(1))
+		Expect(primaryIPs[0].Labels).To(BeEmpty())
+	})
+})
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+// usesPrimaryIPPool returns whether the primary IPs of the server are kept in the pool of the cluster.
+func (s *Service) usesPrimaryIPPool() bool {
+	publicNetwork := s.scope.HCloudMachine.Spec.PublicNetwork
+	return publicNetwork != nil && publicNetwork.UsePrimaryIPPool && s.scope.IsControlPlane()
+}
+
+// referencesPrimaryIPs returns whether the spec references existing primary IPs.
+func (s *Service) referencesPrimaryIPs() bool {
+	publicNetwork := s.scope.HCloudMachine.Spec.PublicNetwork
+	return publicNetwork != nil && (publicNetwork.PrimaryIPv4 != nil || publicNetwork.PrimaryIPv6 != nil)
+}
+
+func (s *Service) primaryIPPoolLabels() map[string]string {
+	return map[string]string{
+		s.scope.HetznerCluster.ClusterTagKey(): string(infrav1.ResourceLifecycleOwned),
+		infrav1.PrimaryIPPoolTagKey:            infrav1.PrimaryIPPoolControlPlane,
+	}
+}
+
+// availablePrimaryIPs returns the primary IPs that are referenced in the spec or, if the pool is used, the
+// primary IPs of the pool that are not assigned to any server.
+func (s *Service) availablePrimaryIPs(ctx context.Context) ([]*hcloud.PrimaryIP, error) {
+	publicNetwork := s.scope.HCloudMachine.Spec.PublicNetwork
+	if publicNetwork == nil {
+		return nil, nil
+	}
+
+	var primaryIPs []*hcloud.PrimaryIP
+
+	referenced := []struct {
+		name   *string
+		ipType hcloud.PrimaryIPType
+	}{
+		{name: publicNetwork.PrimaryIPv4, ipType: hcloud.PrimaryIPTypeIPv4},
+		{name: publicNetwork.PrimaryIPv6, ipType: hcloud.PrimaryIPTypeIPv6},
+	}
+	for _, ref := range referenced {
+		if ref.name == nil {
+			continue
+		}
+
+		primaryIP, err := s.referencedPrimaryIP(ctx, *ref.name, ref.ipType)
+		if err != nil {
+			return nil, err
+		}
+		primaryIPs = append(primaryIPs, primaryIP)
+	}
+
+	if s.referencesPrimaryIPs() || !s.usesPrimaryIPPool() {
+		return primaryIPs, nil
+	}
+
+	opts := hcloud.PrimaryIPListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(s.primaryIPPoolLabels())
+
+	pool, err := s.scope.HCloudClient.ListPrimaryIPs(ctx, opts)
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListPrimaryIPs", "failed to list primary IPs of pool")
+	}
+
+	for _, primaryIP := range pool {
+		if primaryIP.AssigneeID == 0 {
+			primaryIPs = append(primaryIPs, primaryIP)
+		}
+	}
+
+	// use the oldest primary IPs first
+	slices.SortFunc(primaryIPs, func(a, b *hcloud.PrimaryIP) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return primaryIPs, nil
+}
+
+func (s *Service) referencedPrimaryIP(ctx context.Context, name string, ipType hcloud.PrimaryIPType) (*hcloud.PrimaryIP, error) {
+	primaryIPs, err := s.scope.HCloudClient.ListPrimaryIPs(ctx, hcloud.PrimaryIPListOpts{Name: name})
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListPrimaryIPs", "failed to list primary IPs")
+	}
+
+	var msg string
+	switch {
+	case len(primaryIPs) == 0:
+		msg = fmt.Sprintf("primary IP %q not found", name)
+	case primaryIPs[0].Type != ipType:
+		msg = fmt.Sprintf("primary IP %q has type %s instead of %s", name, primaryIPs[0].Type, ipType)
+	case primaryIPs[0].AssigneeID != 0:
+		msg = fmt.Sprintf("primary IP %q is already assigned to server with ID %d", name, primaryIPs[0].AssigneeID)
+	default:
+		return primaryIPs[0], nil
+	}
+
+	record.Warnf(s.scope.HCloudMachine, "PrimaryIPNotAvailable", msg)
+	conditions.MarkFalse(
+		s.scope.HCloudMachine,
+		infrav1.ServerCreateSucceededCondition,
+		infrav1.PrimaryIPNotAvailableReason,
+		clusterv1.ConditionSeverityError,
+		"%s",
+		msg,
+	)
+	return nil, errServerCreateNotPossible
+}
+
+// selectPrimaryIPs returns the first primary IPv4 and IPv6 of the given location. Primary IPs can only be
+// assigned to servers in the same location.
+func selectPrimaryIPs(primaryIPs []*hcloud.PrimaryIP, region infrav1.Region) (ipv4, ipv6 *hcloud.PrimaryIP) {
+	for _, primaryIP := range primaryIPs {
+		if primaryIPRegion(primaryIP) != region {
+			continue
+		}
+		if primaryIP.Type == hcloud.PrimaryIPTypeIPv4 && ipv4 == nil {
+			ipv4 = primaryIP
+		}
+		if primaryIP.Type == hcloud.PrimaryIPTypeIPv6 && ipv6 == nil {
+			ipv6 = primaryIP
+		}
+	}
+	return ipv4, ipv6
+}
+
+func primaryIPRegion(primaryIP *hcloud.PrimaryIP) infrav1.Region {
+	if primaryIP.Datacenter == nil || primaryIP.Datacenter.Location == nil {
+		return ""
+	}
+	return infrav1.Region(primaryIP.Datacenter.Location.Name)
+}
+
+// keepPrimaryIPsInPool makes sure that the primary IPs of the server are not deleted together with the
+// server, but are kept in the pool of the cluster.
+func (s *Service) keepPrimaryIPsInPool(ctx context.Context, server *hcloud.Server) error {
+	if !s.usesPrimaryIPPool() {
+		return nil
+	}
+
+	autoDelete := false
+	labels := s.primaryIPPoolLabels()
+
+	for _, id := range []int64{server.PublicNet.IPv4.ID, server.PublicNet.IPv6.ID} {
+		if id == 0 {
+			continue
+		}
+
+		opts := hcloud.PrimaryIPUpdateOpts{
+			AutoDelete: &autoDelete,
+			Labels:     &labels,
+		}
+		if err := s.scope.HCloudClient.UpdatePrimaryIP(ctx, &hcloud.PrimaryIP{ID: id}, opts); err != nil {
+			if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				continue
+			}
+			return handleRateLimit(s.scope.HCloudMachine, err, "UpdatePrimaryIP", "failed to keep primary IP in pool")
+		}
+	}
+
+	return nil
+}
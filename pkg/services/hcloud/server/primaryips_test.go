@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("Primary IPs", func() {
+	var (
+		hcloudMachine *infrav1.HCloudMachine
+		service       *Service
+		server        *hcloud.Server
+	)
+	client := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hcloud-machine-with-primary-ips",
+				Namespace: "default",
+			},
+			Spec: infrav1.HCloudMachineSpec{
+				PublicNetwork: &infrav1.PublicNetworkSpec{
+					EnableIPv4:       true,
+					EnableIPv6:       true,
+					UsePrimaryIPPool: true,
+				},
+			},
+		}
+
+		hetznerCluster := &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster-primary-ips"
+
+		service = &Service{
+			scope: &scope.MachineScope{
+				HCloudMachine: hcloudMachine,
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{clusterv1.MachineControlPlaneLabel: ""},
+					},
+				},
+				ClusterScope: scope.ClusterScope{
+					HetznerCluster: hetznerCluster,
+					HCloudClient:   client,
+				},
+			},
+		}
+
+		var err error
+		server, err = client.CreateServer(context.Background(), hcloud.ServerCreateOpts{
+			Name:      "server-with-primary-ips",
+			Location:  &hcloud.Location{Name: "fsn1"},
+			PublicNet: &hcloud.ServerCreatePublicNet{EnableIPv4: true, EnableIPv6: true},
+		})
+		Expect(err).To(Succeed())
+	})
+
+	AfterEach(func() {
+		err := client.DeleteServer(context.Background(), server)
+		if !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			Expect(err).To(Succeed())
+		}
+
+		primaryIPs, err := client.ListPrimaryIPs(context.Background(), hcloud.PrimaryIPListOpts{})
+		Expect(err).To(Succeed())
+		for _, primaryIP := range primaryIPs {
+			if primaryIP.AssigneeID == 0 {
+				Expect(client.DeletePrimaryIP(context.Background(), primaryIP)).To(Succeed())
+			}
+		}
+	})
+
+	It("keeps the primary IPs of a deleted server in the pool", func() {
+		Expect(service.keepPrimaryIPsInPool(context.Background(), server)).To(Succeed())
+		Expect(client.DeleteServer(context.Background(), server)).To(Succeed())
+
+		primaryIPs, err := service.availablePrimaryIPs(context.Background())
+		Expect(err).To(Succeed())
+		Expect(primaryIPs).To(HaveLen(2))
+
+		ipv4, ipv6 := selectPrimaryIPs(primaryIPs, "fsn1")
+		Expect(ipv4.ID).To(Equal(server.PublicNet.IPv4.ID))
+		Expect(ipv6.ID).To(Equal(server.PublicNet.IPv6.ID))
+
+		ipv4, ipv6 = selectPrimaryIPs(primaryIPs, "nbg1")
+		Expect(ipv4).To(BeNil())
+		Expect(ipv6).To(BeNil())
+	})
+
+	It("does not keep the primary IPs of workers", func() {
+		service.scope.Machine.Labels = nil
+
+		Expect(service.keepPrimaryIPsInPool(context.Background(), server)).To(Succeed())
+		Expect(client.DeleteServer(context.Background(), server)).To(Succeed())
+
+		primaryIPs, err := client.ListPrimaryIPs(context.Background(), hcloud.PrimaryIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(primaryIPs).To(BeEmpty())
+	})
+
+	It("sets a condition if a referenced primary IP is assigned to another server", func() {
+		primaryIPs, err := client.ListPrimaryIPs(context.Background(), hcloud.PrimaryIPListOpts{})
+		Expect(err).To(Succeed())
+		Expect(primaryIPs).ToNot(BeEmpty())
+
+		var name string
+		for _, primaryIP := range primaryIPs {
+			if primaryIP.Type == hcloud.PrimaryIPTypeIPv4 {
+				name = primaryIP.Name
+			}
+		}
+		hcloudMachine.Spec.PublicNetwork.PrimaryIPv4 = ptr.To(name)
+
+		_, err = service.availablePrimaryIPs(context.Background())
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(isPresentAndFalseWithReason(hcloudMachine, infrav1.ServerCreateSucceededCondition, infrav1.PrimaryIPNotAvailableReason)).To(BeTrue())
+	})
+})
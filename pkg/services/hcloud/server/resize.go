@@ -0,0 +1,369 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+)
+
+const (
+	// podNodeNameField is the field selector to list the pods of a node.
+	podNodeNameField = "spec.nodeName"
+
+	resizeRequeueInterval = 10 * time.Second
+)
+
+// resizeInProgress returns true if an in-place resize of the server was requested or has not been finished yet.
+func (s *Service) resizeInProgress() bool {
+	if _, ok := s.scope.HCloudMachine.Annotations[infrav1.ResizeServerTypeAnnotation]; ok {
+		return true
+	}
+	return s.resizeStarted()
+}
+
+// resizeStarted returns true if the node has already been cordoned for a resize.
+func (s *Service) resizeStarted() bool {
+	condition := conditions.Get(s.scope.HCloudMachine, infrav1.ServerResizeSucceededCondition)
+	if condition == nil || condition.Status != corev1.ConditionFalse {
+		return false
+	}
+
+	switch condition.Reason {
+	case infrav1.ServerResizeDrainingNodeReason,
+		infrav1.ServerResizePoweringOffReason,
+		infrav1.ServerResizeChangingTypeReason,
+		infrav1.ServerResizePoweringOnReason:
+		return true
+	}
+	return false
+}
+
+// reconcileResize changes the type of the server in place. The node is cordoned and drained, the server is
+// powered off, its type is changed and it is powered on again. Once the node is ready, it is uncordoned.
+// Removing the annotation during a resize aborts it and brings the server back with its current type.
+func (s *Service) reconcileResize(ctx context.Context, server *hcloud.Server) (reconcile.Result, error) {
+	targetType, requested := s.scope.HCloudMachine.Annotations[infrav1.ResizeServerTypeAnnotation]
+	currentType := ""
+	if server.ServerType != nil {
+		currentType = server.ServerType.Name
+	}
+
+	if !s.resizeStarted() {
+		if targetType == currentType {
+			// nothing to do
+			delete(s.scope.HCloudMachine.Annotations, infrav1.ResizeServerTypeAnnotation)
+			return reconcile.Result{}, nil
+		}
+
+		serverType, err := s.scope.HCloudClient.GetServerType(ctx, targetType)
+		if err != nil {
+			return reconcile.Result{}, handleRateLimit(s.scope.HCloudMachine, err, "GetServerType", "failed to get server type in HCloud")
+		}
+
+		if err := checkResize(server, serverType, targetType); err != nil {
+			delete(s.scope.HCloudMachine.Annotations, infrav1.ResizeServerTypeAnnotation)
+			conditions.MarkFalse(
+				s.scope.HCloudMachine,
+				infrav1.ServerResizeSucceededCondition,
+				infrav1.ServerResizeFailedReason,
+				clusterv1.ConditionSeverityError,
+				"%s",
+				err.Error(),
+			)
+			record.Warnf(s.scope.HCloudMachine, "ServerResizeFailed", "Cannot resize server %s: %s", server.Name, err.Error())
+			return reconcile.Result{}, nil
+		}
+
+		record.Eventf(
+			s.scope.HCloudMachine,
+			"ServerResizeStarted",
+			"Resizing server %s from type %s to %s",
+			server.Name, currentType, targetType,
+		)
+	}
+
+	if requested && targetType != currentType {
+		return s.resizeServer(ctx, server, targetType)
+	}
+
+	return s.finishResize(ctx, server, currentType, requested)
+}
+
+// checkResize checks whether the server can be changed to the target type.
+func checkResize(server *hcloud.Server, serverType *hcloud.ServerType, targetType string) error {
+	if serverType == nil {
+		return fmt.Errorf("server type %q not found", targetType)
+	}
+	if server.ServerType != nil && server.ServerType.Architecture != "" && serverType.Architecture != server.ServerType.Architecture {
+		return fmt.Errorf("server type %q has architecture %s, but the server has architecture %s",
+			targetType, serverType.Architecture, server.ServerType.Architecture)
+	}
+	return nil
+}
+
+// resizeServer drains the node, powers off the server and changes its type.
+func (s *Service) resizeServer(ctx context.Context, server *hcloud.Server, targetType string) (reconcile.Result, error) {
+	if server.Status == hcloud.ServerStatusRunning {
+		remaining, err := s.drainNode(ctx)
+		if err != nil {
+			s.markResizeProgress(infrav1.ServerResizeDrainingNodeReason, "failed to drain node: %s", err.Error())
+			return reconcile.Result{}, fmt.Errorf("failed to drain node: %w", err)
+		}
+		if remaining > 0 {
+			s.markResizeProgress(infrav1.ServerResizeDrainingNodeReason, "waiting for %d pods to be evicted", remaining)
+			return reconcile.Result{RequeueAfter: resizeRequeueInterval}, nil
+		}
+
+		if err := s.scope.HCloudClient.ShutdownServer(ctx, server); err != nil {
+			return reconcile.Result{}, handleRateLimit(s.scope.HCloudMachine, err, "ShutdownServer", "failed to shutdown server")
+		}
+		s.markResizeProgress(infrav1.ServerResizePoweringOffReason, "server is shutting down")
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if server.Status != hcloud.ServerStatusOff {
+		// some temporary status
+		return reconcile.Result{RequeueAfter: resizeRequeueInterval}, nil
+	}
+
+	s.markResizeProgress(infrav1.ServerResizeChangingTypeReason, "changing server type to %s", targetType)
+	if err := s.scope.HCloudClient.ChangeServerType(ctx, server, hcloud.ServerChangeTypeOpts{
+		ServerType: &hcloud.ServerType{Name: targetType},
+		// keep the disk so that the server can be resized back to a smaller type
+		UpgradeDisk: false,
+	}); err != nil {
+		if hcloud.IsError(err, hcloud.ErrorCodeLocked) {
+			// if server is locked, we just retry again
+			return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		record.Warnf(s.scope.HCloudMachine, "FailedChangeServerType", "Failed to change type of server %s to %s: %s", server.Name, targetType, err)
+		return reconcile.Result{}, handleRateLimit(s.scope.HCloudMachine, err, "ChangeServerType", "failed to change server type")
+	}
+
+	record.Eventf(s.scope.HCloudMachine, "ServerTypeChanged", "Changed type of server %s to %s", server.Name, targetType)
+	return reconcile.Result{RequeueAfter: resizeRequeueInterval}, nil
+}
+
+// finishResize powers the server on, waits for the node to become ready and uncordons it.
+func (s *Service) finishResize(ctx context.Context, server *hcloud.Server, currentType string, requested bool) (reconcile.Result, error) {
+	switch server.Status {
+	case hcloud.ServerStatusOff:
+		if err := s.scope.HCloudClient.PowerOnServer(ctx, server); err != nil {
+			if hcloud.IsError(err, hcloud.ErrorCodeLocked) {
+				// if server is locked, we just retry again
+				return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+			}
+			return reconcile.Result{}, handleRateLimit(s.scope.HCloudMachine, err, "PowerOnServer", "failed to power on server")
+		}
+		s.markResizeProgress(infrav1.ServerResizePoweringOnReason, "server is starting")
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	case hcloud.ServerStatusRunning:
+	default:
+		// some temporary status
+		return reconcile.Result{RequeueAfter: resizeRequeueInterval}, nil
+	}
+
+	ready, err := s.uncordonNode(ctx)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to uncordon node: %w", err)
+	}
+	if !ready {
+		s.markResizeProgress(infrav1.ServerResizePoweringOnReason, "waiting for node to become ready")
+		return reconcile.Result{RequeueAfter: resizeRequeueInterval}, nil
+	}
+
+	delete(s.scope.HCloudMachine.Annotations, infrav1.ResizeServerTypeAnnotation)
+	conditions.MarkTrue(s.scope.HCloudMachine, infrav1.ServerAvailableCondition)
+
+	if !requested {
+		conditions.MarkFalse(
+			s.scope.HCloudMachine,
+			infrav1.ServerResizeSucceededCondition,
+			infrav1.ServerResizeAbortedReason,
+			clusterv1.ConditionSeverityWarning,
+			"resize was aborted, server has type %s",
+			currentType,
+		)
+		record.Warnf(s.scope.HCloudMachine, "ServerResizeAborted", "Aborted resize of server %s", server.Name)
+		return reconcile.Result{}, nil
+	}
+
+	conditions.MarkTrue(s.scope.HCloudMachine, infrav1.ServerResizeSucceededCondition)
+	record.Eventf(s.scope.HCloudMachine, "ServerResized", "Resized server %s to type %s", server.Name, currentType)
+	return reconcile.Result{}, nil
+}
+
+// markResizeProgress updates the progress of the resize. The server is not available until the resize is
+// finished, so that the machine is not reported as ready while its node is drained or powered off.
+func (s *Service) markResizeProgress(reason string, messageFormat string, messageArgs ...interface{}) {
+	conditions.MarkFalse(
+		s.scope.HCloudMachine,
+		infrav1.ServerResizeSucceededCondition,
+		reason,
+		clusterv1.ConditionSeverityInfo,
+		messageFormat,
+		messageArgs...,
+	)
+	conditions.MarkFalse(
+		s.scope.HCloudMachine,
+		infrav1.ServerAvailableCondition,
+		infrav1.ServerResizingReason,
+		clusterv1.ConditionSeverityInfo,
+		"server is being resized",
+	)
+}
+
+// nodeName returns the name of the node of the machine or an empty string if there is none yet.
+func (s *Service) nodeName() string {
+	if s.scope.Machine == nil || s.scope.Machine.Status.NodeRef == nil {
+		return ""
+	}
+	return s.scope.Machine.Status.NodeRef.Name
+}
+
+// drainNode cordons the node and evicts all pods that are not managed by a DaemonSet. It returns the number of
+// pods that are still running on the node.
+func (s *Service) drainNode(ctx context.Context) (int, error) {
+	nodeName := s.nodeName()
+	if nodeName == "" {
+		return 0, nil
+	}
+
+	workloadClient, err := s.workloadClient(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get workload cluster client: %w", err)
+	}
+
+	if err := setUnschedulable(ctx, workloadClient, nodeName, true); err != nil {
+		return 0, err
+	}
+
+	var pods corev1.PodList
+	if err := workloadClient.List(ctx, &pods, client.MatchingFields{podNodeNameField: nodeName}); err != nil {
+		return 0, fmt.Errorf("failed to list pods of node %s: %w", nodeName, err)
+	}
+
+	remaining := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if skipEviction(pod) {
+			continue
+		}
+		remaining++
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+		}
+		if err := workloadClient.SubResource("eviction").Create(ctx, pod, eviction); err != nil {
+			switch {
+			case apierrors.IsNotFound(err):
+				remaining--
+			case apierrors.IsTooManyRequests(err):
+				// the eviction is blocked by a pod disruption budget, try again later
+			default:
+				return 0, fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+	}
+
+	return remaining, nil
+}
+
+// skipEviction returns true for pods that do not need to be evicted to drain a node.
+func skipEviction(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return true
+	}
+	if controllerRef := metav1.GetControllerOf(pod); controllerRef != nil && controllerRef.Kind == "DaemonSet" {
+		return true
+	}
+	return false
+}
+
+// uncordonNode makes the node schedulable again once it is ready. It returns false if the node is not ready yet.
+func (s *Service) uncordonNode(ctx context.Context) (bool, error) {
+	nodeName := s.nodeName()
+	if nodeName == "" {
+		return true, nil
+	}
+
+	workloadClient, err := s.workloadClient(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get workload cluster client: %w", err)
+	}
+
+	var node corev1.Node
+	if err := workloadClient.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+		return false, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+
+	if !isNodeReady(&node) {
+		return false, nil
+	}
+
+	return true, setUnschedulable(ctx, workloadClient, nodeName, false)
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func setUnschedulable(ctx context.Context, c client.Client, nodeName string, unschedulable bool) error {
+	var node corev1.Node
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.Unschedulable = unschedulable
+	if err := c.Patch(ctx, &node, patch); err != nil {
+		return fmt.Errorf("failed to set node %s unschedulable=%t: %w", nodeName, unschedulable, err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("Resize", func() {
+	var (
+		hcloudMachine  *infrav1.HCloudMachine
+		service        *Service
+		server         *hcloud.Server
+		workloadClient client.Client
+	)
+	hcloudClient := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "hcloud-machine-resize",
+				Namespace:   "default",
+				Annotations: map[string]string{infrav1.ResizeServerTypeAnnotation: "cpx31"},
+			},
+		}
+
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-resize"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: node.Name},
+		}
+		daemonSetPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "daemon",
+				Namespace: "kube-system",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "daemon", UID: "1", Controller: ptr.To(true)},
+				},
+			},
+			Spec: corev1.PodSpec{NodeName: node.Name},
+		}
+		workloadClient = fake.NewClientBuilder().
+			WithObjects(node, pod, daemonSetPod).
+			WithIndex(&corev1.Pod{}, podNodeNameField, func(o client.Object) []string {
+				return []string{o.(*corev1.Pod).Spec.NodeName}
+			}).
+			Build()
+
+		service = newTestService(hcloudMachine, hcloudClient)
+		service.scope.Machine = &clusterv1.Machine{
+			Status: clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: node.Name}},
+		}
+		service.workloadClient = func(context.Context) (client.Client, error) {
+			return workloadClient, nil
+		}
+
+		var err error
+		server, err = hcloudClient.CreateServer(context.Background(), hcloud.ServerCreateOpts{
+			Name:       "server-resize",
+			ServerType: &hcloud.ServerType{Name: "cpx11"},
+		})
+		Expect(err).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(hcloudClient.DeleteServer(context.Background(), server)).To(Succeed())
+	})
+
+	reconcileResize := func() {
+		server, err := hcloudClient.GetServer(context.Background(), server.ID)
+		Expect(err).To(Succeed())
+		Expect(service.resizeInProgress()).To(BeTrue())
+		_, err = service.reconcileResize(context.Background(), server)
+		Expect(err).To(Succeed())
+	}
+
+	getNode := func() *corev1.Node {
+		var node corev1.Node
+		Expect(workloadClient.Get(context.Background(), client.ObjectKey{Name: "node-resize"}, &node)).To(Succeed())
+		return &node
+	}
+
+	It("drains the node, changes the server type and uncordons the node", func() {
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizeDrainingNodeReason))
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerAvailableCondition)).To(Equal(infrav1.ServerResizingReason))
+		Expect(getNode().Spec.Unschedulable).To(BeTrue())
+
+		var pods corev1.PodList
+		Expect(workloadClient.List(context.Background(), &pods)).To(Succeed())
+		Expect(pods.Items).To(HaveLen(1))
+		Expect(pods.Items[0].Name).To(Equal("daemon"))
+
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizePoweringOffReason))
+
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizeChangingTypeReason))
+		resized, err := hcloudClient.GetServer(context.Background(), server.ID)
+		Expect(err).To(Succeed())
+		Expect(resized.ServerType.Name).To(Equal("cpx31"))
+
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizePoweringOnReason))
+		Expect(conditions.IsFalse(hcloudMachine, infrav1.ServerAvailableCondition)).To(BeTrue())
+
+		reconcileResize()
+		Expect(conditions.IsTrue(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(BeTrue())
+		Expect(conditions.IsTrue(hcloudMachine, infrav1.ServerAvailableCondition)).To(BeTrue())
+		Expect(hcloudMachine.Annotations).ToNot(HaveKey(infrav1.ResizeServerTypeAnnotation))
+		Expect(getNode().Spec.Unschedulable).To(BeFalse())
+		Expect(service.resizeInProgress()).To(BeFalse())
+	})
+
+	It("brings the server back if the resize is aborted", func() {
+		reconcileResize()
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizePoweringOffReason))
+
+		delete(hcloudMachine.Annotations, infrav1.ResizeServerTypeAnnotation)
+
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizePoweringOnReason))
+
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizeAbortedReason))
+		Expect(conditions.IsTrue(hcloudMachine, infrav1.ServerAvailableCondition)).To(BeTrue())
+		Expect(getNode().Spec.Unschedulable).To(BeFalse())
+
+		resized, err := hcloudClient.GetServer(context.Background(), server.ID)
+		Expect(err).To(Succeed())
+		Expect(resized.ServerType.Name).To(Equal("cpx11"))
+	})
+
+	It("sets a condition and removes the annotation if the server type does not exist", func() {
+		hcloudMachine.Annotations[infrav1.ResizeServerTypeAnnotation] = "unknown"
+
+		reconcileResize()
+		Expect(conditions.GetReason(hcloudMachine, infrav1.ServerResizeSucceededCondition)).To(Equal(infrav1.ServerResizeFailedReason))
+		Expect(conditions.Has(hcloudMachine, infrav1.ServerAvailableCondition)).To(BeFalse())
+		Expect(hcloudMachine.Annotations).ToNot(HaveKey(infrav1.ResizeServerTypeAnnotation))
+		Expect(getNode().Spec.Unschedulable).To(BeFalse())
+		Expect(service.resizeInProgress()).To(BeFalse())
+	})
+})
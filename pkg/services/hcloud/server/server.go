@@ -18,24 +18,31 @@ limitations under the License.
 package server
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/userdata"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
 )
 
@@ -52,12 +59,16 @@ var (
 // Service defines struct with machine scope to reconcile HCloudMachines.
 type Service struct {
 	scope *scope.MachineScope
+
+	// workloadClient returns a client for the workload cluster.
+	workloadClient func(context.Context) (client.Client, error)
 }
 
 // NewService outs a new service with machine scope.
 func NewService(scope *scope.MachineScope) *Service {
 	return &Service{
-		scope: scope,
+		scope:          scope,
+		workloadClient: scope.WorkloadClient,
 	}
 }
 
@@ -113,10 +124,14 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 	// update HCloudMachineStatus
 	c := s.scope.HCloudMachine.Status.Conditions.DeepCopy()
 	sshKeys := s.scope.HCloudMachine.Status.SSHKeys
+	volumes := s.scope.HCloudMachine.Status.Volumes
 	s.scope.HCloudMachine.Status = statusFromHCloudServer(server)
-	s.scope.SetRegion(failureDomain)
+	if s.scope.HCloudMachine.Status.Region == "" {
+		s.scope.SetRegion(failureDomain)
+	}
 	s.scope.HCloudMachine.Status.Conditions = c
 	s.scope.HCloudMachine.Status.SSHKeys = sshKeys
+	s.scope.HCloudMachine.Status.Volumes = volumes
 
 	// validate labels
 	if err := validateLabels(server, s.createLabels()); err != nil {
@@ -125,6 +140,11 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 		return res, nil
 	}
 
+	// resize the server in place if requested
+	if s.resizeInProgress() {
+		return s.reconcileResize(ctx, server)
+	}
+
 	// analyze status of server
 	switch server.Status {
 	case hcloud.ServerStatusOff:
@@ -149,6 +169,11 @@ func (s *Service) Reconcile(ctx context.Context) (res reconcile.Result, err erro
 		return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// check whether all volumes are attached to the server
+	if err := s.reconcileVolumes(ctx, server); err != nil {
+		return res, fmt.Errorf("failed to reconcile volumes: %w", err)
+	}
+
 	// check whether server is attached to the network
 	if err := s.reconcileNetworkAttachment(ctx, server); err != nil {
 		reterr := fmt.Errorf("failed to reconcile network attachment: %w", err)
@@ -217,6 +242,12 @@ func (s *Service) Delete(ctx context.Context) (res reconcile.Result, err error)
 
 	// if no server has been found, then nothing can be deleted
 	if server == nil {
+		// volumes might be left over from a server that has already been deleted
+		res, err := s.deleteVolumes(ctx)
+		if err != nil || !res.IsZero() {
+			return res, err
+		}
+
 		msg := fmt.Sprintf("Unable to delete HCloud server. Could not find matching server for %s", s.scope.Name())
 		s.scope.V(1).Info(msg)
 		record.Warnf(s.scope.HCloudMachine, "NoInstanceFound", msg)
@@ -235,6 +266,11 @@ func (s *Service) Delete(ctx context.Context) (res reconcile.Result, err error)
 		}
 	}
 
+	// primary IPs of the pool have to be kept when the server is deleted
+	if err := s.keepPrimaryIPsInPool(ctx, server); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to keep primary IPs in pool: %w", err)
+	}
+
 	// first shut the server down, then delete it
 	switch server.Status {
 	case hcloud.ServerStatusRunning:
@@ -354,23 +390,85 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 		return nil, fmt.Errorf("failed to get raw bootstrap data: %s", err)
 	}
 
-	image, err := s.getServerImage(ctx)
+	candidates := s.serverCandidates()
+
+	primaryIPs, err := s.availablePrimaryIPs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get primary IPs: %w", err)
+	}
+
+	// referenced primary IPs define the location of the server
+	if s.referencesPrimaryIPs() {
+		candidates = slices.DeleteFunc(candidates, func(candidate serverCandidate) bool {
+			return slices.ContainsFunc(primaryIPs, func(primaryIP *hcloud.PrimaryIP) bool {
+				return primaryIPRegion(primaryIP) != candidate.region
+			})
+		})
+		if len(candidates) == 0 {
+			conditions.MarkFalse(
+				s.scope.HCloudMachine,
+				infrav1.ServerCreateSucceededCondition,
+				infrav1.PrimaryIPNotAvailableReason,
+				clusterv1.ConditionSeverityError,
+				"referenced primary IPs are not in location %s of the machine",
+				s.scope.HCloudMachine.Status.Region,
+			)
+			return nil, errServerCreateNotPossible
+		}
+	}
+
+	// get the image of the server type of the machine. Images of fallback server types, which might differ in
+	// their architecture, are only looked up if they are needed.
+	image, err := s.getServerImage(ctx, s.scope.HCloudMachine.Spec.Type)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get server image: %w", err)
 	}
+	images := map[infrav1.HCloudMachineType]*hcloud.Image{s.scope.HCloudMachine.Spec.Type: image}
+
+	// create volumes before the server, so that they are available when the server boots
+	volumes, err := s.ensureVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile volumes: %w", err)
+	}
+
+	for _, volume := range volumes {
+		if volume.Server != nil {
+			conditions.MarkFalse(
+				s.scope.HCloudMachine,
+				infrav1.VolumesReadyCondition,
+				infrav1.VolumeAttachedToOtherServerReason,
+				clusterv1.ConditionSeverityError,
+				"volume %s is attached to server with ID %d",
+				volume.Name, volume.Server.ID,
+			)
+			return nil, errServerCreateNotPossible
+		}
+	}
+
+	userData, err = addVolumeMounts(userData, volumeMounts(s.scope.HCloudMachine.Spec.Volumes, volumes))
+	if err != nil {
+		record.Warnf(s.scope.HCloudMachine, "FailedAddVolumeMounts", "Failed to add mounts of volumes to bootstrap data: %s", err)
+		conditions.MarkFalse(
+			s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.VolumeMountsNotPossibleReason,
+			clusterv1.ConditionSeverityError,
+			"failed to add mounts of volumes to bootstrap data: %s",
+			err.Error(),
+		)
+		return nil, errServerCreateNotPossible
+	}
+
+	userData, err = s.fitUserData(userData)
+	if err != nil {
+		return nil, err
+	}
 
 	automount := false
 	startAfterCreate := true
 	opts := hcloud.ServerCreateOpts{
-		Name:   s.scope.Name(),
-		Labels: s.createLabels(),
-		Image:  image,
-		Location: &hcloud.Location{
-			Name: string(s.scope.HCloudMachine.Status.Region),
-		},
-		ServerType: &hcloud.ServerType{
-			Name: string(s.scope.HCloudMachine.Spec.Type),
-		},
+		Name:             s.scope.Name(),
+		Labels:           s.createLabels(),
 		Automount:        &automount,
 		StartAfterCreate: &startAfterCreate,
 		UserData:         string(userData),
@@ -453,6 +551,13 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 		return nil, errServerCreateNotPossible
 	}
 
+	// volumes without automount are attached when the server is created, the others after it is running
+	for i, volume := range volumes {
+		if !s.scope.HCloudMachine.Spec.Volumes[i].Automount {
+			opts.Volumes = append(opts.Volumes, volume)
+		}
+	}
+
 	// set up network if available
 	if net := s.scope.HetznerCluster.Status.Network; net != nil {
 		opts.Networks = []*hcloud.Network{{
@@ -466,8 +571,11 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 	}
 
 	// Create the server
-	server, err := s.scope.HCloudClient.CreateServer(ctx, opts)
+	server, err := s.createServerWithFallback(ctx, opts, candidates, images, primaryIPs)
 	if err != nil {
+		if errors.Is(err, errServerCreateNotPossible) {
+			return nil, err
+		}
 		if hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "CreateServer") {
 			// RateLimit was reached. Condition and Event got already created.
 			return nil, fmt.Errorf("failed to create HCloud server %s: %w", s.scope.HCloudMachine.Name, err)
@@ -499,11 +607,135 @@ func (s *Service) createServer(ctx context.Context) (*hcloud.Server, error) {
 	return server, nil
 }
 
-func (s *Service) getServerImage(ctx context.Context) (*hcloud.Image, error) {
+// serverCandidate is a combination of server type and location that is tried in order to create the server.
+type serverCandidate struct {
+	serverType infrav1.HCloudMachineType
+	region     infrav1.Region
+}
+
+// serverCandidates returns the combinations of server type and location in the order they are tried. All server
+// types are tried in one location before the next location is tried.
+func (s *Service) serverCandidates() []serverCandidate {
+	serverTypes := []infrav1.HCloudMachineType{s.scope.HCloudMachine.Spec.Type}
+	for _, serverType := range s.scope.HCloudMachine.Spec.FallbackTypes {
+		if !slices.Contains(serverTypes, serverType) {
+			serverTypes = append(serverTypes, serverType)
+		}
+	}
+
+	regions := []infrav1.Region{s.scope.HCloudMachine.Status.Region}
+
+	// a failure domain that is set explicitly in the Machine has to be respected. Volumes are bound to the
+	// location they have been created in.
+	if s.scope.Machine.Spec.FailureDomain == nil && len(s.scope.HCloudMachine.Spec.Volumes) == 0 && s.scope.Cluster != nil {
+		for _, region := range s.scope.HCloudMachine.Spec.FallbackRegions {
+			fd, found := s.scope.Cluster.Status.FailureDomains[string(region)]
+			if !found || (s.scope.IsControlPlane() && !fd.ControlPlane) {
+				continue
+			}
+			if !slices.Contains(regions, region) {
+				regions = append(regions, region)
+			}
+		}
+	}
+
+	candidates := make([]serverCandidate, 0, len(serverTypes)*len(regions))
+	for _, region := range regions {
+		for _, serverType := range serverTypes {
+			candidates = append(candidates, serverCandidate{serverType: serverType, region: region})
+		}
+	}
+	return candidates
+}
+
+// createServerWithFallback creates the server with the first candidate that Hetzner's Cloud API has capacity for.
+// Images of server types that are missing in images are looked up when the server type is tried for the first
+// time. Server types without a usable image are skipped. It returns errServerCreateNotPossible if there is no
+// capacity for any of the candidates.
+func (s *Service) createServerWithFallback(
+	ctx context.Context,
+	opts hcloud.ServerCreateOpts,
+	candidates []serverCandidate,
+	images map[infrav1.HCloudMachineType]*hcloud.Image,
+	primaryIPs []*hcloud.PrimaryIP,
+) (*hcloud.Server, error) {
+	for i, candidate := range candidates {
+		image, found := images[candidate.serverType]
+		if !found {
+			var err error
+			image, err = s.getServerImage(ctx, candidate.serverType)
+			if err != nil {
+				if !errors.Is(err, errServerCreateNotPossible) {
+					return nil, fmt.Errorf("failed to get server image: %w", err)
+				}
+				record.Warnf(s.scope.HCloudMachine,
+					"FallbackServerTypeSkipped",
+					"Skipping server type %s, as no image can be used for it",
+					candidate.serverType,
+				)
+			}
+			// remember server types without image as nil, so that they are not looked up again
+			images[candidate.serverType] = image
+		}
+		if image == nil {
+			continue
+		}
+
+		opts.Image = image
+		opts.Location = &hcloud.Location{Name: string(candidate.region)}
+		opts.ServerType = &hcloud.ServerType{Name: string(candidate.serverType)}
+
+		if opts.PublicNet != nil {
+			publicNet := *opts.PublicNet
+			publicNet.IPv4, publicNet.IPv6 = selectPrimaryIPs(primaryIPs, candidate.region)
+			if !publicNet.EnableIPv4 {
+				publicNet.IPv4 = nil
+			}
+			if !publicNet.EnableIPv6 {
+				publicNet.IPv6 = nil
+			}
+			opts.PublicNet = &publicNet
+		}
+
+		server, err := s.scope.HCloudClient.CreateServer(ctx, opts)
+		if err == nil {
+			if i > 0 {
+				record.Eventf(s.scope.HCloudMachine,
+					"UsedFallbackServerType",
+					"Created server with server type %s in %s, as server type %s is not available in %s",
+					candidate.serverType, candidate.region, candidates[0].serverType, candidates[0].region,
+				)
+			}
+			return server, nil
+		}
+
+		if !hcloud.IsError(err, hcloud.ErrorCodeResourceUnavailable) {
+			return nil, err
+		}
+
+		record.Warnf(s.scope.HCloudMachine,
+			"ServerTypeUnavailable",
+			"Server type %s is not available in %s: %s",
+			candidate.serverType, candidate.region, err,
+		)
+	}
+
+	conditions.MarkFalse(
+		s.scope.HCloudMachine,
+		infrav1.ServerCreateSucceededCondition,
+		infrav1.ServerTypeUnavailableReason,
+		clusterv1.ConditionSeverityWarning,
+		"no capacity available for any of the %d combinations of server type and location",
+		len(candidates),
+	)
+	return nil, errServerCreateNotPossible
+}
+
+func (s *Service) getServerImage(ctx context.Context, serverTypeName infrav1.HCloudMachineType) (*hcloud.Image, error) {
 	key := fmt.Sprintf("%s%s", infrav1.NameHetznerProviderPrefix, "image-name")
 
 	// Get server type so we can filter for images with correct architecture
-	serverType, err := s.scope.HCloudClient.GetServerType(ctx, string(s.scope.HCloudMachine.Spec.Type))
+	serverType, err := s.scope.HCloudClient.GetServerType(ctx, string(serverTypeName))
 	if err != nil {
 		return nil, handleRateLimit(s.scope.HCloudMachine, err, "GetServerType", "failed to get server type in HCloud")
 	}
@@ -518,6 +750,10 @@ func (s *Service) getServerImage(ctx context.Context) (*hcloud.Image, error) {
 		return nil, errServerCreateNotPossible
 	}
 
+	if s.scope.HCloudMachine.Spec.ImageSelector != nil {
+		return s.getServerImageBySelector(ctx, serverType)
+	}
+
 	// query for an existing image by label
 	// this is needed because snapshots don't have a name, only descriptions and labels
 	listOpts := hcloud.ImageListOpts{
@@ -572,6 +808,122 @@ func (s *Service) getServerImage(ctx context.Context) (*hcloud.Image, error) {
 	return images[0], nil
 }
 
+// fitUserData compresses the bootstrap data if it exceeds the size limit of user data in Hetzner's Cloud API.
+func (s *Service) fitUserData(userData []byte) ([]byte, error) {
+	fitted, compressed, err := userdata.Fit(userData, userdata.MaxHCloudSize)
+	if err != nil {
+		record.Warnf(s.scope.HCloudMachine, "BootstrapDataTooLarge", "Bootstrap data cannot be passed as user data: %s", err)
+		conditions.MarkFalse(
+			s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.BootstrapDataTooLargeReason,
+			clusterv1.ConditionSeverityError,
+			"bootstrap data cannot be passed as user data: %s",
+			err.Error(),
+		)
+		return nil, errServerCreateNotPossible
+	}
+
+	if compressed {
+		record.Eventf(s.scope.HCloudMachine, "BootstrapDataCompressed",
+			"Compressed bootstrap data from %d to %d bytes to fit the limit of user data", len(userData), len(fitted))
+	}
+	return fitted, nil
+}
+
+// imageSelectorData contains the fields that can be used in the label selector of an image selector.
+type imageSelectorData struct {
+	KubernetesVersion string
+	Architecture      string
+}
+
+// getServerImageBySelector returns the image matching the image selector. If several images match, the
+// newest one is returned, unless the policy requires a unique image.
+func (s *Service) getServerImageBySelector(ctx context.Context, serverType *hcloud.ServerType) (*hcloud.Image, error) {
+	selector := s.scope.HCloudMachine.Spec.ImageSelector
+
+	labelSelector, err := renderImageLabelSelector(selector.LabelSelector, imageSelectorData{
+		KubernetesVersion: ptr.Deref(s.scope.Machine.Spec.Version, ""),
+		Architecture:      string(serverType.Architecture),
+	})
+	if err != nil {
+		record.Warnf(s.scope.HCloudMachine, "ImageSelectorInvalid", err.Error())
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.ImageSelectorInvalidReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			err.Error(),
+		)
+		return nil, errServerCreateNotPossible
+	}
+
+	listOpts := hcloud.ImageListOpts{
+		ListOpts: hcloud.ListOpts{
+			LabelSelector: labelSelector,
+		},
+		Architecture: []hcloud.Architecture{serverType.Architecture},
+	}
+
+	images, err := s.scope.HCloudClient.ListImages(ctx, listOpts)
+	if err != nil {
+		return nil, handleRateLimit(s.scope.HCloudMachine, err, "ListImages", "failed to list images by label selector in HCloud")
+	}
+
+	if len(images) == 0 {
+		err := fmt.Errorf("no image found with label selector %s", labelSelector)
+		record.Warnf(s.scope.HCloudMachine, "ImageNotFound", err.Error())
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.ImageNotFoundReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			err.Error(),
+		)
+		return nil, errServerCreateNotPossible
+	}
+
+	if len(images) > 1 && selector.Policy == infrav1.HCloudImageSelectionPolicyUnique {
+		err := fmt.Errorf("image is ambiguous - %d images match label selector %s", len(images), labelSelector)
+		record.Warnf(s.scope.HCloudMachine, "ImageNameAmbiguous", err.Error())
+		conditions.MarkFalse(s.scope.HCloudMachine,
+			infrav1.ServerCreateSucceededCondition,
+			infrav1.ImageAmbiguousReason,
+			clusterv1.ConditionSeverityError,
+			"%s",
+			err.Error(),
+		)
+		return nil, errServerCreateNotPossible
+	}
+
+	return newestImage(images), nil
+}
+
+// renderImageLabelSelector renders the template of the label selector of an image selector.
+func renderImageLabelSelector(labelSelector string, data imageSelectorData) (string, error) {
+	tmpl, err := template.New("labelSelector").Option("missingkey=error").Parse(labelSelector)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse label selector of image selector: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render label selector of image selector: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// newestImage returns the image that has been created last. Images that have been created at the same time
+// are ordered by their ID.
+func newestImage(images []*hcloud.Image) *hcloud.Image {
+	return slices.MaxFunc(images, func(a, b *hcloud.Image) int {
+		if c := a.Created.Compare(b.Created); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
 func (s *Service) handleServerStatusOff(ctx context.Context, server *hcloud.Server) (res reconcile.Result, err error) {
 	// Check if server is in ServerStatusOff and turn it on. This is to avoid a bug of Hetzner where
 	// sometimes machines are created and not turned on
@@ -644,7 +996,7 @@ func (s *Service) handleDeleteServerStatusRunning(ctx context.Context, server *h
 	}
 
 	record.Eventf(s.scope.HCloudMachine, "HCloudServerDeleted", "HCloud server %s deleted", s.scope.Name())
-	return res, nil
+	return s.deleteVolumes(ctx)
 }
 
 func (s *Service) handleDeleteServerStatusOff(ctx context.Context, server *hcloud.Server) (res reconcile.Result, err error) {
@@ -655,7 +1007,7 @@ func (s *Service) handleDeleteServerStatusOff(ctx context.Context, server *hclou
 	}
 
 	record.Eventf(s.scope.HCloudMachine, "HCloudServerDeleted", "HCloud server %s deleted", s.scope.Name())
-	return res, nil
+	return s.deleteVolumes(ctx)
 }
 
 func (s *Service) deleteServerOfLoadBalancer(ctx context.Context, server *hcloud.Server) error {
@@ -771,10 +1123,19 @@ func statusFromHCloudServer(server *hcloud.Server) infrav1.HCloudMachineStatus {
 		)
 	}
 
-	return infrav1.HCloudMachineStatus{
+	status := infrav1.HCloudMachineStatus{
 		InstanceState: &instanceState,
 		Addresses:     addresses,
 	}
+
+	if server.ServerType != nil {
+		status.ServerType = infrav1.HCloudMachineType(server.ServerType.Name)
+	}
+	if server.Datacenter != nil && server.Datacenter.Location != nil {
+		status.Region = infrav1.Region(server.Datacenter.Location.Name)
+	}
+
+	return status
 }
 
 func (s *Service) createLabels() map[string]string {
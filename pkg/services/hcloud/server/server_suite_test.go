@@ -212,7 +212,7 @@ var _ = BeforeSuite(func() {
 
 func newTestService(hcloudMachine *infrav1.HCloudMachine, hcloudClient hcloudclient.Client) *Service {
 	return &Service{
-		&scope.MachineScope{
+		scope: &scope.MachineScope{
 			HCloudMachine: hcloudMachine,
 			ClusterScope: scope.ClusterScope{
 				HCloudClient: hcloudClient,
@@ -19,11 +19,13 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
@@ -34,6 +36,7 @@ import (
 	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
 	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
 	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/mocks"
 )
 
 var _ = Describe("statusFromHCloudServer", func() {
@@ -57,6 +60,242 @@ var _ = Describe("statusFromHCloudServer", func() {
 			Expect(addr.Type).To(Equal(addressTypes[i]))
 		}
 	})
+	It("should have the right region and server type", func() {
+		Expect(sts.Region).To(Equal(infrav1.Region("fsn1")))
+		Expect(sts.ServerType).To(Equal(infrav1.HCloudMachineType("cx11")))
+	})
+})
+
+var _ = Describe("serverCandidates", func() {
+	var (
+		hcloudMachine *infrav1.HCloudMachine
+		machine       *clusterv1.Machine
+		service       *Service
+	)
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			Spec: infrav1.HCloudMachineSpec{
+				Type:            "cpx31",
+				FallbackTypes:   []infrav1.HCloudMachineType{"cpx41", "cpx31"},
+				FallbackRegions: []infrav1.Region{"nbg1", "hel1"},
+			},
+			Status: infrav1.HCloudMachineStatus{Region: "fsn1"},
+		}
+		machine = &clusterv1.Machine{}
+
+		service = &Service{
+			scope: &scope.MachineScope{
+				HCloudMachine: hcloudMachine,
+				Machine:       machine,
+				ClusterScope: scope.ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						Status: clusterv1.ClusterStatus{
+							FailureDomains: clusterv1.FailureDomains{
+								"fsn1": clusterv1.FailureDomainSpec{ControlPlane: true},
+								"nbg1": clusterv1.FailureDomainSpec{ControlPlane: true},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("tries all server types in failure domains of the cluster", func() {
+		Expect(service.serverCandidates()).To(Equal([]serverCandidate{
+			{serverType: "cpx31", region: "fsn1"},
+			{serverType: "cpx41", region: "fsn1"},
+			{serverType: "cpx31", region: "nbg1"},
+			{serverType: "cpx41", region: "nbg1"},
+		}))
+	})
+
+	It("respects the failure domain of the machine", func() {
+		machine.Spec.FailureDomain = ptr.To("fsn1")
+
+		Expect(service.serverCandidates()).To(Equal([]serverCandidate{
+			{serverType: "cpx31", region: "fsn1"},
+			{serverType: "cpx41", region: "fsn1"},
+		}))
+	})
+
+	It("does not use fallback regions for machines with volumes", func() {
+		hcloudMachine.Spec.Volumes = []infrav1.HCloudVolumeSpec{{Name: "data", Size: 10}}
+
+		Expect(service.serverCandidates()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("createServerWithFallback", func() {
+	var (
+		hcloudMachine *infrav1.HCloudMachine
+		client        *mocks.Client
+		service       *Service
+	)
+
+	candidates := []serverCandidate{
+		{serverType: "cpx31", region: "fsn1"},
+		{serverType: "cpx41", region: "fsn1"},
+		{serverType: "cpx31", region: "nbg1"},
+	}
+	images := map[infrav1.HCloudMachineType]*hcloud.Image{
+		"cpx31": {ID: 1},
+		"cpx41": {ID: 1},
+	}
+	unavailable := hcloud.Error{Code: hcloud.ErrorCodeResourceUnavailable, Message: "unavailable"}
+
+	isCandidate := func(serverType, location string) func(hcloud.ServerCreateOpts) bool {
+		return func(opts hcloud.ServerCreateOpts) bool {
+			return opts.ServerType.Name == serverType && opts.Location.Name == location
+		}
+	}
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "hcloud-machine", Namespace: "default"},
+		}
+		client = &mocks.Client{}
+		service = newTestService(hcloudMachine, client)
+	})
+
+	It("uses the next candidate if there is no capacity", func() {
+		client.On("CreateServer", mock.Anything, mock.MatchedBy(isCandidate("cpx31", "fsn1"))).Return(nil, unavailable)
+		client.On("CreateServer", mock.Anything, mock.MatchedBy(isCandidate("cpx41", "fsn1"))).Return(&hcloud.Server{ID: 42}, nil)
+
+		server, err := service.createServerWithFallback(context.Background(), hcloud.ServerCreateOpts{}, candidates, images, nil)
+		Expect(err).To(Succeed())
+		Expect(server.ID).To(Equal(int64(42)))
+		client.AssertNumberOfCalls(GinkgoT(), "CreateServer", 2)
+	})
+
+	It("does not try other candidates on other errors", func() {
+		client.On("CreateServer", mock.Anything, mock.Anything).Return(nil, hcloud.Error{Code: hcloud.ErrorCodeConflict})
+
+		_, err := service.createServerWithFallback(context.Background(), hcloud.ServerCreateOpts{}, candidates, images, nil)
+		Expect(hcloud.IsError(err, hcloud.ErrorCodeConflict)).To(BeTrue())
+		client.AssertNumberOfCalls(GinkgoT(), "CreateServer", 1)
+	})
+
+	It("skips fallback server types without usable image", func() {
+		client.On("GetServerType", mock.Anything, "cpx41").Return(nil, nil)
+		client.On("CreateServer", mock.Anything, mock.MatchedBy(isCandidate("cpx31", "fsn1"))).Return(nil, unavailable)
+		client.On("CreateServer", mock.Anything, mock.MatchedBy(isCandidate("cpx31", "nbg1"))).Return(&hcloud.Server{ID: 42}, nil)
+
+		primaryImages := map[infrav1.HCloudMachineType]*hcloud.Image{"cpx31": {ID: 1}}
+		server, err := service.createServerWithFallback(context.Background(), hcloud.ServerCreateOpts{}, candidates, primaryImages, nil)
+		Expect(err).To(Succeed())
+		Expect(server.ID).To(Equal(int64(42)))
+		client.AssertNumberOfCalls(GinkgoT(), "GetServerType", 1)
+		client.AssertNumberOfCalls(GinkgoT(), "CreateServer", 2)
+	})
+
+	It("sets a condition if there is no capacity for any candidate", func() {
+		client.On("CreateServer", mock.Anything, mock.Anything).Return(nil, unavailable)
+
+		_, err := service.createServerWithFallback(context.Background(), hcloud.ServerCreateOpts{}, candidates, images, nil)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(isPresentAndFalseWithReason(hcloudMachine, infrav1.ServerCreateSucceededCondition, infrav1.ServerTypeUnavailableReason)).To(BeTrue())
+		client.AssertNumberOfCalls(GinkgoT(), "CreateServer", 3)
+	})
+})
+
+var _ = Describe("getServerImageBySelector", func() {
+	var (
+		hcloudMachine *infrav1.HCloudMachine
+		client        *mocks.Client
+		service       *Service
+	)
+
+	serverType := &hcloud.ServerType{Name: "cax21", Architecture: hcloud.ArchitectureARM}
+	now := time.Now()
+	images := []*hcloud.Image{
+		{ID: 1, Created: now.Add(-2 * time.Hour)},
+		{ID: 3, Created: now},
+		{ID: 2, Created: now.Add(-time.Hour)},
+	}
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "hcloud-machine", Namespace: "default"},
+			Spec: infrav1.HCloudMachineSpec{
+				ImageSelector: &infrav1.HCloudImageSelector{
+					LabelSelector: "os==ubuntu,k8s-version=={{ .KubernetesVersion }},arch=={{ .Architecture }}",
+					Policy:        infrav1.HCloudImageSelectionPolicyNewest,
+				},
+			},
+		}
+		client = &mocks.Client{}
+		service = newTestService(hcloudMachine, client)
+		service.scope.Machine = &clusterv1.Machine{Spec: clusterv1.MachineSpec{Version: ptr.To("v1.30.1")}}
+	})
+
+	It("uses the newest image matching the rendered label selector", func() {
+		client.On("ListImages", mock.Anything, hcloud.ImageListOpts{
+			ListOpts:     hcloud.ListOpts{LabelSelector: "os==ubuntu,k8s-version==v1.30.1,arch==arm"},
+			Architecture: []hcloud.Architecture{hcloud.ArchitectureARM},
+		}).Return(images, nil)
+
+		image, err := service.getServerImageBySelector(context.Background(), serverType)
+		Expect(err).To(Succeed())
+		Expect(image.ID).To(Equal(int64(3)))
+	})
+
+	It("sets a condition if several images match and a unique image is required", func() {
+		hcloudMachine.Spec.ImageSelector.Policy = infrav1.HCloudImageSelectionPolicyUnique
+		client.On("ListImages", mock.Anything, mock.Anything).Return(images, nil)
+
+		_, err := service.getServerImageBySelector(context.Background(), serverType)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(isPresentAndFalseWithReason(hcloudMachine, infrav1.ServerCreateSucceededCondition, infrav1.ImageAmbiguousReason)).To(BeTrue())
+	})
+
+	It("sets a condition if no image matches", func() {
+		client.On("ListImages", mock.Anything, mock.Anything).Return(nil, nil)
+
+		_, err := service.getServerImageBySelector(context.Background(), serverType)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(isPresentAndFalseWithReason(hcloudMachine, infrav1.ServerCreateSucceededCondition, infrav1.ImageNotFoundReason)).To(BeTrue())
+	})
+
+	It("sets a condition if the label selector cannot be rendered", func() {
+		hcloudMachine.Spec.ImageSelector.LabelSelector = "k8s-version=={{ .Version }}"
+
+		_, err := service.getServerImageBySelector(context.Background(), serverType)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(isPresentAndFalseWithReason(hcloudMachine, infrav1.ServerCreateSucceededCondition, infrav1.ImageSelectorInvalidReason)).To(BeTrue())
+		client.AssertNotCalled(GinkgoT(), "ListImages", mock.Anything, mock.Anything)
+	})
+})
+
+var _ = Describe("fitUserData", func() {
+	var (
+		hcloudMachine *infrav1.HCloudMachine
+		service       *Service
+	)
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "hcloud-machine", Namespace: "default"},
+		}
+		service = newTestService(hcloudMachine, nil)
+	})
+
+	It("compresses large cloud-config bootstrap data", func() {
+		userData := []byte("#cloud-config\nwrite_files:\n" + strings.Repeat("  - path: /etc/kubernetes/file\n    content: foo\n", 2000))
+
+		out, err := service.fitUserData(userData)
+		Expect(err).To(Succeed())
+		Expect(len(out)).To(BeNumerically("<", len(userData)))
+	})
+
+	It("sets a condition if bootstrap data is too large", func() {
+		userData := []byte(`{"ignition":{"version":"3.2.0"},"storage":{"files":[` + strings.Repeat(`{"path":"/etc/file"},`, 2000) + `]}}`)
+
+		_, err := service.fitUserData(userData)
+		Expect(err).To(MatchError(errServerCreateNotPossible))
+		Expect(isPresentAndFalseWithReason(hcloudMachine, infrav1.ServerCreateSucceededCondition, infrav1.BootstrapDataTooLargeReason)).To(BeTrue())
+	})
 })
 
 type testCaseStatusFromHCloudServer struct {
@@ -0,0 +1,347 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"gopkg.in/yaml.v3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	hcloudutil "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/util"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/utils"
+)
+
+const cloudConfigHeader = "#cloud-config"
+
+var errNoCloudConfig = errors.New("bootstrap data is not in cloud-config format")
+
+// reconcileVolumes makes sure that all volumes of the machine exist and are attached to the server.
+func (s *Service) reconcileVolumes(ctx context.Context, server *hcloud.Server) error {
+	volumeSpecs := s.scope.HCloudMachine.Spec.Volumes
+	if len(volumeSpecs) == 0 {
+		return nil
+	}
+
+	// if all volumes are already attached, then do nothing
+	if conditions.IsTrue(s.scope.HCloudMachine, infrav1.VolumesReadyCondition) &&
+		len(s.scope.HCloudMachine.Status.Volumes) == len(volumeSpecs) {
+		return nil
+	}
+
+	volumes, err := s.ensureVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	volumeStatuses := make([]infrav1.HCloudVolumeStatus, 0, len(volumes))
+	for i, volume := range volumes {
+		volumeSpec := volumeSpecs[i]
+
+		if volume.Server != nil && volume.Server.ID != server.ID {
+			err := fmt.Errorf("volume %s is attached to server with ID %d", volume.Name, volume.Server.ID)
+			conditions.MarkFalse(
+				s.scope.HCloudMachine,
+				infrav1.VolumesReadyCondition,
+				infrav1.VolumeAttachedToOtherServerReason,
+				clusterv1.ConditionSeverityError,
+				"%s",
+				err.Error(),
+			)
+			return err
+		}
+
+		if volume.Server == nil {
+			automount := volumeSpec.Automount
+			if err := s.scope.HCloudClient.AttachVolumeToServer(ctx, volume, hcloud.VolumeAttachOpts{
+				Server:    server,
+				Automount: &automount,
+			}); err != nil {
+				hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "AttachVolumeToServer")
+				conditions.MarkFalse(
+					s.scope.HCloudMachine,
+					infrav1.VolumesReadyCondition,
+					infrav1.VolumeAttachFailedReason,
+					clusterv1.ConditionSeverityWarning,
+					"%s",
+					err.Error(),
+				)
+				return fmt.Errorf("failed to attach volume %s to server: %w", volume.Name, err)
+			}
+			record.Eventf(s.scope.HCloudMachine, "AttachedVolume", "Attached volume %s with ID %d to server %s", volume.Name, volume.ID, server.Name)
+		}
+
+		volumeStatuses = append(volumeStatuses, infrav1.HCloudVolumeStatus{
+			Name:        volumeSpec.Name,
+			ID:          volume.ID,
+			LinuxDevice: volume.LinuxDevice,
+		})
+	}
+
+	s.scope.HCloudMachine.Status.Volumes = volumeStatuses
+	conditions.MarkTrue(s.scope.HCloudMachine, infrav1.VolumesReadyCondition)
+	return nil
+}
+
+// ensureVolumes returns the volumes of the machine in the order of the spec and creates the missing ones.
+func (s *Service) ensureVolumes(ctx context.Context) ([]*hcloud.Volume, error) {
+	volumes := make([]*hcloud.Volume, 0, len(s.scope.HCloudMachine.Spec.Volumes))
+
+	for _, volumeSpec := range s.scope.HCloudMachine.Spec.Volumes {
+		volume, err := s.findVolume(ctx, volumeSpec)
+		if err != nil {
+			return nil, err
+		}
+
+		if volume == nil {
+			volume, err = s.createVolume(ctx, volumeSpec)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	return volumes, nil
+}
+
+func (s *Service) createVolume(ctx context.Context, volumeSpec infrav1.HCloudVolumeSpec) (*hcloud.Volume, error) {
+	opts := hcloud.VolumeCreateOpts{
+		Name:   s.volumeName(volumeSpec),
+		Size:   volumeSpec.Size,
+		Labels: s.createVolumeLabels(volumeSpec),
+		Format: volumeSpec.Format,
+		Location: &hcloud.Location{
+			Name: string(s.scope.HCloudMachine.Status.Region),
+		},
+	}
+
+	volume, err := s.scope.HCloudClient.CreateVolume(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "CreateVolume")
+		conditions.MarkFalse(
+			s.scope.HCloudMachine,
+			infrav1.VolumesReadyCondition,
+			infrav1.VolumeCreateFailedReason,
+			clusterv1.ConditionSeverityWarning,
+			"%s",
+			err.Error(),
+		)
+		record.Warnf(s.scope.HCloudMachine, "FailedCreateHCloudVolume", "Failed to create HCloud volume %s: %s", opts.Name, err)
+		return nil, fmt.Errorf("failed to create volume %s: %w", opts.Name, err)
+	}
+
+	record.Eventf(s.scope.HCloudMachine, "SuccessfulCreateVolume", "Created new volume %s with ID %d", volume.Name, volume.ID)
+	return volume, nil
+}
+
+// deleteVolumes deletes all volumes of the machine that should be deleted together with it.
+// Volumes that are still attached are detached first.
+func (s *Service) deleteVolumes(ctx context.Context) (reconcile.Result, error) {
+	for _, volumeSpec := range s.scope.HCloudMachine.Spec.Volumes {
+		if !volumeSpec.DeleteOnMachineDelete {
+			continue
+		}
+
+		volume, err := s.findVolume(ctx, volumeSpec)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// volume has already been deleted
+		if volume == nil {
+			continue
+		}
+
+		// volumes are detached asynchronously when the server gets deleted
+		if volume.Server != nil {
+			if err := s.scope.HCloudClient.DetachVolume(ctx, volume); err != nil && !hcloud.IsError(err, hcloud.ErrorCodeLocked) {
+				hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "DetachVolume")
+				return reconcile.Result{}, fmt.Errorf("failed to detach volume %s: %w", volume.Name, err)
+			}
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+
+		if err := s.scope.HCloudClient.DeleteVolume(ctx, volume); err != nil {
+			if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+				continue
+			}
+			if hcloud.IsError(err, hcloud.ErrorCodeLocked) {
+				return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+			}
+			hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "DeleteVolume")
+			conditions.MarkFalse(
+				s.scope.HCloudMachine,
+				infrav1.VolumesReadyCondition,
+				infrav1.VolumeDeleteFailedReason,
+				clusterv1.ConditionSeverityWarning,
+				"%s",
+				err.Error(),
+			)
+			record.Warnf(s.scope.HCloudMachine, "FailedDeleteHCloudVolume", "Failed to delete HCloud volume %s: %s", volume.Name, err)
+			return reconcile.Result{}, fmt.Errorf("failed to delete volume %s: %w", volume.Name, err)
+		}
+
+		record.Eventf(s.scope.HCloudMachine, "HCloudVolumeDeleted", "HCloud volume %s deleted", volume.Name)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (s *Service) findVolume(ctx context.Context, volumeSpec infrav1.HCloudVolumeSpec) (*hcloud.Volume, error) {
+	opts := hcloud.VolumeListOpts{}
+	opts.LabelSelector = utils.LabelsToLabelSelector(s.createVolumeLabels(volumeSpec))
+
+	volumes, err := s.scope.HCloudClient.ListVolumes(ctx, opts)
+	if err != nil {
+		hcloudutil.HandleRateLimitExceeded(s.scope.HCloudMachine, err, "ListVolumes")
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+
+	if len(volumes) > 1 {
+		err := fmt.Errorf("found %d volumes with name %s", len(volumes), s.volumeName(volumeSpec))
+		record.Warn(s.scope.HCloudMachine, "MultipleVolumes", err.Error())
+		return nil, err
+	}
+
+	if len(volumes) == 0 {
+		return nil, nil
+	}
+
+	return volumes[0], nil
+}
+
+func (s *Service) volumeName(volumeSpec infrav1.HCloudVolumeSpec) string {
+	return fmt.Sprintf("%s-%s", s.scope.Name(), volumeSpec.Name)
+}
+
+func (s *Service) createVolumeLabels(volumeSpec infrav1.HCloudVolumeSpec) map[string]string {
+	labels := s.createLabels()
+	labels[infrav1.VolumeNameTagKey] = volumeSpec.Name
+	return labels
+}
+
+// volumeMounts returns the cloud-init mount entries of all volumes with a mount path.
+func volumeMounts(volumeSpecs []infrav1.HCloudVolumeSpec, volumes []*hcloud.Volume) [][]string {
+	var mounts [][]string
+	for i, volumeSpec := range volumeSpecs {
+		if volumeSpec.MountPath == nil || volumeSpec.Format == nil {
+			continue
+		}
+		mounts = append(mounts, []string{
+			fmt.Sprintf("/dev/disk/by-id/scsi-0HC_Volume_%d", volumes[i].ID),
+			*volumeSpec.MountPath,
+			*volumeSpec.Format,
+			"defaults,nofail,discard",
+			"0",
+			"2",
+		})
+	}
+	return mounts
+}
+
+// addVolumeMounts adds mount entries to cloud-init bootstrap data. Leading comments like
+// "## template: jinja" and "#cloud-config" are kept as they are.
+func addVolumeMounts(userData []byte, mounts [][]string) ([]byte, error) {
+	if len(mounts) == 0 {
+		return userData, nil
+	}
+
+	var header []string
+	var isCloudConfig bool
+	body := string(userData)
+	for body != "" {
+		line, rest, _ := strings.Cut(body, "\n")
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		if strings.TrimSpace(line) == cloudConfigHeader {
+			isCloudConfig = true
+		}
+		header = append(header, line)
+		body = rest
+	}
+	if !isCloudConfig {
+		return nil, errNoCloudConfig
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(body), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse cloud-config: %w", err)
+	}
+
+	// an empty document has no content yet
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("cloud-config is not a mapping: %w", errNoCloudConfig)
+	}
+
+	var mountsNode *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "mounts" {
+			mountsNode = root.Content[i+1]
+			break
+		}
+	}
+	if mountsNode == nil {
+		mountsNode = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "mounts"},
+			mountsNode,
+		)
+	}
+	if mountsNode.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("mounts of cloud-config is not a list: %w", errNoCloudConfig)
+	}
+
+	for _, mount := range mounts {
+		entry := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, field := range mount {
+			entry.Content = append(entry.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field, Style: yaml.DoubleQuotedStyle})
+		}
+		mountsNode.Content = append(mountsNode.Content, entry)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(strings.Join(header, "\n"))
+	buf.WriteString("\n")
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode cloud-config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode cloud-config: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
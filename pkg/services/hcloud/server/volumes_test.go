@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/syself/cluster-api-provider-hetzner/api/v1beta1"
+	"github.com/syself/cluster-api-provider-hetzner/pkg/scope"
+	fakeclient "github.com/syself/cluster-api-provider-hetzner/pkg/services/hcloud/client/fake"
+)
+
+var _ = Describe("addVolumeMounts", func() {
+	mounts := [][]string{{"/dev/disk/by-id/scsi-0HC_Volume_1", "/var/lib/etcd", "ext4", "defaults,nofail,discard", "0", "2"}}
+
+	It("adds mounts to cloud-config and keeps the header", func() {
+		userData := []byte("## template: jinja\n#cloud-config\nruncmd:\n  - echo '{{ ds.meta_data.local_hostname }}'\n")
+
+		out, err := addVolumeMounts(userData, mounts)
+		Expect(err).To(Succeed())
+		Expect(string(out)).To(Equal(`## template: jinja
+#cloud-config
+runcmd:
+  - echo '{{ ds.meta_data.local_hostname }}'
+mounts:
+  - ["/dev/disk/by-id/scsi-0HC_Volume_1", "/var/lib/etcd", "ext4", "defaults,nofail,discard", "0", "2"]
+`))
+	})
+
+	It("appends mounts to existing mounts", func() {
+		userData := []byte("#cloud-config\nmounts:\n  - [swap, none]\n")
+
+		out, err := addVolumeMounts(userData, mounts)
+		Expect(err).To(Succeed())
+		Expect(string(out)).To(Equal(`#cloud-config
+mounts:
+  - [swap, none]
+  - ["/dev/disk/by-id/scsi-0HC_Volume_1", "/var/lib/etcd", "ext4", "defaults,nofail,discard", "0", "2"]
+`))
+	})
+
+	It("does not change bootstrap data without mounts", func() {
+		userData := []byte(`{"ignition":{"version":"3.2.0"}}`)
+
+		out, err := addVolumeMounts(userData, nil)
+		Expect(err).To(Succeed())
+		Expect(out).To(Equal(userData))
+	})
+
+	It("gives an error if bootstrap data is not in cloud-config format", func() {
+		_, err := addVolumeMounts([]byte(`{"ignition":{"version":"3.2.0"}}`), mounts)
+		Expect(err).To(MatchError(errNoCloudConfig))
+	})
+})
+
+var _ = Describe("Volumes", func() {
+	var (
+		hcloudMachine *infrav1.HCloudMachine
+		service       *Service
+		server        *hcloud.Server
+	)
+	client := fakeclient.NewHCloudClientFactory().NewClient("")
+
+	BeforeEach(func() {
+		hcloudMachine = &infrav1.HCloudMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hcloud-machine-with-volumes",
+				Namespace: "default",
+			},
+			Spec: infrav1.HCloudMachineSpec{
+				ImageName: "fedora-control-plane",
+				Type:      "cpx31",
+				Volumes: []infrav1.HCloudVolumeSpec{
+					{
+						Name:                  "etcd",
+						Size:                  10,
+						Format:                ptr.To("ext4"),
+						MountPath:             ptr.To("/var/lib/etcd"),
+						DeleteOnMachineDelete: true,
+					},
+					{
+						Name:      "data",
+						Size:      20,
+						Automount: true,
+					},
+				},
+			},
+			Status: infrav1.HCloudMachineStatus{
+				Region: "fsn1",
+			},
+		}
+
+		hetznerCluster := &infrav1.HetznerCluster{}
+		hetznerCluster.Name = "hetzner-cluster"
+
+		service = &Service{
+			scope: &scope.MachineScope{
+				HCloudMachine: hcloudMachine,
+				Machine:       &clusterv1.Machine{},
+				ClusterScope: scope.ClusterScope{
+					HetznerCluster: hetznerCluster,
+					HCloudClient:   client,
+				},
+			},
+		}
+
+		var err error
+		server, err = client.CreateServer(context.Background(), hcloud.ServerCreateOpts{Name: "server-with-volumes"})
+		Expect(err).To(Succeed())
+	})
+
+	AfterEach(func() {
+		volumes, err := client.ListVolumes(context.Background(), hcloud.VolumeListOpts{})
+		Expect(err).To(Succeed())
+		for _, volume := range volumes {
+			Expect(client.DetachVolume(context.Background(), volume)).To(Succeed())
+			Expect(client.DeleteVolume(context.Background(), volume)).To(Succeed())
+		}
+		Expect(client.DeleteServer(context.Background(), server)).To(Succeed())
+	})
+
+	It("creates and attaches all volumes", func() {
+		Expect(service.reconcileVolumes(context.Background(), server)).To(Succeed())
+
+		Expect(conditions.IsTrue(hcloudMachine, infrav1.VolumesReadyCondition)).To(BeTrue())
+		Expect(hcloudMachine.Status.Volumes).To(HaveLen(2))
+		Expect(hcloudMachine.Status.Volumes[0].Name).To(Equal("etcd"))
+		Expect(hcloudMachine.Status.Volumes[1].Name).To(Equal("data"))
+
+		volumes, err := client.ListVolumes(context.Background(), hcloud.VolumeListOpts{Name: "hcloud-machine-with-volumes-etcd"})
+		Expect(err).To(Succeed())
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Size).To(Equal(10))
+		Expect(volumes[0].Server.ID).To(Equal(server.ID))
+		Expect(volumes[0].Labels).To(HaveKeyWithValue(infrav1.VolumeNameTagKey, "etcd"))
+	})
+
+	It("sets a condition if a volume is attached to another server", func() {
+		otherServer, err := client.CreateServer(context.Background(), hcloud.ServerCreateOpts{Name: "other-server"})
+		Expect(err).To(Succeed())
+		defer func() {
+			Expect(client.DeleteServer(context.Background(), otherServer)).To(Succeed())
+		}()
+
+		volumes, err := service.ensureVolumes(context.Background())
+		Expect(err).To(Succeed())
+		Expect(client.AttachVolumeToServer(context.Background(), volumes[0], hcloud.VolumeAttachOpts{Server: otherServer})).To(Succeed())
+
+		Expect(service.reconcileVolumes(context.Background(), server)).ToNot(Succeed())
+		Expect(conditions.GetReason(hcloudMachine, infrav1.VolumesReadyCondition)).To(Equal(infrav1.VolumeAttachedToOtherServerReason))
+	})
+
+	It("detaches and deletes volumes that are deleted together with the machine", func() {
+		Expect(service.reconcileVolumes(context.Background(), server)).To(Succeed())
+
+		// the attached volume gets detached first
+		res, err := service.deleteVolumes(context.Background())
+		Expect(err).To(Succeed())
+		Expect(res.RequeueAfter).ToNot(BeZero())
+
+		res, err = service.deleteVolumes(context.Background())
+		Expect(err).To(Succeed())
+		Expect(res).To(Equal(reconcile.Result{}))
+
+		volumes, err := client.ListVolumes(context.Background(), hcloud.VolumeListOpts{})
+		Expect(err).To(Succeed())
+		Expect(volumes).To(HaveLen(1))
+		Expect(volumes[0].Name).To(Equal("hcloud-machine-with-volumes-data"))
+	})
+})
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package userdata prepares bootstrap data so that it can be passed to servers as user data.
+package userdata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// MaxHCloudSize is the maximum size of user data that Hetzner's Cloud API accepts.
+const MaxHCloudSize = 32 * 1024
+
+var (
+	// ErrTooLarge indicates that the user data exceeds the maximum size even after compression.
+	ErrTooLarge = errors.New("user data too large")
+	// ErrNotCompressible indicates that the user data exceeds the maximum size and cannot be compressed.
+	ErrNotCompressible = errors.New("user data exceeds maximum size and cannot be compressed")
+)
+
+// IsCompressible returns whether the user data can be compressed. cloud-init detects and decompresses
+// gzip compressed user data, whereas Ignition configs are JSON and cannot be compressed as a whole.
+func IsCompressible(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] != '{'
+}
+
+// Compress compresses the user data with gzip and encodes it with base64.
+func Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := base64.NewEncoder(base64.StdEncoding, &buf)
+
+	writer, err := gzip.NewWriterLevel(encoder, gzip.BestCompression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip writer: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress user data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close base64 encoder: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Fit returns user data that does not exceed maxSize. User data of cloud-init that is too large is compressed
+// with gzip and encoded with base64. The datasource of Hetzner in cloud-init decodes and decompresses it transparently.
+// The returned bool reports whether the user data has been compressed.
+func Fit(data []byte, maxSize int) ([]byte, bool, error) {
+	if len(data) <= maxSize {
+		return data, false, nil
+	}
+
+	if !IsCompressible(data) {
+		return nil, false, fmt.Errorf("%w: %d bytes, maximum is %d bytes", ErrNotCompressible, len(data), maxSize)
+	}
+
+	compressed, err := Compress(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(compressed) > maxSize {
+		return nil, false, fmt.Errorf("%w: %d bytes after compression (%d bytes before), maximum is %d bytes",
+			ErrTooLarge, len(compressed), len(data), maxSize)
+	}
+
+	return compressed, true, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decompress(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	require.NoError(t, err)
+
+	reader, err := gzip.NewReader(bytes.NewReader(decoded))
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return out
+}
+
+func TestFit(t *testing.T) {
+	largeCloudConfig := []byte("#cloud-config\nwrite_files:\n" + strings.Repeat("  - path: /etc/kubernetes/audit-policy.yaml\n    content: |\n      apiVersion: audit.k8s.io/v1\n", 1000))
+
+	randomData := make([]byte, 2*MaxHCloudSize)
+	_, err := rand.Read(randomData)
+	require.NoError(t, err)
+	incompressible := []byte("#cloud-config\n" + base64.StdEncoding.EncodeToString(randomData))
+
+	tests := []struct {
+		name           string
+		data           []byte
+		wantCompressed bool
+		wantErr        error
+	}{
+		{
+			name: "small user data is not changed",
+			data: []byte("#cloud-config\nruncmd: []\n"),
+		},
+		{
+			name:           "large cloud-config is compressed",
+			data:           largeCloudConfig,
+			wantCompressed: true,
+		},
+		{
+			name:    "large ignition config cannot be compressed",
+			data:    []byte(`{"ignition":{"version":"3.2.0"},"storage":{"files":[` + strings.Repeat(`{"path":"/etc/file"},`, 2000) + `]}}`),
+			wantErr: ErrNotCompressible,
+		},
+		{
+			name:    "compressed user data is still too large",
+			data:    incompressible,
+			wantErr: ErrTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, compressed, err := Fit(tt.data, MaxHCloudSize)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCompressed, compressed)
+			require.LessOrEqual(t, len(out), MaxHCloudSize)
+
+			if compressed {
+				require.Equal(t, tt.data, decompress(t, out))
+			} else {
+				require.Equal(t, tt.data, out)
+			}
+		})
+	}
+}